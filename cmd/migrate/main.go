@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/yukikurage/task-management-api/internal/config"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/database/migrations"
+)
+
+const usage = `Usage: migrate <command>
+
+Commands:
+  up        Apply all pending migrations
+  down      Roll back the most recent migration
+  status    Show applied and pending migrations
+  to N      Migrate up or down to version N (0 rolls back everything)`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	runner := migrations.NewRunner(database.GetDB(), migrations.All())
+
+	var err error
+	switch os.Args[1] {
+	case "up":
+		err = runner.Up()
+	case "down":
+		err = runner.Down()
+	case "status":
+		err = printStatus(runner)
+	case "to":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+		version, parseErr := strconv.Atoi(os.Args[2])
+		if parseErr != nil || version < 0 {
+			log.Fatalf("Invalid version %q", os.Args[2])
+		}
+		err = runner.To(version)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+func printStatus(runner *migrations.Runner) error {
+	statuses, err := runner.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d  %-30s %s\n", s.Version, s.Name, state)
+	}
+	return nil
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/redis"
+	"github.com/gin-gonic/gin"
+	redigo "github.com/gomodule/redigo/redis"
+	"github.com/yukikurage/task-management-api/internal/config"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/handlers"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/notifier"
+	"github.com/yukikurage/task-management-api/internal/policy"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+func main() {
+	cfg := config.Load()
+	gin.SetMode(cfg.GinMode)
+
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.Migrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	store, err := redis.NewStore(
+		constants.SessionStoreMaxIdleConnections,
+		"tcp",
+		net.JoinHostPort(cfg.RedisHost, cfg.RedisPort),
+		"",
+		"",
+		[]byte(cfg.SessionSecret),
+	)
+	if err != nil {
+		log.Fatalf("Failed to connect to session store: %v", err)
+	}
+	store.Options(sessions.Options{
+		Path:     "/",
+		MaxAge:   constants.SessionMaxAge,
+		HttpOnly: true,
+		Secure:   cfg.GinMode == gin.ReleaseMode,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	loginAttempts, closeLoginAttempts, err := newLoginAttemptStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up login attempt store: %v", err)
+	}
+	defer closeLoginAttempts()
+
+	accountNotifier, err := newNotifier(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up notifier: %v", err)
+	}
+
+	router := setupRouter(cfg, store, loginAttempts, accountNotifier)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           router,
+		ReadHeaderTimeout: constants.ServerReadHeaderTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	retentionService := services.NewRetentionService(repository.NewRetentionRepository(database.GetDB()), cfg.TrashRetention)
+	go runTrashPurge(ctx, retentionService)
+
+	go func() {
+		log.Printf("Server listening on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), constants.ServerShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	if sqlDB, err := database.GetDB().DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("Failed to close database connection: %v", err)
+		}
+	}
+
+	log.Println("Server stopped")
+}
+
+// runTrashPurge purges deleted data past its retention period at startup and
+// then every TrashPurgeInterval until ctx is canceled.
+func runTrashPurge(ctx context.Context, retentionService *services.RetentionService) {
+	if !retentionService.Enabled() {
+		log.Println("Trash purge disabled; deleted data is kept forever")
+		return
+	}
+
+	ticker := time.NewTicker(constants.TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := retentionService.PurgeExpired(time.Now())
+		if err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if result.Organizations+result.Tasks+result.TaskAssignments > 0 {
+			log.Printf("Trash purge removed %d organizations, %d tasks and %d task assignments",
+				result.Organizations, result.Tasks, result.TaskAssignments)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newLoginAttemptStore creates the brute-force protection backend selected by
+// LOGIN_ATTEMPT_BACKEND. The returned function releases its resources.
+func newLoginAttemptStore(cfg *config.Config) (repository.LoginAttemptStore, func(), error) {
+	switch cfg.LoginAttemptBackend {
+	case "memory":
+		return repository.NewMemoryLoginAttemptStore(), func() {}, nil
+	case "redis":
+		addr := net.JoinHostPort(cfg.RedisHost, cfg.RedisPort)
+		pool := &redigo.Pool{
+			MaxIdle:     constants.SessionStoreMaxIdleConnections,
+			IdleTimeout: constants.RedisIdleTimeout,
+			Dial: func() (redigo.Conn, error) {
+				return redigo.Dial("tcp", addr)
+			},
+		}
+		closePool := func() {
+			if err := pool.Close(); err != nil {
+				log.Printf("Failed to close Redis pool: %v", err)
+			}
+		}
+		return repository.NewRedisLoginAttemptStore(pool, constants.LoginAttemptKeyPrefix), closePool, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported login attempt backend %q", cfg.LoginAttemptBackend)
+	}
+}
+
+// newNotifier creates the account notification sink selected by NOTIFIER.
+func newNotifier(cfg *config.Config) (notifier.Notifier, error) {
+	switch cfg.Notifier {
+	case "log":
+		return notifier.NewLogNotifier(cfg.PasswordResetURL), nil
+	case "file":
+		return notifier.NewFileNotifier(cfg.NotifierFilePath, cfg.PasswordResetURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier %q", cfg.Notifier)
+	}
+}
+
+// setupRouter constructs the dependency graph and registers all API routes.
+func setupRouter(
+	cfg *config.Config,
+	store sessions.Store,
+	loginAttempts repository.LoginAttemptStore,
+	accountNotifier notifier.Notifier,
+) *gin.Engine {
+	db := database.GetDB()
+
+	// Repositories
+	userRepo := repository.NewUserRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	inviteRepo := repository.NewInviteRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	taskRepo := repository.NewTaskRepository(db)
+	accessTokenRepo := repository.NewAccessTokenRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+
+	// Services
+	var aiService *services.AIService
+	if cfg.OpenAIAPIKey != "" {
+		aiService = services.NewAIService(cfg.OpenAIAPIKey)
+	}
+	auditService := services.NewAuditService(auditLogRepo)
+	orgService := services.NewOrganizationService(orgRepo, inviteRepo, auditService)
+	invitationService := services.NewInvitationService(invitationRepo, orgRepo, userRepo, auditService)
+	taskService := services.NewTaskService(taskRepo, orgRepo, aiService, auditService)
+	accessTokenService := services.NewAccessTokenService(accessTokenRepo)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo)
+	sessionService := services.NewSessionService(sessionRepo)
+	authService := services.NewAuthService(
+		userRepo,
+		passwordResetRepo,
+		services.NewLoginThrottle(loginAttempts),
+		sessionService,
+		accountNotifier,
+	)
+	accountService := services.NewAccountService(
+		authService,
+		userRepo,
+		orgRepo,
+		taskRepo,
+		sessionService,
+		accessTokenService,
+		twoFactorService,
+	)
+	oidcService := services.NewOIDCService(services.OIDCConfig{
+		IssuerURL:    cfg.OIDCIssuerURL,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+	}, userRepo)
+
+	// Handlers
+	authHandler := handlers.NewAuthHandler(authService, twoFactorService, sessionService)
+	orgHandler := handlers.NewOrganizationHandler(orgService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	taskHandler := handlers.NewTaskHandler(taskService)
+	accessTokenHandler := handlers.NewAccessTokenHandler(accessTokenService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService, twoFactorService, sessionService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService, authService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditService)
+
+	r := gin.New()
+	// The client IP feeds the login throttle, sessions and the audit log, so
+	// X-Forwarded-For is only honored from the configured proxies
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.Use(gin.Logger(), gin.Recovery())
+	r.Use(sessions.Sessions(constants.SessionCookieName, store))
+
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "ok",
+			"message": "Task Management API is running",
+		})
+	})
+
+	api := r.Group("/api")
+
+	auth := api.Group("/auth")
+	{
+		auth.POST("/signup", authHandler.Signup)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/login/2fa", authHandler.VerifyTwoFactorLogin)
+		auth.POST("/logout", authHandler.Logout)
+		auth.POST("/password", middleware.RequireAuth(), middleware.RequireSessionAuth(), authHandler.ChangePassword)
+		auth.POST("/password/forgot", authHandler.ForgotPassword)
+		auth.POST("/password/reset", authHandler.ResetPassword)
+		auth.GET("/me", middleware.RequireAuth(), authHandler.GetCurrentUser)
+		auth.DELETE("/me", middleware.RequireAuth(), middleware.RequireSessionAuth(), accountHandler.DeleteAccount)
+		auth.GET("/me/export", middleware.RequireAuth(), middleware.RequireSessionAuth(), accountHandler.ExportData)
+		auth.GET("/oidc/login", oidcHandler.Login)
+		auth.GET("/oidc/callback", oidcHandler.Callback)
+
+		tokens := auth.Group("/tokens", middleware.RequireAuth(), middleware.RequireSessionAuth())
+		{
+			tokens.GET("", accessTokenHandler.ListTokens)
+			tokens.POST("", accessTokenHandler.CreateToken)
+			tokens.DELETE("/:token_id", accessTokenHandler.RevokeToken)
+		}
+
+		userSessions := auth.Group("/sessions", middleware.RequireAuth(), middleware.RequireSessionAuth())
+		{
+			userSessions.GET("", sessionHandler.ListSessions)
+			userSessions.POST("/revoke-others", sessionHandler.RevokeOtherSessions)
+			userSessions.DELETE("/:session_id", sessionHandler.RevokeSession)
+		}
+
+		twoFactor := auth.Group("/2fa", middleware.RequireAuth(), middleware.RequireSessionAuth())
+		{
+			twoFactor.GET("", twoFactorHandler.GetStatus)
+			twoFactor.POST("/enroll", twoFactorHandler.Enroll)
+			twoFactor.POST("/activate", twoFactorHandler.Activate)
+			twoFactor.POST("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			twoFactor.POST("/disable", twoFactorHandler.Disable)
+		}
+	}
+
+	orgs := api.Group("/organizations", middleware.RequireAuth())
+	{
+		orgs.GET("", orgHandler.ListOrganizations)
+		orgs.POST("", orgHandler.CreateOrganization)
+		orgs.POST("/join", orgHandler.JoinOrganization)
+
+		org := orgs.Group("/:id", middleware.RequireOrganizationAccess())
+		{
+			org.GET("", orgHandler.GetOrganization)
+			org.DELETE("", middleware.RequireOrganizationPermission(policy.ActionOrganizationDelete), orgHandler.DeleteOrganization)
+			org.POST("/archive", middleware.RequireOrganizationPermission(policy.ActionOrganizationArchive), orgHandler.ArchiveOrganization)
+			org.POST("/restore", middleware.RequireOrganizationPermission(policy.ActionOrganizationArchive), orgHandler.RestoreOrganization)
+			org.GET("/members", orgHandler.ListMembers)
+			org.GET("/invites", middleware.RequireOrganizationPermission(policy.ActionManageInvites), orgHandler.ListInvites)
+			org.GET("/invites/:invite_id", middleware.RequireOrganizationPermission(policy.ActionManageInvites), orgHandler.GetInvite)
+			org.GET("/invitations", middleware.RequireOrganizationPermission(policy.ActionManageInvites), invitationHandler.ListOrganizationInvitations)
+			org.GET("/policy", orgHandler.GetPolicy)
+			org.GET("/settings", orgHandler.GetSettings)
+			org.GET("/workflow", orgHandler.GetWorkflow)
+			org.GET("/labels", orgHandler.ListLabels)
+			org.GET("/trash", taskHandler.ListTrash)
+			org.GET("/tasks/order", taskHandler.ListTaskOrder)
+			org.GET("/audit-log", middleware.RequireOrganizationPermission(policy.ActionViewAuditLog), auditLogHandler.ListAuditLog)
+
+			// Archived organizations are read-only
+			active := org.Group("", middleware.RequireActiveOrganization())
+			{
+				active.PUT("", middleware.RequireOrganizationPermission(policy.ActionOrganizationUpdate), orgHandler.UpdateOrganization)
+				active.POST("/regenerate-code", middleware.RequireOrganizationPermission(policy.ActionManageInvites), orgHandler.RegenerateInviteCode)
+				active.DELETE("/members/:user_id", middleware.RequireOrganizationPermission(policy.ActionRemoveMembers), orgHandler.RemoveMember)
+				active.PUT("/members/:user_id/role", middleware.RequireOrganizationPermission(policy.ActionChangeRoles), orgHandler.ChangeMemberRole)
+				active.POST("/transfer-ownership", middleware.RequireOrganizationPermission(policy.ActionTransferOwnership), orgHandler.TransferOwnership)
+				active.POST("/leave", orgHandler.LeaveOrganization)
+				// Who may invite depends on the organization's settings and is checked by the service
+				active.POST("/invites", orgHandler.CreateInvite)
+				active.DELETE("/invites/:invite_id", middleware.RequireOrganizationPermission(policy.ActionManageInvites), orgHandler.RevokeInvite)
+				active.POST("/invitations", invitationHandler.CreateInvitation)
+				active.DELETE("/invitations/:invitation_id", middleware.RequireOrganizationPermission(policy.ActionManageInvites), invitationHandler.CancelInvitation)
+				active.PUT("/policy", middleware.RequireOrganizationPermission(policy.ActionManagePolicy), orgHandler.UpdatePolicy)
+				active.PUT("/settings", middleware.RequireOrganizationPermission(policy.ActionManageSettings), orgHandler.UpdateSettings)
+				active.PUT("/workflow", middleware.RequireOrganizationPermission(policy.ActionManageWorkflow), orgHandler.UpdateWorkflow)
+				active.POST("/labels", middleware.RequireOrganizationPermission(policy.ActionManageLabels), orgHandler.CreateLabel)
+				active.PUT("/labels/:label_id", middleware.RequireOrganizationPermission(policy.ActionManageLabels), orgHandler.UpdateLabel)
+				active.DELETE("/labels/:label_id", middleware.RequireOrganizationPermission(policy.ActionManageLabels), orgHandler.DeleteLabel)
+				active.POST("/trash/:task_id/restore", taskHandler.RestoreTask)
+			}
+		}
+	}
+
+	invitations := api.Group("/invitations", middleware.RequireAuth())
+	{
+		invitations.GET("", invitationHandler.ListInvitations)
+		invitations.POST("/:invitation_id/accept", invitationHandler.AcceptInvitation)
+		invitations.POST("/:invitation_id/decline", invitationHandler.DeclineInvitation)
+	}
+
+	tasks := api.Group("/tasks", middleware.RequireAuth())
+	{
+		tasks.GET("", taskHandler.ListTasks)
+		tasks.POST("", taskHandler.CreateTask)
+		tasks.POST("/generate", taskHandler.GenerateTasks)
+
+		task := tasks.Group("/:id", middleware.RequireTaskAccess())
+		{
+			task.GET("", taskHandler.GetTask)
+			task.GET("/subtasks", taskHandler.ListSubtasks)
+			task.PATCH("", taskHandler.UpdateTask)
+			task.DELETE("", taskHandler.DeleteTask)
+			task.POST("/assign", taskHandler.AssignTask)
+			task.POST("/unassign", taskHandler.UnassignTask)
+			task.POST("/toggle-status", taskHandler.ToggleTaskStatus)
+			task.POST("/transition", taskHandler.TransitionTask)
+			task.POST("/labels", taskHandler.AddTaskLabels)
+			task.DELETE("/labels/:label_id", taskHandler.RemoveTaskLabel)
+			task.GET("/dependencies", taskHandler.ListTaskDependencies)
+			task.POST("/dependencies", taskHandler.AddTaskDependency)
+			task.DELETE("/dependencies/:blocker_id", taskHandler.RemoveTaskDependency)
+		}
+	}
+
+	return r
+}
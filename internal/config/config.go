@@ -2,36 +2,90 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
+	DBDriver      string
 	DBHost        string
 	DBPort        string
 	DBUser        string
 	DBPassword    string
 	DBName        string
+	DBSSLMode     string
 	RedisHost     string
 	RedisPort     string
 	SessionSecret string
 	GinMode       string
 	OpenAIAPIKey  string
+	Port          string
+
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For is
+	// used for the client IP; when empty the header is ignored
+	TrustedProxies []string
+
+	// LoginAttemptBackend selects where failed logins are tracked: "redis" or "memory"
+	LoginAttemptBackend string
+
+	// Notifier selects how account notifications are delivered: "log" or "file"
+	Notifier         string
+	NotifierFilePath string
+	PasswordResetURL string
+
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+
+	// TrashRetention is how long deleted data stays restorable before it is purged; zero keeps it forever
+	TrashRetention time.Duration
 }
 
 func Load() *Config {
+	dbDriver := getEnv("DB_DRIVER", "mysql")
+
 	return &Config{
+		DBDriver:      dbDriver,
 		DBHost:        getEnv("DB_HOST", "localhost"),
-		DBPort:        getEnv("DB_PORT", "3306"),
+		DBPort:        getEnv("DB_PORT", defaultDBPort(dbDriver)),
 		DBUser:        getEnv("DB_USER", "taskuser"),
 		DBPassword:    getEnv("DB_PASSWORD", "taskpassword"),
 		DBName:        getEnv("DB_NAME", "task_management"),
+		DBSSLMode:     getEnv("DB_SSLMODE", "disable"),
 		RedisHost:     getEnv("REDIS_HOST", "localhost"),
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		SessionSecret: getEnv("SESSION_SECRET", "default-secret-key-change-me"),
 		GinMode:       getEnv("GIN_MODE", "debug"),
 		OpenAIAPIKey:  getEnv("OPENAI_API_KEY", ""),
+		Port:          getEnv("PORT", "8080"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		LoginAttemptBackend: getEnv("LOGIN_ATTEMPT_BACKEND", "redis"),
+
+		Notifier:         getEnv("NOTIFIER", "log"),
+		NotifierFilePath: getEnv("NOTIFIER_FILE_PATH", "notifications.log"),
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+
+		OIDCIssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/auth/oidc/callback"),
+
+		TrashRetention: time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
 	}
 }
 
+// defaultDBPort returns the conventional port for the given database driver
+func defaultDBPort(driver string) string {
+	if driver == "postgres" {
+		return "5432"
+	}
+	return "3306"
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -39,3 +93,25 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvList reads a comma separated list, returning nil when the variable is
+// unset or empty
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvInt reads a non-negative integer, falling back to the default when the
+// variable is unset or invalid
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -9,6 +9,180 @@ const (
 
 	// SessionCookieName is the name of the session cookie
 	SessionCookieName = "session"
+
+	// SessionKeySessionToken is the session key holding the token that identifies
+	// the session in the server-side registry
+	SessionKeySessionToken = "session_token"
+
+	// SessionTokenByteLength is the number of random bytes in a session registry token
+	SessionTokenByteLength = 32
+
+	// SessionLastSeenUpdateInterval throttles writes of a session's last-seen timestamp
+	SessionLastSeenUpdateInterval = time.Minute
+
+	// SessionKeyOIDCState is the session key holding the pending OIDC state parameter
+	SessionKeyOIDCState = "oidc_state"
+
+	// SessionKeyOIDCNonce is the session key holding the pending OIDC nonce
+	SessionKeyOIDCNonce = "oidc_nonce"
+
+	// SessionKeyOIDCVerifier is the session key holding the pending PKCE code verifier
+	SessionKeyOIDCVerifier = "oidc_verifier"
+
+	// SessionKeyTwoFactorUserID is the session key holding a user who passed the
+	// password check but still has to provide a second factor
+	SessionKeyTwoFactorUserID = "two_factor_user_id"
+
+	// SessionKeyTwoFactorStartedAt is the session key holding when the pending login began (unix seconds)
+	SessionKeyTwoFactorStartedAt = "two_factor_started_at"
+
+	// SessionKeyTwoFactorAttempts is the session key counting failed second-factor attempts
+	SessionKeyTwoFactorAttempts = "two_factor_attempts"
+)
+
+// Two-factor authentication constants
+const (
+	// TOTPIssuer is the issuer name shown in authenticator apps
+	TOTPIssuer = "Task Management API"
+
+	// TOTPSecretByteLength is the size of generated TOTP secrets (160 bits as recommended by RFC 4226)
+	TOTPSecretByteLength = 20
+
+	// TOTPDigits is the number of digits in a TOTP code
+	TOTPDigits = 6
+
+	// TOTPPeriod is the TOTP time step
+	TOTPPeriod = 30 * time.Second
+
+	// TOTPAllowedSkew is the number of time steps accepted before and after the current one
+	TOTPAllowedSkew = 1
+
+	// RecoveryCodeCount is the number of recovery codes issued at a time
+	RecoveryCodeCount = 10
+
+	// RecoveryCodeByteLength is the number of random bytes in a recovery code
+	RecoveryCodeByteLength = 10
+
+	// TwoFactorLoginTimeout is how long a half-authenticated login waits for the second factor
+	TwoFactorLoginTimeout = 5 * time.Minute
+
+	// TwoFactorMaxAttempts is the number of wrong codes allowed before the pending login is discarded
+	TwoFactorMaxAttempts = 5
+)
+
+// OpenID Connect constants
+const (
+	// OIDCRandomByteLength is the number of random bytes in generated state and nonce values
+	OIDCRandomByteLength = 32
+
+	// OIDCProviderTimeout is the timeout for requests to the identity provider
+	OIDCProviderTimeout = 10 * time.Second
+
+	// OIDCUsernameMaxAttempts is how many suffixed usernames are tried when provisioning a user
+	OIDCUsernameMaxAttempts = 5
+)
+
+// Personal access token constants
+const (
+	// AccessTokenPrefix is prepended to every personal access token to make it recognizable
+	AccessTokenPrefix = "tma_"
+
+	// AccessTokenByteLength is the number of random bytes in a personal access token
+	AccessTokenByteLength = 32
+
+	// AccessTokenDisplayPrefixLength is the number of leading characters kept for display
+	AccessTokenDisplayPrefixLength = 12
+
+	// AccessTokenMaxLifetimeDays is the maximum lifetime of a personal access token
+	AccessTokenMaxLifetimeDays = 365
+
+	// AccessTokenLastUsedUpdateInterval throttles writes of the last-used timestamp
+	AccessTokenLastUsedUpdateInterval = time.Minute
+)
+
+// Password reset constants
+const (
+	// PasswordResetTokenByteLength is the number of random bytes in a password reset token
+	PasswordResetTokenByteLength = 32
+
+	// PasswordResetTokenTTL is how long a password reset token stays valid
+	PasswordResetTokenTTL = 30 * time.Minute
+)
+
+// Organization invite constants
+const (
+	// InviteCodePrefix is prepended to invite link codes to tell them apart from legacy organization codes
+	InviteCodePrefix = "inv_"
+
+	// InviteCodeByteLength is the number of random bytes in an invite link code
+	InviteCodeByteLength = 16
+
+	// InviteMaxLifetimeHours is the maximum lifetime of an invite link
+	InviteMaxLifetimeHours = 30 * 24
+
+	// InviteMaxUses is the largest use limit an invite link can have
+	InviteMaxUses = 1000
+
+	// InvitationTTL is how long a targeted invitation waits for an answer
+	InvitationTTL = 14 * 24 * time.Hour
+)
+
+// Workflow constants
+const (
+	// WorkflowMaxStates is the largest number of states a task workflow can have
+	WorkflowMaxStates = 20
+
+	// WorkflowStateNameMaxLength is the longest a workflow state name can be
+	WorkflowStateNameMaxLength = 50
+)
+
+// Task hierarchy constants
+const (
+	// TaskMaxDepth is how many levels a task hierarchy can have, counting the
+	// top-level task
+	TaskMaxDepth = 3
+)
+
+// Label constants
+const (
+	// LabelNameMaxLength is the longest a label name can be
+	LabelNameMaxLength = 50
+
+	// MaxLabelsPerOrganization is the largest number of labels an organization can have
+	MaxLabelsPerOrganization = 100
+)
+
+// Trash constants
+const (
+	// TrashPurgeInterval is how often deleted data past its retention period is purged
+	TrashPurgeInterval = time.Hour
+)
+
+// Account deletion constants
+const (
+	// DeletedUsernamePrefix is the reserved username prefix given to anonymized, deleted users
+	DeletedUsernamePrefix = "deleted-user-"
+)
+
+// Login brute-force protection constants
+const (
+	// LoginMaxFailuresPerUsername is the number of failed logins for a username before it is locked
+	LoginMaxFailuresPerUsername = 5
+
+	// LoginMaxFailuresPerIP is the number of failed logins from an IP address before it is locked
+	LoginMaxFailuresPerIP = 20
+
+	// LoginFailureWindow is how long failed attempts are remembered after the most recent one
+	LoginFailureWindow = 15 * time.Minute
+
+	// LoginLockoutBaseDuration is the first lockout; each further failure doubles it
+	LoginLockoutBaseDuration = 30 * time.Second
+
+	// LoginLockoutMaxDuration caps the exponential lockout
+	LoginLockoutMaxDuration = time.Hour
+
+	// LoginAttemptKeyPrefix namespaces login attempt keys in the shared store
+	LoginAttemptKeyPrefix = "login_attempts:"
 )
 
 // Pagination constants
@@ -28,8 +202,8 @@ const (
 	// MinPasswordLength is the minimum password length
 	MinPasswordLength = 8
 
-	// MaxPasswordLength is the maximum password length
-	MaxPasswordLength = 128
+	// MaxPasswordLength is the maximum password length in bytes (bcrypt only uses the first 72 bytes)
+	MaxPasswordLength = 72
 
 	// MinNameLength is the minimum name length
 	MinNameLength = 1
@@ -64,6 +238,12 @@ const (
 	// ContextKeyUserID is the key for user ID in context
 	ContextKeyUserID = "user_id"
 
+	// ContextKeyAccessToken is the key for the personal access token used to authenticate
+	ContextKeyAccessToken = "access_token"
+
+	// ContextKeySessionID is the key for the registry ID of the current login session
+	ContextKeySessionID = "session_id"
+
 	// ContextKeyTask is the key for task in context
 	ContextKeyTask = "task"
 
@@ -73,3 +253,18 @@ const (
 	// ContextKeyOrganizationMember is the key for organization member in context
 	ContextKeyOrganizationMember = "organization_member"
 )
+
+// Server constants
+const (
+	// ServerReadHeaderTimeout is the maximum time allowed to read request headers
+	ServerReadHeaderTimeout = 10 * time.Second
+
+	// ServerShutdownTimeout is the maximum time to wait for in-flight requests on shutdown
+	ServerShutdownTimeout = 15 * time.Second
+
+	// SessionStoreMaxIdleConnections is the maximum number of idle Redis connections for the session store
+	SessionStoreMaxIdleConnections = 10
+
+	// RedisIdleTimeout closes pooled Redis connections that stay idle this long
+	RedisIdleTimeout = 4 * time.Minute
+)
@@ -5,45 +5,81 @@ import (
 	"log"
 
 	"github.com/yukikurage/task-management-api/internal/config"
-	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/database/migrations"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// Supported values for config.Config.DBDriver
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 var DB *gorm.DB
 
 func Connect(cfg *config.Config) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBHost,
-		cfg.DBPort,
-		cfg.DBName,
-	)
-
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return err
+	}
+
+	DB, err = gorm.Open(dialector, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Report unique violations as gorm.ErrDuplicatedKey on every driver
+		TranslateError: true,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("Database connection established")
+	log.Printf("Database connection established (driver: %s)", cfg.DBDriver)
 	return nil
 }
 
+// newDialector builds the GORM dialector for the configured database driver.
+// For SQLite, DBName is interpreted as the database file path. Unsupported
+// drivers are rejected here, before any connection is attempted.
+func newDialector(cfg *config.Config) (gorm.Dialector, error) {
+	switch cfg.DBDriver {
+	case DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.DBUser,
+			cfg.DBPassword,
+			cfg.DBHost,
+			cfg.DBPort,
+			cfg.DBName,
+		)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			cfg.DBHost,
+			cfg.DBPort,
+			cfg.DBUser,
+			cfg.DBPassword,
+			cfg.DBName,
+			cfg.DBSSLMode,
+		)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		// The Docker image is built with CGO_ENABLED=0, so it only supports MySQL and PostgreSQL
+		if !sqliteSupported {
+			return nil, fmt.Errorf("database driver %q requires a binary built with CGO_ENABLED=1", cfg.DBDriver)
+		}
+		return sqlite.Open(cfg.DBName), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", cfg.DBDriver)
+	}
+}
+
+// Migrate applies all pending versioned schema migrations.
 func Migrate() error {
 	log.Println("Running database migrations...")
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.Organization{},
-		&models.OrganizationMember{},
-		&models.Task{},
-		&models.TaskAssignment{},
-	)
-	if err != nil {
+	if err := migrations.NewRunner(DB, migrations.All()).Up(); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 	log.Println("Database migrations completed")
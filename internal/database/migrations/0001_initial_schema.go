@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The structs below are frozen snapshots of the models at the time of this
+// migration. They must not be changed when the models in internal/models evolve.
+
+type user0001 struct {
+	ID           uint64 `gorm:"primarykey"`
+	Username     string `gorm:"type:varchar(50);uniqueIndex;not null"`
+	PasswordHash string `gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (user0001) TableName() string { return "users" }
+
+type organization0001 struct {
+	ID         uint64 `gorm:"primarykey"`
+	Name       string `gorm:"type:varchar(255);not null"`
+	InviteCode string `gorm:"type:varchar(50);uniqueIndex;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
+
+func (organization0001) TableName() string { return "organizations" }
+
+type organizationMember0001 struct {
+	OrganizationID uint64 `gorm:"primarykey"`
+	UserID         uint64 `gorm:"primarykey"`
+	Role           string `gorm:"type:varchar(20);not null"`
+	JoinedAt       time.Time
+}
+
+func (organizationMember0001) TableName() string { return "organization_members" }
+
+type task0001 struct {
+	ID             uint64 `gorm:"primarykey"`
+	Title          string `gorm:"not null"`
+	Description    string `gorm:"type:text"`
+	Status         string `gorm:"type:varchar(20);not null;default:'TODO'"`
+	DueDate        *time.Time
+	CreatorID      uint64 `gorm:"not null"`
+	OrganizationID uint64 `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (task0001) TableName() string { return "tasks" }
+
+type taskAssignment0001 struct {
+	TaskID    uint64 `gorm:"primarykey"`
+	UserID    uint64 `gorm:"primarykey"`
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (taskAssignment0001) TableName() string { return "task_assignments" }
+
+// migration0001InitialSchema creates the core tables. Tables that already
+// exist (databases previously managed by AutoMigrate) are left untouched so
+// existing deployments can adopt versioned migrations in place.
+var migration0001InitialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx,
+			&user0001{},
+			&organization0001{},
+			&organizationMember0001{},
+			&task0001{},
+			&taskAssignment0001{},
+		)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&taskAssignment0001{},
+			&task0001{},
+			&organizationMember0001{},
+			&organization0001{},
+			&user0001{},
+		)
+	},
+}
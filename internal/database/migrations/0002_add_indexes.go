@@ -0,0 +1,33 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// performanceIndexes0002 are the indexes used for filtering and sorting tasks
+// and resolving memberships. The invite code index is created by 0001.
+var performanceIndexes0002 = []index{
+	// Task indexes for filtering and sorting
+	{"tasks", "idx_tasks_organization_id", "organization_id"},
+	{"tasks", "idx_tasks_creator_id", "creator_id"},
+	{"tasks", "idx_tasks_status", "status"},
+	{"tasks", "idx_tasks_due_date", "due_date"},
+	{"tasks", "idx_tasks_created_at", "created_at"},
+
+	// Organization members indexes
+	{"organization_members", "idx_org_members_organization_id", "organization_id"},
+	{"organization_members", "idx_org_members_user_id", "user_id"},
+
+	// Task assignments indexes
+	{"task_assignments", "idx_task_assignments_task_id", "task_id"},
+	{"task_assignments", "idx_task_assignments_user_id", "user_id"},
+}
+
+var migration0002AddIndexes = Migration{
+	Version: 2,
+	Name:    "add_indexes",
+	Up: func(tx *gorm.DB) error {
+		return createIndexes(tx, performanceIndexes0002...)
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropIndexes(tx, performanceIndexes0002...)
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type personalAccessToken0003 struct {
+	ID          uint64 `gorm:"primarykey"`
+	UserID      uint64 `gorm:"not null;index"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	TokenHash   string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	TokenPrefix string `gorm:"type:varchar(16);not null"`
+	Scopes      string `gorm:"type:varchar(255);not null"`
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+func (personalAccessToken0003) TableName() string { return "personal_access_tokens" }
+
+var migration0003PersonalAccessTokens = Migration{
+	Version: 3,
+	Name:    "personal_access_tokens",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &personalAccessToken0003{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&personalAccessToken0003{})
+	},
+}
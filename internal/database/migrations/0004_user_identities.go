@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userIdentity0004 struct {
+	ID        uint64 `gorm:"primarykey"`
+	UserID    uint64 `gorm:"not null;index"`
+	Issuer    string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject"`
+	Subject   string `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject"`
+	Email     string `gorm:"type:varchar(255)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (userIdentity0004) TableName() string { return "user_identities" }
+
+var migration0004UserIdentities = Migration{
+	Version: 4,
+	Name:    "user_identities",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &userIdentity0004{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userIdentity0004{})
+	},
+}
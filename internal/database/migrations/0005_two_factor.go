@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userTwoFactor0005 struct {
+	UserID       uint64 `gorm:"primarykey;autoIncrement:false"`
+	Secret       string `gorm:"type:varchar(64);not null"`
+	EnabledAt    *time.Time
+	LastUsedStep int64 `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (userTwoFactor0005) TableName() string { return "user_two_factors" }
+
+type recoveryCode0005 struct {
+	ID        uint64 `gorm:"primarykey"`
+	UserID    uint64 `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (recoveryCode0005) TableName() string { return "recovery_codes" }
+
+var migration0005TwoFactor = Migration{
+	Version: 5,
+	Name:    "two_factor",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &userTwoFactor0005{}, &recoveryCode0005{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&recoveryCode0005{}, &userTwoFactor0005{})
+	},
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userSession0006 struct {
+	ID         uint64 `gorm:"primarykey"`
+	UserID     uint64 `gorm:"not null;index"`
+	TokenHash  string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	UserAgent  string `gorm:"type:varchar(255)"`
+	IPAddress  string `gorm:"type:varchar(45)"`
+	LastSeenAt time.Time
+	ExpiresAt  time.Time `gorm:"not null"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (userSession0006) TableName() string { return "user_sessions" }
+
+var migration0006UserSessions = Migration{
+	Version: 6,
+	Name:    "user_sessions",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &userSession0006{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userSession0006{})
+	},
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type passwordResetToken0007 struct {
+	ID        uint64    `gorm:"primarykey"`
+	UserID    uint64    `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (passwordResetToken0007) TableName() string { return "password_reset_tokens" }
+
+var migration0007PasswordResetTokens = Migration{
+	Version: 7,
+	Name:    "password_reset_tokens",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &passwordResetToken0007{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&passwordResetToken0007{})
+	},
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type organizationPolicyRule0008 struct {
+	OrganizationID uint64 `gorm:"primarykey"`
+	Action         string `gorm:"primarykey;type:varchar(50)"`
+	Role           string `gorm:"primarykey;type:varchar(20)"`
+	Scope          string `gorm:"type:varchar(20);not null"`
+	UpdatedAt      time.Time
+}
+
+func (organizationPolicyRule0008) TableName() string { return "organization_policy_rules" }
+
+var migration0008OrganizationPolicyRules = Migration{
+	Version: 8,
+	Name:    "organization_policy_rules",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &organizationPolicyRule0008{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&organizationPolicyRule0008{})
+	},
+}
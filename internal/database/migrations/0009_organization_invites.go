@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type organizationInvite0009 struct {
+	ID             uint64 `gorm:"primarykey"`
+	OrganizationID uint64 `gorm:"not null;index"`
+	Name           string `gorm:"type:varchar(100);not null"`
+	Code           string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Role           string `gorm:"type:varchar(20);not null"`
+	MaxUses        *int
+	UseCount       int `gorm:"not null;default:0"`
+	ExpiresAt      *time.Time
+	RevokedAt      *time.Time
+	CreatedByID    uint64 `gorm:"not null"`
+	CreatedAt      time.Time
+}
+
+func (organizationInvite0009) TableName() string { return "organization_invites" }
+
+type organizationInviteUse0009 struct {
+	ID       uint64    `gorm:"primarykey"`
+	InviteID uint64    `gorm:"not null;index"`
+	UserID   uint64    `gorm:"not null;index"`
+	UsedAt   time.Time `gorm:"not null"`
+}
+
+func (organizationInviteUse0009) TableName() string { return "organization_invite_uses" }
+
+var migration0009OrganizationInvites = Migration{
+	Version: 9,
+	Name:    "organization_invites",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &organizationInvite0009{}, &organizationInviteUse0009{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&organizationInviteUse0009{}, &organizationInvite0009{})
+	},
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type organizationInvitation0010 struct {
+	ID             uint64    `gorm:"primarykey"`
+	OrganizationID uint64    `gorm:"not null;index"`
+	InviteeID      uint64    `gorm:"not null;index"`
+	InviterID      uint64    `gorm:"not null"`
+	Role           string    `gorm:"type:varchar(20);not null"`
+	Status         string    `gorm:"type:varchar(20);not null"`
+	ExpiresAt      time.Time `gorm:"not null"`
+	RespondedAt    *time.Time
+	CreatedAt      time.Time
+}
+
+func (organizationInvitation0010) TableName() string { return "organization_invitations" }
+
+var migration0010OrganizationInvitations = Migration{
+	Version: 10,
+	Name:    "organization_invitations",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &organizationInvitation0010{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&organizationInvitation0010{})
+	},
+}
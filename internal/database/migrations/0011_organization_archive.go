@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type organization0011 struct {
+	ArchivedAt *time.Time
+}
+
+func (organization0011) TableName() string { return "organizations" }
+
+var migration0011OrganizationArchive = Migration{
+	Version: 11,
+	Name:    "organization_archive",
+	Up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn(&organization0011{}, "ArchivedAt") {
+			return nil
+		}
+		return tx.Migrator().AddColumn(&organization0011{}, "ArchivedAt")
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropColumn(&organization0011{}, "ArchivedAt")
+	},
+}
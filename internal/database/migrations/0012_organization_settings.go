@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type organizationSettings0012 struct {
+	OrganizationID      uint64 `gorm:"primarykey"`
+	DefaultTaskStatus   string `gorm:"type:varchar(20);not null"`
+	MembersCanInvite    bool   `gorm:"not null"`
+	DefaultDueTime      string `gorm:"type:varchar(5);not null"`
+	Timezone            string `gorm:"type:varchar(64);not null"`
+	AIGenerationEnabled bool   `gorm:"not null"`
+	MaxMembers          *int
+	MaxTasks            *int
+	AIGenerationsPerDay *int
+	UpdatedAt           time.Time
+}
+
+func (organizationSettings0012) TableName() string { return "organization_settings" }
+
+type organizationAIUsage0012 struct {
+	OrganizationID uint64 `gorm:"primarykey"`
+	Day            string `gorm:"primarykey;type:varchar(10)"`
+	Count          int    `gorm:"not null"`
+}
+
+func (organizationAIUsage0012) TableName() string { return "organization_ai_usages" }
+
+var migration0012OrganizationSettings = Migration{
+	Version: 12,
+	Name:    "organization_settings",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &organizationSettings0012{}, &organizationAIUsage0012{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&organizationAIUsage0012{}, &organizationSettings0012{})
+	},
+}
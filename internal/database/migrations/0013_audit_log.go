@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type auditLogEntry0013 struct {
+	ID             uint64 `gorm:"primarykey"`
+	OrganizationID uint64 `gorm:"not null;index"`
+	ActorID        uint64 `gorm:"not null;index"`
+	Action         string `gorm:"type:varchar(64);not null"`
+	TargetType     string `gorm:"type:varchar(20);not null"`
+	TargetID       uint64 `gorm:"not null"`
+	Changes        string `gorm:"type:text;not null"`
+	IPAddress      string `gorm:"type:varchar(45)"`
+	UserAgent      string `gorm:"type:varchar(255)"`
+	AccessTokenID  *uint64
+	CreatedAt      time.Time `gorm:"index"`
+}
+
+func (auditLogEntry0013) TableName() string { return "audit_log_entries" }
+
+var migration0013AuditLog = Migration{
+	Version: 13,
+	Name:    "audit_log",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &auditLogEntry0013{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&auditLogEntry0013{})
+	},
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type workflowState0014 struct {
+	ID             uint64 `gorm:"primarykey"`
+	OrganizationID uint64 `gorm:"not null;uniqueIndex:idx_workflow_states_org_key"`
+	Key            string `gorm:"type:varchar(20);not null;uniqueIndex:idx_workflow_states_org_key"`
+	Name           string `gorm:"type:varchar(50);not null"`
+	Position       int    `gorm:"not null"`
+	Done           bool   `gorm:"not null"`
+}
+
+func (workflowState0014) TableName() string { return "workflow_states" }
+
+type workflowTransition0014 struct {
+	ID             uint64 `gorm:"primarykey"`
+	OrganizationID uint64 `gorm:"not null;index"`
+	FromStatus     string `gorm:"type:varchar(20);not null"`
+	ToStatus       string `gorm:"type:varchar(20);not null"`
+}
+
+func (workflowTransition0014) TableName() string { return "workflow_transitions" }
+
+var migration0014Workflows = Migration{
+	Version: 14,
+	Name:    "workflows",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &workflowState0014{}, &workflowTransition0014{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&workflowTransition0014{}, &workflowState0014{})
+	},
+}
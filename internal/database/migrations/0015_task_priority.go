@@ -0,0 +1,23 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type task0015 struct {
+	Priority string `gorm:"type:varchar(10);not null;default:'normal'"`
+}
+
+func (task0015) TableName() string { return "tasks" }
+
+var migration0015TaskPriority = Migration{
+	Version: 15,
+	Name:    "task_priority",
+	Up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn(&task0015{}, "Priority") {
+			return nil
+		}
+		return tx.Migrator().AddColumn(&task0015{}, "Priority")
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropColumn(&task0015{}, "Priority")
+	},
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type label0016 struct {
+	ID             uint64 `gorm:"primarykey"`
+	OrganizationID uint64 `gorm:"not null;uniqueIndex:idx_labels_org_name"`
+	Name           string `gorm:"type:varchar(50);not null;uniqueIndex:idx_labels_org_name"`
+	Color          string `gorm:"type:varchar(7);not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (label0016) TableName() string { return "labels" }
+
+type taskLabel0016 struct {
+	TaskID    uint64 `gorm:"primarykey"`
+	LabelID   uint64 `gorm:"primarykey;index"`
+	CreatedAt time.Time
+}
+
+func (taskLabel0016) TableName() string { return "task_labels" }
+
+var migration0016Labels = Migration{
+	Version: 16,
+	Name:    "labels",
+	Up: func(tx *gorm.DB) error {
+		return createTablesIfNotExist(tx, &label0016{}, &taskLabel0016{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&taskLabel0016{}, &label0016{})
+	},
+}
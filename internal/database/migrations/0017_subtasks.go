@@ -0,0 +1,31 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type task0017 struct {
+	ParentID *uint64 `gorm:"index"`
+}
+
+func (task0017) TableName() string { return "tasks" }
+
+var migration0017Subtasks = Migration{
+	Version: 17,
+	Name:    "subtasks",
+	Up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn(&task0017{}, "ParentID") {
+			return nil
+		}
+		if err := tx.Migrator().AddColumn(&task0017{}, "ParentID"); err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&task0017{}, "ParentID")
+	},
+	Down: func(tx *gorm.DB) error {
+		if tx.Migrator().HasIndex(&task0017{}, "ParentID") {
+			if err := tx.Migrator().DropIndex(&task0017{}, "ParentID"); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&task0017{}, "ParentID")
+	},
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type taskDependency0018 struct {
+	TaskID    uint64 `gorm:"primarykey"`
+	BlockerID uint64 `gorm:"primarykey;index"`
+	CreatedAt time.Time
+}
+
+func (taskDependency0018) TableName() string { return "task_dependencies" }
+
+type organizationSettings0018 struct {
+	EnforceDependencies bool `gorm:"not null;default:false"`
+}
+
+func (organizationSettings0018) TableName() string { return "organization_settings" }
+
+var migration0018TaskDependencies = Migration{
+	Version: 18,
+	Name:    "task_dependencies",
+	Up: func(tx *gorm.DB) error {
+		if err := createTablesIfNotExist(tx, &taskDependency0018{}); err != nil {
+			return err
+		}
+		if tx.Migrator().HasColumn(&organizationSettings0018{}, "EnforceDependencies") {
+			return nil
+		}
+		return tx.Migrator().AddColumn(&organizationSettings0018{}, "EnforceDependencies")
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&organizationSettings0018{}, "EnforceDependencies"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&taskDependency0018{})
+	},
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// index describes a plain (non-unique) index created with raw SQL.
+type index struct {
+	table   string
+	name    string
+	columns string
+}
+
+// createTablesIfNotExist creates each table that does not exist yet.
+func createTablesIfNotExist(tx *gorm.DB, tables ...interface{}) error {
+	for _, table := range tables {
+		if tx.Migrator().HasTable(table) {
+			continue
+		}
+		if err := tx.Migrator().CreateTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIndexes creates each index that does not exist yet.
+func createIndexes(tx *gorm.DB, indexes ...index) error {
+	for _, idx := range indexes {
+		if tx.Migrator().HasIndex(idx.table, idx.name) {
+			continue
+		}
+
+		sql := fmt.Sprintf("CREATE INDEX %s ON %s (%s)", idx.name, idx.table, idx.columns)
+		if err := tx.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// dropIndexes drops each index that exists.
+func dropIndexes(tx *gorm.DB, indexes ...index) error {
+	for _, idx := range indexes {
+		if !tx.Migrator().HasIndex(idx.table, idx.name) {
+			continue
+		}
+		if err := tx.Migrator().DropIndex(idx.table, idx.name); err != nil {
+			return fmt.Errorf("failed to drop index %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
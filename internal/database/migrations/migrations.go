@@ -0,0 +1,27 @@
+package migrations
+
+// All returns every schema migration in version order.
+// New migrations must be appended here with the next version number and
+// must never be edited once released; add a new migration instead.
+func All() []Migration {
+	return []Migration{
+		migration0001InitialSchema,
+		migration0002AddIndexes,
+		migration0003PersonalAccessTokens,
+		migration0004UserIdentities,
+		migration0005TwoFactor,
+		migration0006UserSessions,
+		migration0007PasswordResetTokens,
+		migration0008OrganizationPolicyRules,
+		migration0009OrganizationInvites,
+		migration0010OrganizationInvitations,
+		migration0011OrganizationArchive,
+		migration0012OrganizationSettings,
+		migration0013AuditLog,
+		migration0014Workflows,
+		migration0015TaskPriority,
+		migration0016Labels,
+		migration0017Subtasks,
+		migration0018TaskDependencies,
+	}
+}
@@ -0,0 +1,357 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultLockTimeout is how long a runner waits for another runner to release the lock
+	DefaultLockTimeout = 2 * time.Minute
+
+	// DefaultLockHeartbeat is how often the lock holder refreshes the lock while migrations run
+	DefaultLockHeartbeat = 30 * time.Second
+
+	// DefaultStaleLockAge is how long a lock may go without a heartbeat before
+	// its holder is considered gone and the lock is taken over
+	DefaultStaleLockAge = 2 * time.Minute
+
+	lockPollInterval = 500 * time.Millisecond
+	lockRowID        = 1
+)
+
+var (
+	// ErrLockTimeout is returned when the migration lock cannot be acquired in time.
+	ErrLockTimeout = errors.New("timed out waiting for migration lock")
+	// ErrUnknownVersion is returned when a target version does not match any migration.
+	ErrUnknownVersion = errors.New("unknown migration version")
+	// ErrNothingToRollback is returned when Down is called with no applied migrations.
+	ErrNothingToRollback = errors.New("no applied migrations to roll back")
+)
+
+// Migration is a single numbered, reversible schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration in the schema_migrations table.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName returns the table used to track applied migrations.
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// SchemaMigrationLock is a single-row table used as a cross-process mutex.
+// Inserting the row acquires the lock; the primary key guarantees only one holder.
+type SchemaMigrationLock struct {
+	ID       int       `gorm:"primaryKey;autoIncrement:false"`
+	LockedBy string    `gorm:"type:varchar(255);not null"`
+	LockedAt time.Time `gorm:"not null"`
+}
+
+// TableName returns the table used for the migration lock.
+func (SchemaMigrationLock) TableName() string {
+	return "schema_migrations_lock"
+}
+
+// Status describes whether a known migration has been applied.
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Runner applies and rolls back migrations against a database.
+type Runner struct {
+	db            *gorm.DB
+	migrations    []Migration
+	LockTimeout   time.Duration
+	LockHeartbeat time.Duration
+	StaleLockAge  time.Duration
+}
+
+// NewRunner creates a Runner for the given migrations, sorted by version.
+func NewRunner(db *gorm.DB, migrations []Migration) *Runner {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Runner{
+		db:            db,
+		migrations:    sorted,
+		LockTimeout:   DefaultLockTimeout,
+		LockHeartbeat: DefaultLockHeartbeat,
+		StaleLockAge:  DefaultStaleLockAge,
+	}
+}
+
+// Up applies all pending migrations.
+func (r *Runner) Up() error {
+	if len(r.migrations) == 0 {
+		return nil
+	}
+	return r.To(r.migrations[len(r.migrations)-1].Version)
+}
+
+// Down rolls back the most recently applied migration.
+func (r *Runner) Down() error {
+	return r.withLock(func() error {
+		applied, err := r.appliedVersions()
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[r.migrations[i].Version]; ok {
+				return r.rollback(r.migrations[i])
+			}
+		}
+		return ErrNothingToRollback
+	})
+}
+
+// To migrates the schema up or down so that exactly the migrations with
+// version <= target are applied. A target of 0 rolls back everything.
+func (r *Runner) To(target int) error {
+	if target != 0 && r.find(target) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	return r.withLock(func() error {
+		applied, err := r.appliedVersions()
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0; i-- {
+			m := r.migrations[i]
+			if _, ok := applied[m.Version]; ok && m.Version > target {
+				if err := r.rollback(m); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, m := range r.migrations {
+			if _, ok := applied[m.Version]; !ok && m.Version <= target {
+				if err := r.apply(m); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status reports every known migration and whether it has been applied.
+func (r *Runner) Status() ([]Status, error) {
+	if err := r.ensureTables(); err != nil {
+		return nil, err
+	}
+
+	applied, err := r.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(r.migrations))
+	for i, m := range r.migrations {
+		statuses[i] = Status{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			appliedAt := record.AppliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// apply runs a migration's Up step and records it.
+func (r *Runner) apply(m Migration) error {
+	log.Printf("Applying migration %04d_%s", m.Version, m.Name)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&SchemaMigration{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// rollback runs a migration's Down step and removes its record.
+func (r *Runner) rollback(m Migration) error {
+	log.Printf("Rolling back migration %04d_%s", m.Version, m.Name)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&SchemaMigration{}, m.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
+func (r *Runner) find(version int) *Migration {
+	for i := range r.migrations {
+		if r.migrations[i].Version == version {
+			return &r.migrations[i]
+		}
+	}
+	return nil
+}
+
+func (r *Runner) appliedVersions() (map[int]SchemaMigration, error) {
+	var records []SchemaMigration
+	if err := r.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// ensureTables creates the bookkeeping tables if they do not exist yet.
+// Another process may create them concurrently, so a failed create is
+// only an error if the table is still missing afterwards.
+func (r *Runner) ensureTables() error {
+	for _, table := range []interface{}{&SchemaMigration{}, &SchemaMigrationLock{}} {
+		if r.db.Migrator().HasTable(table) {
+			continue
+		}
+		if err := r.db.Migrator().CreateTable(table); err != nil && !r.db.Migrator().HasTable(table) {
+			return fmt.Errorf("failed to create migration table: %w", err)
+		}
+	}
+	return nil
+}
+
+// withLock runs fn while holding the cross-process migration lock.
+func (r *Runner) withLock(fn func() error) error {
+	if err := r.ensureTables(); err != nil {
+		return err
+	}
+
+	owner := lockOwner()
+	if err := r.acquireLock(owner); err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.db.Delete(&SchemaMigrationLock{}, lockRowID).Error; err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	stopHeartbeat := r.keepLockAlive(owner)
+	defer stopHeartbeat()
+
+	return fn()
+}
+
+func (r *Runner) acquireLock(owner string) error {
+	deadline := time.Now().Add(r.LockTimeout)
+
+	for {
+		err := r.db.Create(&SchemaMigrationLock{
+			ID:       lockRowID,
+			LockedBy: owner,
+			LockedAt: time.Now(),
+		}).Error
+		if err == nil {
+			return nil
+		}
+
+		var current SchemaMigrationLock
+		if findErr := r.db.First(&current, lockRowID).Error; findErr != nil {
+			if !errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to acquire migration lock: %w", err)
+			}
+			// The holder released the lock between our insert and read; retry immediately.
+			continue
+		}
+
+		if time.Since(current.LockedAt) > r.StaleLockAge {
+			log.Printf("Taking over stale migration lock held by %s since %s", current.LockedBy, current.LockedAt)
+			// Only delete the lock if no heartbeat refreshed it since it was read
+			r.db.Where("id = ? AND locked_at < ?", lockRowID, time.Now().Add(-r.StaleLockAge)).Delete(&SchemaMigrationLock{})
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w (held by %s)", ErrLockTimeout, current.LockedBy)
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// keepLockAlive refreshes LockedAt every LockHeartbeat until the returned
+// function is called, so that a long migration is not mistaken for an
+// abandoned lock while its runner is still alive.
+func (r *Runner) keepLockAlive(owner string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(r.LockHeartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				result := r.db.Model(&SchemaMigrationLock{}).
+					Where("id = ? AND locked_by = ?", lockRowID, owner).
+					Update("locked_at", time.Now())
+				if result.Error != nil {
+					log.Printf("Failed to refresh migration lock: %v", result.Error)
+				} else if result.RowsAffected == 0 {
+					log.Printf("Migration lock held by %s was lost", owner)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func lockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
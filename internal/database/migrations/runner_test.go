@@ -0,0 +1,176 @@
+package migrations
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupMigrationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return db
+}
+
+// currentModels lists every model that must be fully backed by the migrations.
+func currentModels() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.Task{},
+		&models.TaskAssignment{},
+		&models.PersonalAccessToken{},
+		&models.UserIdentity{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.UserSession{},
+		&models.PasswordResetToken{},
+		&models.OrganizationPolicyRule{},
+		&models.OrganizationInvite{},
+		&models.OrganizationInviteUse{},
+		&models.OrganizationInvitation{},
+		&models.OrganizationSettings{},
+		&models.OrganizationAIUsage{},
+		&models.AuditLogEntry{},
+		&models.WorkflowState{},
+		&models.WorkflowTransition{},
+		&models.Label{},
+		&models.TaskLabel{},
+		&models.TaskDependency{},
+	}
+}
+
+func TestRunner_UpCoversAllModels(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	runner := NewRunner(db, All())
+
+	require.NoError(t, runner.Up())
+
+	for _, model := range currentModels() {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+
+		require.True(t, db.Migrator().HasTable(model), "missing table %s", stmt.Schema.Table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			require.True(t, db.Migrator().HasColumn(model, field.DBName),
+				"missing column %s.%s", stmt.Schema.Table, field.DBName)
+		}
+	}
+
+	statuses, err := runner.Status()
+	require.NoError(t, err)
+	for _, s := range statuses {
+		require.True(t, s.Applied, "migration %d not applied", s.Version)
+	}
+
+	// Running again is a no-op.
+	require.NoError(t, runner.Up())
+}
+
+func TestRunner_DownAndTo(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	runner := NewRunner(db, All())
+
+	require.NoError(t, runner.Up())
+	require.NoError(t, runner.Down())
+
+	statuses, err := runner.Status()
+	require.NoError(t, err)
+	require.False(t, statuses[len(statuses)-1].Applied)
+
+	require.NoError(t, runner.To(0))
+	require.False(t, db.Migrator().HasTable("users"))
+	require.False(t, db.Migrator().HasTable("tasks"))
+
+	require.NoError(t, runner.To(1))
+	require.True(t, db.Migrator().HasTable("users"))
+
+	require.ErrorIs(t, runner.To(9999), ErrUnknownVersion)
+}
+
+func TestRunner_AdoptsExistingAutoMigratedSchema(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	require.NoError(t, db.AutoMigrate(currentModels()...))
+
+	require.NoError(t, NewRunner(db, All()).Up())
+}
+
+func TestRunner_LockPreventsConcurrentRuns(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	runner := NewRunner(db, All())
+	runner.LockTimeout = 50 * time.Millisecond
+
+	require.NoError(t, runner.ensureTables())
+	require.NoError(t, db.Create(&SchemaMigrationLock{
+		ID:       lockRowID,
+		LockedBy: "other-replica",
+		LockedAt: time.Now(),
+	}).Error)
+
+	require.ErrorIs(t, runner.Up(), ErrLockTimeout)
+
+	// A lock older than StaleLockAge is taken over.
+	runner.StaleLockAge = 0
+	require.NoError(t, runner.Up())
+
+	var count int64
+	require.NoError(t, db.Model(&SchemaMigrationLock{}).Count(&count).Error)
+	require.Equal(t, int64(0), count)
+}
+
+func TestRunner_HeartbeatKeepsLockDuringLongMigration(t *testing.T) {
+	db := setupMigrationTestDB(t)
+
+	var applied atomic.Int32
+	slow := []Migration{{
+		Version: 1,
+		Name:    "slow",
+		Up: func(tx *gorm.DB) error {
+			applied.Add(1)
+			time.Sleep(2 * lockPollInterval)
+			return nil
+		},
+		Down: func(tx *gorm.DB) error { return nil },
+	}}
+
+	holder := NewRunner(db, slow)
+	holder.LockHeartbeat = 20 * time.Millisecond
+	holder.StaleLockAge = 100 * time.Millisecond
+	done := make(chan error, 1)
+	go func() { done <- holder.Up() }()
+
+	require.Eventually(t, func() bool {
+		var count int64
+		return db.Model(&SchemaMigrationLock{}).Count(&count).Error == nil && count == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// The migration outlasts StaleLockAge, but the heartbeat keeps the lock
+	// fresh so the second runner waits instead of taking it over
+	waiter := NewRunner(db, slow)
+	waiter.StaleLockAge = 100 * time.Millisecond
+	require.NoError(t, waiter.Up())
+	require.NoError(t, <-done)
+	require.Equal(t, int32(1), applied.Load())
+}
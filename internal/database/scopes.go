@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+
 	"gorm.io/gorm"
 
 	"github.com/yukikurage/task-management-api/internal/utils"
@@ -12,3 +14,19 @@ func Paginate(params utils.PaginationParams) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(params.Offset).Limit(params.Limit)
 	}
 }
+
+// NullsLastExpr returns an ORDER BY expression for column that sorts NULL values last.
+// MySQL has no NULLS LAST modifier, so an IS NULL prefix is used there instead.
+func NullsLastExpr(db *gorm.DB, column string, desc bool) string {
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	switch db.Dialector.Name() {
+	case DriverPostgres, DriverSQLite:
+		return fmt.Sprintf("%s %s NULLS LAST", column, direction)
+	default:
+		return fmt.Sprintf("%s IS NULL, %s %s", column, column, direction)
+	}
+}
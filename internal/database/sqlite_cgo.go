@@ -0,0 +1,7 @@
+//go:build cgo
+
+package database
+
+// sqliteSupported reports whether the SQLite driver works in this build. It
+// wraps the C library and so needs cgo.
+const sqliteSupported = true
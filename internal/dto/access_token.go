@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// AccessTokenDTO represents a personal access token in API responses
+type AccessTokenDTO struct {
+	ID          uint64              `json:"id"`
+	Name        string              `json:"name"`
+	TokenPrefix string              `json:"token_prefix"`
+	Scopes      []models.TokenScope `json:"scopes"`
+	ExpiresAt   *time.Time          `json:"expires_at"`
+	LastUsedAt  *time.Time          `json:"last_used_at"`
+	RevokedAt   *time.Time          `json:"revoked_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// CreatedAccessTokenDTO includes the plaintext token, returned only once at creation
+type CreatedAccessTokenDTO struct {
+	AccessTokenDTO
+	Token string `json:"token"`
+}
+
+// ToAccessTokenDTO converts a PersonalAccessToken model to AccessTokenDTO
+func ToAccessTokenDTO(token models.PersonalAccessToken) AccessTokenDTO {
+	return AccessTokenDTO{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.ScopeList(),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		RevokedAt:   token.RevokedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
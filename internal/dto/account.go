@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// ExportedProfileDTO is the profile section of a personal data export
+type ExportedProfileDTO struct {
+	ID               uint64    `json:"id"`
+	Username         string    `json:"username"`
+	HasPassword      bool      `json:"has_password"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	ExportedAt       time.Time `json:"exported_at"`
+}
+
+// ExportedIdentityDTO represents a linked single sign-on identity in a personal data export
+type ExportedIdentityDTO struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportedMembershipDTO represents an organization membership in a personal data export
+type ExportedMembershipDTO struct {
+	Organization OrganizationDTO         `json:"organization"`
+	Role         models.OrganizationRole `json:"role"`
+	JoinedAt     time.Time               `json:"joined_at"`
+}
+
+// ToExportedIdentityDTO converts a UserIdentity model to ExportedIdentityDTO
+func ToExportedIdentityDTO(identity models.UserIdentity) ExportedIdentityDTO {
+	return ExportedIdentityDTO{
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+// ToExportedMembershipDTO converts an organization member to ExportedMembershipDTO
+func ToExportedMembershipDTO(member models.OrganizationMember) ExportedMembershipDTO {
+	return ExportedMembershipDTO{
+		Organization: ToOrganizationDTO(member.Organization, false),
+		Role:         member.Role,
+		JoinedAt:     member.JoinedAt,
+	}
+}
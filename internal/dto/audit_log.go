@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// AuditLogEntryDTO represents an entry of an organization's audit log
+type AuditLogEntryDTO struct {
+	ID            uint64                 `json:"id"`
+	Actor         UserDTO                `json:"actor"`
+	Action        models.AuditAction     `json:"action"`
+	TargetType    models.AuditTargetType `json:"target_type"`
+	TargetID      uint64                 `json:"target_id"`
+	Changes       json.RawMessage        `json:"changes"`
+	IPAddress     string                 `json:"ip_address"`
+	UserAgent     string                 `json:"user_agent"`
+	AccessTokenID *uint64                `json:"access_token_id"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// AuditLogResponse represents a page of audit log entries, newest first
+type AuditLogResponse struct {
+	Entries []AuditLogEntryDTO `json:"entries"`
+	// NextCursor is passed as cursor to fetch the next page, null on the last page
+	NextCursor *string `json:"next_cursor"`
+}
+
+// ToAuditLogEntryDTO converts an audit log entry to DTO
+func ToAuditLogEntryDTO(entry models.AuditLogEntry) AuditLogEntryDTO {
+	changes := json.RawMessage(entry.Changes)
+	if !json.Valid(changes) {
+		changes = json.RawMessage("{}")
+	}
+	return AuditLogEntryDTO{
+		ID:            entry.ID,
+		Actor:         ToUserDTO(entry.Actor),
+		Action:        entry.Action,
+		TargetType:    entry.TargetType,
+		TargetID:      entry.TargetID,
+		Changes:       changes,
+		IPAddress:     entry.IPAddress,
+		UserAgent:     entry.UserAgent,
+		AccessTokenID: entry.AccessTokenID,
+		CreatedAt:     entry.CreatedAt,
+	}
+}
+
+// ToAuditLogResponse converts a page of audit log entries to a response
+func ToAuditLogResponse(entries []models.AuditLogEntry, nextCursor *uint64) AuditLogResponse {
+	response := AuditLogResponse{Entries: make([]AuditLogEntryDTO, len(entries))}
+	for i, entry := range entries {
+		response.Entries[i] = ToAuditLogEntryDTO(entry)
+	}
+	if nextCursor != nil {
+		cursor := strconv.FormatUint(*nextCursor, 10)
+		response.NextCursor = &cursor
+	}
+	return response
+}
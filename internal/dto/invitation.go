@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// InvitationDTO represents a targeted organization invitation. The organization
+// is included in the invitee's inbox, the invitee in the organization's list.
+type InvitationDTO struct {
+	ID           uint64                  `json:"id"`
+	Organization *OrganizationDTO        `json:"organization,omitempty"`
+	Invitee      *UserDTO                `json:"invitee,omitempty"`
+	Inviter      UserDTO                 `json:"inviter"`
+	Role         models.OrganizationRole `json:"role"`
+	Status       models.InvitationStatus `json:"status"`
+	ExpiresAt    time.Time               `json:"expires_at"`
+	RespondedAt  *time.Time              `json:"responded_at"`
+	CreatedAt    time.Time               `json:"created_at"`
+}
+
+// ToInvitationDTO converts an invitation to DTO, computing its status at the given time
+func ToInvitationDTO(invitation models.OrganizationInvitation, now time.Time) InvitationDTO {
+	dto := InvitationDTO{
+		ID:          invitation.ID,
+		Inviter:     ToUserDTO(invitation.Inviter),
+		Role:        invitation.Role,
+		Status:      invitation.EffectiveStatus(now),
+		ExpiresAt:   invitation.ExpiresAt,
+		RespondedAt: invitation.RespondedAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+	if invitation.Organization.ID != 0 {
+		org := ToOrganizationDTO(invitation.Organization, false)
+		dto.Organization = &org
+	}
+	if invitation.Invitee.ID != 0 {
+		invitee := ToUserDTO(invitation.Invitee)
+		dto.Invitee = &invitee
+	}
+	return dto
+}
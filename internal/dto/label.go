@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// LabelDTO represents an organization label in API responses
+type LabelDTO struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// LabelListResponse represents the labels of an organization
+type LabelListResponse struct {
+	Labels []LabelDTO `json:"labels"`
+}
+
+// ToLabelDTO converts a Label model to LabelDTO
+func ToLabelDTO(label models.Label) LabelDTO {
+	return LabelDTO{
+		ID:    label.ID,
+		Name:  label.Name,
+		Color: label.Color,
+	}
+}
+
+// ToLabelListResponse converts labels to LabelListResponse
+func ToLabelListResponse(labels []models.Label) LabelListResponse {
+	response := LabelListResponse{Labels: make([]LabelDTO, len(labels))}
+	for i, label := range labels {
+		response.Labels[i] = ToLabelDTO(label)
+	}
+	return response
+}
+
+// toTaskLabelDTOs converts the preloaded labels of a task, ordered by name
+func toTaskLabelDTOs(taskLabels []models.TaskLabel) []LabelDTO {
+	labels := make([]LabelDTO, len(taskLabels))
+	for i, taskLabel := range taskLabels {
+		labels[i] = ToLabelDTO(taskLabel.Label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name)
+	})
+	return labels
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
 )
 
 // OrganizationWithRoleDTO represents an organization with the user's role
@@ -19,6 +20,22 @@ type OrganizationMemberDTO struct {
 	JoinedAt time.Time               `json:"joined_at"`
 }
 
+// OrganizationMemberListItemDTO represents a member in the member listing
+type OrganizationMemberListItemDTO struct {
+	OrganizationMemberDTO
+	RegisteredAt      time.Time `json:"registered_at"`
+	OpenAssignedTasks int64     `json:"open_assigned_tasks"`
+}
+
+// OrganizationMemberListResponse represents a paginated list of members
+type OrganizationMemberListResponse struct {
+	Members    []OrganizationMemberListItemDTO `json:"members"`
+	Page       int                             `json:"page"`
+	PageSize   int                             `json:"page_size"`
+	TotalCount int64                           `json:"total_count"`
+	TotalPages int                             `json:"total_pages"`
+}
+
 // OrganizationDetailDTO represents detailed organization information
 type OrganizationDetailDTO struct {
 	OrganizationDTO
@@ -43,6 +60,26 @@ func ToOrganizationMemberDTO(member models.OrganizationMember) OrganizationMembe
 	}
 }
 
+// ToOrganizationMemberListResponse converts a page of members to OrganizationMemberListResponse
+func ToOrganizationMemberListResponse(members []models.OrganizationMember, openAssignedTasks map[uint64]int64, page, pageSize int, totalCount int64) OrganizationMemberListResponse {
+	items := make([]OrganizationMemberListItemDTO, len(members))
+	for i, member := range members {
+		items[i] = OrganizationMemberListItemDTO{
+			OrganizationMemberDTO: ToOrganizationMemberDTO(member),
+			RegisteredAt:          member.User.CreatedAt,
+			OpenAssignedTasks:     openAssignedTasks[member.UserID],
+		}
+	}
+
+	return OrganizationMemberListResponse{
+		Members:    items,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: totalCount,
+		TotalPages: totalPages(totalCount, pageSize),
+	}
+}
+
 // ToOrganizationDetailDTO converts organization with members to detailed DTO
 func ToOrganizationDetailDTO(org models.Organization, members []models.OrganizationMember, yourRole models.OrganizationRole) OrganizationDetailDTO {
 	memberDTOs := make([]OrganizationMemberDTO, len(members))
@@ -56,3 +93,161 @@ func ToOrganizationDetailDTO(org models.Organization, members []models.Organizat
 		YourRole:        yourRole,
 	}
 }
+
+// PolicyRuleDTO represents the scope a role has for a customizable action
+type PolicyRuleDTO struct {
+	Action     policy.Action           `json:"action"`
+	Role       models.OrganizationRole `json:"role"`
+	Scope      policy.Scope            `json:"scope"`
+	Customized bool                    `json:"customized"`
+}
+
+// OrganizationPolicyDTO represents an organization's effective policy for customizable actions
+type OrganizationPolicyDTO struct {
+	Rules []PolicyRuleDTO `json:"rules"`
+}
+
+// ToOrganizationPolicyDTO converts a policy to DTO
+func ToOrganizationPolicyDTO(p policy.Policy) OrganizationPolicyDTO {
+	rules := make([]PolicyRuleDTO, 0, len(policy.CustomizableActions)*len(policy.Roles))
+	for _, action := range policy.CustomizableActions {
+		for _, role := range policy.Roles {
+			rules = append(rules, PolicyRuleDTO{
+				Action:     action,
+				Role:       role,
+				Scope:      p.Scope(role, action),
+				Customized: p.Overridden(role, action),
+			})
+		}
+	}
+	return OrganizationPolicyDTO{Rules: rules}
+}
+
+// OrganizationQuotasDTO represents an organization's limits, null meaning unlimited
+type OrganizationQuotasDTO struct {
+	MaxMembers          *int `json:"max_members"`
+	MaxTasks            *int `json:"max_tasks"`
+	AIGenerationsPerDay *int `json:"ai_generations_per_day"`
+}
+
+// OrganizationSettingsDTO represents an organization's settings
+type OrganizationSettingsDTO struct {
+	DefaultTaskStatus   models.TaskStatus     `json:"default_task_status"`
+	MembersCanInvite    bool                  `json:"members_can_invite"`
+	DefaultDueTime      string                `json:"default_due_time"`
+	Timezone            string                `json:"timezone"`
+	AIGenerationEnabled bool                  `json:"ai_generation_enabled"`
+	EnforceDependencies bool                  `json:"enforce_dependencies"`
+	Quotas              OrganizationQuotasDTO `json:"quotas"`
+}
+
+// ToOrganizationSettingsDTO converts organization settings to DTO
+func ToOrganizationSettingsDTO(settings models.OrganizationSettings) OrganizationSettingsDTO {
+	return OrganizationSettingsDTO{
+		DefaultTaskStatus:   settings.DefaultTaskStatus,
+		MembersCanInvite:    settings.MembersCanInvite,
+		DefaultDueTime:      settings.DefaultDueTime,
+		Timezone:            settings.Timezone,
+		AIGenerationEnabled: settings.AIGenerationEnabled,
+		EnforceDependencies: settings.EnforceDependencies,
+		Quotas: OrganizationQuotasDTO{
+			MaxMembers:          settings.MaxMembers,
+			MaxTasks:            settings.MaxTasks,
+			AIGenerationsPerDay: settings.AIGenerationsPerDay,
+		},
+	}
+}
+
+// WorkflowStateDTO represents a state of an organization's task workflow
+type WorkflowStateDTO struct {
+	Key  models.TaskStatus `json:"key"`
+	Name string            `json:"name"`
+	Done bool              `json:"done"`
+}
+
+// WorkflowTransitionDTO represents an allowed move between workflow states
+type WorkflowTransitionDTO struct {
+	From models.TaskStatus `json:"from"`
+	To   models.TaskStatus `json:"to"`
+}
+
+// WorkflowDTO represents an organization's task workflow with its states in order
+type WorkflowDTO struct {
+	States      []WorkflowStateDTO      `json:"states"`
+	Transitions []WorkflowTransitionDTO `json:"transitions"`
+}
+
+// ToWorkflowDTO converts a workflow to DTO
+func ToWorkflowDTO(workflow models.Workflow) WorkflowDTO {
+	dto := WorkflowDTO{
+		States:      make([]WorkflowStateDTO, len(workflow.States)),
+		Transitions: make([]WorkflowTransitionDTO, len(workflow.Transitions)),
+	}
+	for i, state := range workflow.States {
+		dto.States[i] = WorkflowStateDTO{Key: state.Key, Name: state.Name, Done: state.Done}
+	}
+	for i, transition := range workflow.Transitions {
+		dto.Transitions[i] = WorkflowTransitionDTO{From: transition.FromStatus, To: transition.ToStatus}
+	}
+	return dto
+}
+
+// InviteDTO represents an organization invite link
+type InviteDTO struct {
+	ID        uint64                  `json:"id"`
+	Name      string                  `json:"name"`
+	Code      string                  `json:"code"`
+	Role      models.OrganizationRole `json:"role"`
+	Status    models.InviteStatus     `json:"status"`
+	MaxUses   *int                    `json:"max_uses"`
+	UseCount  int                     `json:"use_count"`
+	ExpiresAt *time.Time              `json:"expires_at"`
+	RevokedAt *time.Time              `json:"revoked_at"`
+	CreatedBy uint64                  `json:"created_by"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// InviteUseDTO represents a user who joined through an invite link
+type InviteUseDTO struct {
+	User   UserDTO   `json:"user"`
+	UsedAt time.Time `json:"used_at"`
+}
+
+// InviteDetailDTO represents an invite link with its usage history
+type InviteDetailDTO struct {
+	InviteDTO
+	Uses []InviteUseDTO `json:"uses"`
+}
+
+// ToInviteDTO converts an invite to DTO, computing its status at the given time
+func ToInviteDTO(invite models.OrganizationInvite, now time.Time) InviteDTO {
+	return InviteDTO{
+		ID:        invite.ID,
+		Name:      invite.Name,
+		Code:      invite.Code,
+		Role:      invite.Role,
+		Status:    invite.Status(now),
+		MaxUses:   invite.MaxUses,
+		UseCount:  invite.UseCount,
+		ExpiresAt: invite.ExpiresAt,
+		RevokedAt: invite.RevokedAt,
+		CreatedBy: invite.CreatedByID,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+// ToInviteDetailDTO converts an invite with its uses to DTO
+func ToInviteDetailDTO(invite models.OrganizationInvite, now time.Time) InviteDetailDTO {
+	uses := make([]InviteUseDTO, len(invite.Uses))
+	for i, use := range invite.Uses {
+		uses[i] = InviteUseDTO{
+			User:   ToUserDTO(use.User),
+			UsedAt: use.UsedAt,
+		}
+	}
+
+	return InviteDetailDTO{
+		InviteDTO: ToInviteDTO(invite, now),
+		Uses:      uses,
+	}
+}
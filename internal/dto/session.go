@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// SessionDTO represents a login session in API responses
+type SessionDTO struct {
+	ID         uint64    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `json:"current"`
+}
+
+// ToSessionDTO converts a UserSession model to SessionDTO
+func ToSessionDTO(session models.UserSession, currentSessionID uint64) SessionDTO {
+	return SessionDTO{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		CreatedAt:  session.CreatedAt,
+		Current:    session.ID == currentSessionID,
+	}
+}
@@ -14,9 +14,10 @@ type UserDTO struct {
 
 // OrganizationDTO represents an organization in API responses
 type OrganizationDTO struct {
-	ID         uint64 `json:"id"`
-	Name       string `json:"name"`
-	InviteCode string `json:"invite_code,omitempty"`
+	ID         uint64     `json:"id"`
+	Name       string     `json:"name"`
+	InviteCode string     `json:"invite_code,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at"`
 }
 
 // TaskAssignmentDTO represents a task assignment in API responses
@@ -30,9 +31,14 @@ type TaskDTO struct {
 	Title          string              `json:"title"`
 	Description    string              `json:"description"`
 	Status         models.TaskStatus   `json:"status"`
+	Priority       models.TaskPriority `json:"priority"`
+	Labels         []LabelDTO          `json:"labels"`
 	DueDate        *time.Time          `json:"due_date"`
 	CreatorID      uint64              `json:"creator_id"`
 	OrganizationID uint64              `json:"organization_id"`
+	ParentID       *uint64             `json:"parent_id"`
+	Subtasks       *SubtaskProgressDTO `json:"subtasks,omitempty"`
+	IsBlocked      bool                `json:"is_blocked"`
 	CreatedAt      time.Time           `json:"created_at"`
 	UpdatedAt      time.Time           `json:"updated_at"`
 	Creator        *UserDTO            `json:"creator,omitempty"`
@@ -42,14 +48,31 @@ type TaskDTO struct {
 
 // TaskListItemDTO represents a task in list responses (minimal data)
 type TaskListItemDTO struct {
-	ID          uint64            `json:"id"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Status      models.TaskStatus `json:"status"`
-	DueDate     *time.Time        `json:"due_date"`
-	CreatorID   uint64            `json:"creator_id"`
-	Creator     *UserDTO          `json:"creator,omitempty"`
-	CreatedAt   time.Time         `json:"created_at"`
+	ID          uint64              `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Status      models.TaskStatus   `json:"status"`
+	Priority    models.TaskPriority `json:"priority"`
+	Labels      []LabelDTO          `json:"labels"`
+	DueDate     *time.Time          `json:"due_date"`
+	CreatorID   uint64              `json:"creator_id"`
+	Creator     *UserDTO            `json:"creator,omitempty"`
+	ParentID    *uint64             `json:"parent_id"`
+	Subtasks    *SubtaskProgressDTO `json:"subtasks,omitempty"`
+	IsBlocked   bool                `json:"is_blocked"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// SubtaskProgressDTO summarizes how many direct subtasks of a task are done
+type SubtaskProgressDTO struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
+
+// TrashedTaskDTO represents a deleted task in an organization's trash
+type TrashedTaskDTO struct {
+	TaskListItemDTO
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
 // TaskListResponse represents a paginated list of tasks
@@ -61,6 +84,15 @@ type TaskListResponse struct {
 	TotalPages int               `json:"total_pages"`
 }
 
+// TrashListResponse represents a paginated list of deleted tasks
+type TrashListResponse struct {
+	Tasks      []TrashedTaskDTO `json:"tasks"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalCount int64            `json:"total_count"`
+	TotalPages int              `json:"total_pages"`
+}
+
 // Conversion functions
 
 // ToUserDTO converts a User model to UserDTO
@@ -74,8 +106,9 @@ func ToUserDTO(user models.User) UserDTO {
 // ToOrganizationDTO converts an Organization model to OrganizationDTO
 func ToOrganizationDTO(org models.Organization, includeInviteCode bool) OrganizationDTO {
 	dto := OrganizationDTO{
-		ID:   org.ID,
-		Name: org.Name,
+		ID:         org.ID,
+		Name:       org.Name,
+		ArchivedAt: org.ArchivedAt,
 	}
 	if includeInviteCode {
 		dto.InviteCode = org.InviteCode
@@ -90,9 +123,12 @@ func ToTaskDTO(task models.Task) TaskDTO {
 		Title:          task.Title,
 		Description:    task.Description,
 		Status:         task.Status,
+		Priority:       task.Priority,
+		Labels:         toTaskLabelDTOs(task.Labels),
 		DueDate:        task.DueDate,
 		CreatorID:      task.CreatorID,
 		OrganizationID: task.OrganizationID,
+		ParentID:       task.ParentID,
 		CreatedAt:      task.CreatedAt,
 		UpdatedAt:      task.UpdatedAt,
 	}
@@ -129,8 +165,11 @@ func ToTaskListItemDTO(task models.Task) TaskListItemDTO {
 		Title:       task.Title,
 		Description: task.Description,
 		Status:      task.Status,
+		Priority:    task.Priority,
+		Labels:      toTaskLabelDTOs(task.Labels),
 		DueDate:     task.DueDate,
 		CreatorID:   task.CreatorID,
+		ParentID:    task.ParentID,
 		CreatedAt:   task.CreatedAt,
 	}
 
@@ -143,6 +182,26 @@ func ToTaskListItemDTO(task models.Task) TaskListItemDTO {
 	return dto
 }
 
+// ToSubtaskProgressDTO converts subtask counts to SubtaskProgressDTO, or nil
+// for a task without subtasks
+func ToSubtaskProgressDTO(progress models.SubtaskProgress) *SubtaskProgressDTO {
+	if progress.Total == 0 {
+		return nil
+	}
+	return &SubtaskProgressDTO{
+		Done:  progress.Done,
+		Total: progress.Total,
+	}
+}
+
+// ToTrashedTaskDTO converts a soft-deleted Task model to TrashedTaskDTO
+func ToTrashedTaskDTO(task models.Task) TrashedTaskDTO {
+	return TrashedTaskDTO{
+		TaskListItemDTO: ToTaskListItemDTO(task),
+		DeletedAt:       task.DeletedAt.Time,
+	}
+}
+
 // ToTaskListResponse converts a slice of tasks to TaskListResponse
 func ToTaskListResponse(tasks []models.Task, page, pageSize int, totalCount int64) TaskListResponse {
 	items := make([]TaskListItemDTO, len(tasks))
@@ -150,16 +209,36 @@ func ToTaskListResponse(tasks []models.Task, page, pageSize int, totalCount int6
 		items[i] = ToTaskListItemDTO(task)
 	}
 
-	totalPages := int(totalCount) / pageSize
-	if int(totalCount)%pageSize > 0 {
-		totalPages++
+	return TaskListResponse{
+		Tasks:      items,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: totalCount,
+		TotalPages: totalPages(totalCount, pageSize),
 	}
+}
 
-	return TaskListResponse{
+// ToTrashListResponse converts a slice of soft-deleted tasks to TrashListResponse
+func ToTrashListResponse(tasks []models.Task, page, pageSize int, totalCount int64) TrashListResponse {
+	items := make([]TrashedTaskDTO, len(tasks))
+	for i, task := range tasks {
+		items[i] = ToTrashedTaskDTO(task)
+	}
+
+	return TrashListResponse{
 		Tasks:      items,
 		Page:       page,
 		PageSize:   pageSize,
 		TotalCount: totalCount,
-		TotalPages: totalPages,
+		TotalPages: totalPages(totalCount, pageSize),
+	}
+}
+
+// totalPages returns the number of pages needed to show totalCount items
+func totalPages(totalCount int64, pageSize int) int {
+	pages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize > 0 {
+		pages++
 	}
+	return pages
 }
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// TwoFactorStatusDTO represents a user's two-factor configuration
+type TwoFactorStatusDTO struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollmentDTO carries the TOTP secret for an authenticator app
+type TwoFactorEnrollmentDTO struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesDTO carries newly issued recovery codes, shown only once
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
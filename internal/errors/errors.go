@@ -11,6 +11,7 @@ const (
 	// Authentication errors
 	ErrCodeUnauthorized     = "UNAUTHORIZED"
 	ErrCodeInvalidCredentials = "INVALID_CREDENTIALS"
+	ErrCodeAccountLocked    = "ACCOUNT_LOCKED"
 
 	// Authorization errors
 	ErrCodeForbidden        = "FORBIDDEN"
@@ -25,10 +26,12 @@ const (
 	ErrCodeNotFound         = "NOT_FOUND"
 	ErrCodeAlreadyExists    = "ALREADY_EXISTS"
 	ErrCodeConflict         = "CONFLICT"
+	ErrCodeGone             = "GONE"
 
 	// Business logic errors
 	ErrCodeInvalidOperation = "INVALID_OPERATION"
 	ErrCodeOperationFailed  = "OPERATION_FAILED"
+	ErrCodeQuotaExceeded    = "QUOTA_EXCEEDED"
 
 	// Service errors
 	ErrCodeInternalError    = "INTERNAL_ERROR"
@@ -126,6 +129,30 @@ func Conflict(c *gin.Context, message string) {
 	RespondWithError(c, http.StatusConflict, NewAPIError(ErrCodeConflict, message))
 }
 
+// Gone sends a 410 response for resources that existed but can no longer be used
+func Gone(c *gin.Context, message string) {
+	if message == "" {
+		message = "Resource is no longer available"
+	}
+	RespondWithError(c, http.StatusGone, NewAPIError(ErrCodeGone, message))
+}
+
+// QuotaExceeded sends a 403 response when an organization quota has been used up
+func QuotaExceeded(c *gin.Context, message string) {
+	if message == "" {
+		message = "Quota exceeded"
+	}
+	RespondWithError(c, http.StatusForbidden, NewAPIError(ErrCodeQuotaExceeded, message))
+}
+
+// AccountLocked sends a 429 response for logins blocked by brute-force protection
+func AccountLocked(c *gin.Context, message string) {
+	if message == "" {
+		message = "Too many failed login attempts"
+	}
+	RespondWithError(c, http.StatusTooManyRequests, NewAPIError(ErrCodeAccountLocked, message))
+}
+
 // InternalError sends a 500 response
 func InternalError(c *gin.Context, message string) {
 	if message == "" {
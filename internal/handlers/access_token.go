@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// AccessTokenHandler handles personal access token management.
+type AccessTokenHandler struct {
+	tokenService *services.AccessTokenService
+}
+
+// NewAccessTokenHandler creates a new AccessTokenHandler.
+func NewAccessTokenHandler(tokenService *services.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// CreateToken mints a new personal access token for the current user.
+func (h *AccessTokenHandler) CreateToken(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	type CreateTokenRequest struct {
+		Name          string              `json:"name" binding:"required,max=100"`
+		Scopes        []models.TokenScope `json:"scopes" binding:"required"`
+		ExpiresInDays *int                `json:"expires_in_days"`
+	}
+
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	token, secret, err := h.tokenService.CreateAccessToken(services.CreateAccessTokenInput{
+		UserID:        userID,
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+	})
+	if err != nil {
+		respondAccessTokenError(c, err, "Failed to create access token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreatedAccessTokenDTO{
+		AccessTokenDTO: dto.ToAccessTokenDTO(*token),
+		Token:          secret,
+	})
+}
+
+// ListTokens returns the current user's personal access tokens.
+func (h *AccessTokenHandler) ListTokens(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	tokens, err := h.tokenService.ListAccessTokens(userID)
+	if err != nil {
+		respondAccessTokenError(c, err, "Failed to list access tokens")
+		return
+	}
+
+	tokenDTOs := make([]dto.AccessTokenDTO, len(tokens))
+	for i, token := range tokens {
+		tokenDTOs[i] = dto.ToAccessTokenDTO(token)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokenDTOs,
+	})
+}
+
+// RevokeToken revokes one of the current user's personal access tokens.
+func (h *AccessTokenHandler) RevokeToken(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	tokenID, err := strconv.ParseUint(c.Param("token_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid token ID")
+		return
+	}
+
+	if err := h.tokenService.RevokeAccessToken(userID, tokenID); err != nil {
+		respondAccessTokenError(c, err, "Failed to revoke access token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Access token revoked successfully",
+	})
+}
+
+func respondAccessTokenError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAccessTokenName),
+		errors.Is(err, services.ErrInvalidAccessTokenScope):
+		apierrors.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrInvalidAccessTokenTTL):
+		apierrors.BadRequest(c, fmt.Sprintf("expires_in_days must be between 1 and %d", constants.AccessTokenMaxLifetimeDays))
+	case errors.Is(err, services.ErrAccessTokenNotFound):
+		apierrors.NotFound(c, err.Error())
+	default:
+		apierrors.InternalError(c, defaultMessage)
+	}
+}
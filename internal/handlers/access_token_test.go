@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type accessTokenTestEnv struct {
+	db     *gorm.DB
+	router *gin.Engine
+	user   *models.User
+}
+
+func setupAccessTokenTestEnv(t *testing.T) accessTokenTestEnv {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.PersonalAccessToken{},
+		&models.UserSession{},
+	)
+	require.NoError(t, err)
+
+	database.SetDB(db)
+
+	userRepo := repository.NewUserRepository(db)
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db))
+	authHandler := NewAuthHandler(
+		newTestAuthService(db, sessionService, &recordingNotifier{}),
+		services.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo),
+		sessionService,
+	)
+	tokenHandler := NewAccessTokenHandler(services.NewAccessTokenService(repository.NewAccessTokenRepository(db)))
+
+	user := &models.User{Username: "scripter", PasswordHash: "hashed"}
+	require.NoError(t, db.Create(user).Error)
+
+	_, sessionToken, err := sessionService.CreateSession(services.CreateSessionInput{UserID: user.ID})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+
+	// Simulate a logged-in session for token management requests
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader("X-Test-Session") == "1" {
+			session := sessions.Default(c)
+			session.Set(constants.ContextKeyUserID, user.ID)
+			session.Set(constants.SessionKeySessionToken, sessionToken)
+		}
+		c.Next()
+	})
+
+	auth := r.Group("/api/auth")
+	auth.GET("/me", middleware.RequireAuth(), authHandler.GetCurrentUser)
+	auth.POST("/ping", middleware.RequireAuth(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "pong"})
+	})
+	tokens := auth.Group("/tokens", middleware.RequireAuth(), middleware.RequireSessionAuth())
+	tokens.GET("", tokenHandler.ListTokens)
+	tokens.POST("", tokenHandler.CreateToken)
+	tokens.DELETE("/:token_id", tokenHandler.RevokeToken)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return accessTokenTestEnv{
+		db:     db,
+		router: r,
+		user:   user,
+	}
+}
+
+func (env accessTokenTestEnv) do(method, url string, body any, headers map[string]string) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, url, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+	return w
+}
+
+func (env accessTokenTestEnv) createToken(t *testing.T, scopes []string) dto.CreatedAccessTokenDTO {
+	t.Helper()
+
+	w := env.do(http.MethodPost, "/api/auth/tokens", map[string]any{
+		"name":            "ci",
+		"scopes":          scopes,
+		"expires_in_days": 30,
+	}, map[string]string{"X-Test-Session": "1"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created dto.CreatedAccessTokenDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	return created
+}
+
+func TestAccessToken_AuthenticatesAndTracksLastUsed(t *testing.T) {
+	env := setupAccessTokenTestEnv(t)
+
+	created := env.createToken(t, []string{"write"})
+	require.NotEmpty(t, created.Token)
+	require.Equal(t, created.Token[:constants.AccessTokenDisplayPrefixLength], created.TokenPrefix)
+	require.ElementsMatch(t, []models.TokenScope{models.TokenScopeRead, models.TokenScopeWrite}, created.Scopes)
+
+	var stored models.PersonalAccessToken
+	require.NoError(t, env.db.First(&stored, created.ID).Error)
+	require.NotEqual(t, created.Token, stored.TokenHash, "token must be stored hashed")
+	require.Nil(t, stored.LastUsedAt)
+
+	bearer := map[string]string{"Authorization": "Bearer " + created.Token}
+
+	w := env.do(http.MethodGet, "/api/auth/me", nil, bearer)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var me dto.UserDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &me))
+	require.Equal(t, env.user.ID, me.ID)
+
+	w = env.do(http.MethodPost, "/api/auth/ping", nil, bearer)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.NoError(t, env.db.First(&stored, created.ID).Error)
+	require.NotNil(t, stored.LastUsedAt)
+
+	// Tokens cannot be used to manage tokens
+	w = env.do(http.MethodGet, "/api/auth/tokens", nil, bearer)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAccessToken_ReadScopeRejectsWrites(t *testing.T) {
+	env := setupAccessTokenTestEnv(t)
+
+	created := env.createToken(t, []string{"read"})
+	bearer := map[string]string{"Authorization": "Bearer " + created.Token}
+
+	require.Equal(t, http.StatusOK, env.do(http.MethodGet, "/api/auth/me", nil, bearer).Code)
+	require.Equal(t, http.StatusForbidden, env.do(http.MethodPost, "/api/auth/ping", nil, bearer).Code)
+}
+
+func TestAccessToken_Revoke(t *testing.T) {
+	env := setupAccessTokenTestEnv(t)
+
+	created := env.createToken(t, []string{"read"})
+	bearer := map[string]string{"Authorization": "Bearer " + created.Token}
+
+	w := env.do(http.MethodDelete, "/api/auth/tokens/"+strconv.FormatUint(created.ID, 10), nil,
+		map[string]string{"X-Test-Session": "1"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, http.StatusUnauthorized, env.do(http.MethodGet, "/api/auth/me", nil, bearer).Code)
+
+	w = env.do(http.MethodGet, "/api/auth/tokens", nil, map[string]string{"X-Test-Session": "1"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string][]dto.AccessTokenDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response["tokens"], 1)
+	require.NotNil(t, response["tokens"][0].RevokedAt)
+}
+
+func TestAccessToken_InvalidToken(t *testing.T) {
+	env := setupAccessTokenTestEnv(t)
+
+	w := env.do(http.MethodGet, "/api/auth/me", nil, map[string]string{"Authorization": "Bearer tma_bogus"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// AccountHandler handles account deletion and personal data export.
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler.
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// DeleteAccount deletes the current user's account and ends the session.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	type DeleteAccountRequest struct {
+		Password string `json:"password"`
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	// The body may be omitted by accounts without a password
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(userID, req.Password); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Clear()
+	if err := session.Save(); err != nil {
+		apierrors.InternalError(c, "Failed to clear session")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Account deleted successfully",
+	})
+}
+
+// ExportData returns a zip archive with all personal data of the current user.
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	export, err := h.accountService.ExportAccount(userID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	archive, err := buildExportArchive(export)
+	if err != nil {
+		apierrors.InternalError(c, "Failed to build export")
+		return
+	}
+
+	filename := fmt.Sprintf("account-export-%d-%s.zip", userID, export.ExportedAt.UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// buildExportArchive writes each section of the export as a JSON file in a zip archive.
+func buildExportArchive(export *services.AccountExport) ([]byte, error) {
+	identities := make([]dto.ExportedIdentityDTO, len(export.Identities))
+	for i, identity := range export.Identities {
+		identities[i] = dto.ToExportedIdentityDTO(identity)
+	}
+
+	memberships := make([]dto.ExportedMembershipDTO, len(export.Memberships))
+	for i, member := range export.Memberships {
+		memberships[i] = dto.ToExportedMembershipDTO(member)
+	}
+
+	createdTasks := make([]dto.TaskDTO, len(export.CreatedTasks))
+	for i, task := range export.CreatedTasks {
+		createdTasks[i] = dto.ToTaskDTO(task)
+	}
+
+	assignedTasks := make([]dto.TaskDTO, len(export.AssignedTasks))
+	for i, task := range export.AssignedTasks {
+		assignedTasks[i] = dto.ToTaskDTO(task)
+	}
+
+	userSessions := make([]dto.SessionDTO, len(export.Sessions))
+	for i, s := range export.Sessions {
+		userSessions[i] = dto.ToSessionDTO(s, 0)
+	}
+
+	accessTokens := make([]dto.AccessTokenDTO, len(export.AccessTokens))
+	for i, token := range export.AccessTokens {
+		accessTokens[i] = dto.ToAccessTokenDTO(token)
+	}
+
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", dto.ExportedProfileDTO{
+			ID:               export.User.ID,
+			Username:         export.User.Username,
+			HasPassword:      export.User.PasswordHash != "",
+			TwoFactorEnabled: export.TwoFactorEnabled,
+			CreatedAt:        export.User.CreatedAt,
+			UpdatedAt:        export.User.UpdatedAt,
+			ExportedAt:       export.ExportedAt,
+		}},
+		{"identities.json", identities},
+		{"organizations.json", memberships},
+		{"tasks_created.json", createdTasks},
+		{"tasks_assigned.json", assignedTasks},
+		{"sessions.json", userSessions},
+		{"access_tokens.json", accessTokens},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
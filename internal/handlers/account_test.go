@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type accountTestEnv struct {
+	db     *gorm.DB
+	router *gin.Engine
+	user   *models.User
+}
+
+func setupAccountTestEnv(t *testing.T) accountTestEnv {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.OrganizationPolicyRule{},
+		&models.OrganizationInvite{},
+		&models.OrganizationInviteUse{},
+		&models.OrganizationInvitation{},
+		&models.OrganizationSettings{},
+		&models.OrganizationAIUsage{},
+		&models.WorkflowState{},
+		&models.WorkflowTransition{},
+		&models.Label{},
+		&models.TaskLabel{},
+		&models.TaskDependency{},
+		&models.Task{},
+		&models.TaskAssignment{},
+		&models.PersonalAccessToken{},
+		&models.UserIdentity{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.UserSession{},
+		&models.PasswordResetToken{},
+	)
+	require.NoError(t, err)
+
+	database.SetDB(db)
+
+	userRepo := repository.NewUserRepository(db)
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db))
+	authService := newTestAuthService(db, sessionService, &recordingNotifier{})
+	twoFactorService := services.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo)
+	authHandler := NewAuthHandler(authService, twoFactorService, sessionService)
+	accountHandler := NewAccountHandler(services.NewAccountService(
+		authService,
+		userRepo,
+		repository.NewOrganizationRepository(db),
+		repository.NewTaskRepository(db),
+		sessionService,
+		services.NewAccessTokenService(repository.NewAccessTokenRepository(db)),
+		twoFactorService,
+	))
+
+	user, err := authService.Signup(services.SignupInput{Username: "secure", Password: "supersecret"})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.POST("/api/auth/login", authHandler.Login)
+	r.GET("/api/auth/me", middleware.RequireAuth(), authHandler.GetCurrentUser)
+	r.DELETE("/api/auth/me", middleware.RequireAuth(), middleware.RequireSessionAuth(), accountHandler.DeleteAccount)
+	r.GET("/api/auth/me/export", middleware.RequireAuth(), middleware.RequireSessionAuth(), accountHandler.ExportData)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return accountTestEnv{
+		db:     db,
+		router: r,
+		user:   user,
+	}
+}
+
+func joinOrganization(t *testing.T, db *gorm.DB, orgID, userID uint64, role models.OrganizationRole, joinedAt time.Time) {
+	t.Helper()
+
+	require.NoError(t, db.Create(&models.OrganizationMember{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Role:           role,
+		JoinedAt:       joinedAt,
+	}).Error)
+}
+
+func TestAccountHandler_DeleteAccount(t *testing.T) {
+	env := setupAccountTestEnv(t)
+
+	now := time.Now()
+	veteran := createUser(t, env.db, "veteran")
+	newcomer := createUser(t, env.db, "newcomer")
+
+	shared := createOrganization(t, env.db, "shared")
+	joinOrganization(t, env.db, shared.ID, env.user.ID, models.RoleOwner, now.Add(-3*time.Hour))
+	joinOrganization(t, env.db, shared.ID, veteran.ID, models.RoleMember, now.Add(-2*time.Hour))
+	joinOrganization(t, env.db, shared.ID, newcomer.ID, models.RoleMember, now.Add(-time.Hour))
+
+	task := &models.Task{Title: "Write report", CreatorID: env.user.ID, OrganizationID: shared.ID}
+	require.NoError(t, env.db.Create(task).Error)
+	require.NoError(t, env.db.Create(&models.TaskAssignment{TaskID: task.ID, UserID: env.user.ID}).Error)
+	require.NoError(t, env.db.Create(&models.TaskAssignment{TaskID: task.ID, UserID: veteran.ID}).Error)
+
+	var personal models.OrganizationMember
+	require.NoError(t, env.db.Where("user_id = ? AND organization_id <> ?", env.user.ID, shared.ID).First(&personal).Error)
+
+	client := &sessionClient{t: t, router: env.router}
+	require.Equal(t, http.StatusOK, client.login().Code)
+
+	w := client.do(http.MethodDelete, "/api/auth/me", map[string]string{"password": "wrong-password"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = client.do(http.MethodDelete, "/api/auth/me", map[string]string{"password": "supersecret"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Signed out and unable to sign in again
+	require.Equal(t, http.StatusUnauthorized, client.do(http.MethodGet, "/api/auth/me", nil).Code)
+	require.Equal(t, http.StatusUnauthorized, (&sessionClient{t: t, router: env.router}).login().Code)
+
+	// The personal organization is gone, the shared one goes to its longest-standing member
+	require.ErrorIs(t, env.db.First(&models.Organization{}, personal.OrganizationID).Error, gorm.ErrRecordNotFound)
+	var promoted, unchanged models.OrganizationMember
+	require.NoError(t, env.db.Where("organization_id = ? AND user_id = ?", shared.ID, veteran.ID).First(&promoted).Error)
+	require.Equal(t, models.RoleOwner, promoted.Role)
+	require.NoError(t, env.db.Where("organization_id = ? AND user_id = ?", shared.ID, newcomer.ID).First(&unchanged).Error)
+	require.Equal(t, models.RoleMember, unchanged.Role)
+
+	var count int64
+	require.NoError(t, env.db.Model(&models.OrganizationMember{}).Where("user_id = ?", env.user.ID).Count(&count).Error)
+	require.Zero(t, count)
+	require.NoError(t, env.db.Unscoped().Model(&models.TaskAssignment{}).Where("user_id = ?", env.user.ID).Count(&count).Error)
+	require.Zero(t, count)
+	require.NoError(t, env.db.Model(&models.UserSession{}).Where("user_id = ?", env.user.ID).Count(&count).Error)
+	require.Zero(t, count)
+
+	// Authored tasks stay and show the anonymized creator
+	found, err := repository.NewTaskRepository(env.db).FindByID(task.ID, "Creator")
+	require.NoError(t, err)
+	require.Equal(t, constants.DeletedUsernamePrefix+strconv.FormatUint(env.user.ID, 10), found.Creator.Username)
+
+	// The original username is free again
+	_, err = repository.NewUserRepository(env.db).FindByUsername("secure")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestAccountHandler_ExportData(t *testing.T) {
+	env := setupAccountTestEnv(t)
+
+	var personal models.OrganizationMember
+	require.NoError(t, env.db.Where("user_id = ?", env.user.ID).First(&personal).Error)
+	task := &models.Task{Title: "Plan trip", CreatorID: env.user.ID, OrganizationID: personal.OrganizationID}
+	require.NoError(t, env.db.Create(task).Error)
+	require.NoError(t, env.db.Create(&models.TaskAssignment{TaskID: task.ID, UserID: env.user.ID}).Error)
+
+	client := &sessionClient{t: t, router: env.router}
+	require.Equal(t, http.StatusOK, client.login().Code)
+
+	w := client.do(http.MethodGet, "/api/auth/me/export", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Disposition"), "attachment;")
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = content
+	}
+	for _, name := range []string{
+		"profile.json", "identities.json", "organizations.json",
+		"tasks_created.json", "tasks_assigned.json", "sessions.json", "access_tokens.json",
+	} {
+		require.Contains(t, files, name)
+	}
+
+	var profile dto.ExportedProfileDTO
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	require.Equal(t, "secure", profile.Username)
+	require.True(t, profile.HasPassword)
+	require.NotContains(t, string(files["profile.json"]), "$2a$", "password hashes are never exported")
+
+	var created, assigned []dto.TaskDTO
+	require.NoError(t, json.Unmarshal(files["tasks_created.json"], &created))
+	require.NoError(t, json.Unmarshal(files["tasks_assigned.json"], &assigned))
+	require.Len(t, created, 1)
+	require.Equal(t, "Plan trip", created[0].Title)
+	require.Len(t, assigned, 1)
+
+	var memberships []dto.ExportedMembershipDTO
+	require.NoError(t, json.Unmarshal(files["organizations.json"], &memberships))
+	require.Len(t, memberships, 1)
+	require.Equal(t, models.RoleOwner, memberships[0].Role)
+
+	var userSessions []dto.SessionDTO
+	require.NoError(t, json.Unmarshal(files["sessions.json"], &userSessions))
+	require.Len(t, userSessions, 1)
+}
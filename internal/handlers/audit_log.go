@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/services"
+	"github.com/yukikurage/task-management-api/internal/utils"
+)
+
+// AuditLogHandler serves organization audit logs.
+type AuditLogHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditLogHandler creates a new AuditLogHandler.
+func NewAuditLogHandler(auditService *services.AuditService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLog returns a page of the organization's audit log, newest first.
+func (h *AuditLogHandler) ListAuditLog(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	input := services.ListAuditLogInput{
+		OrganizationID: org.ID,
+		Limit:          utils.GetPaginationParams(c).Limit,
+	}
+
+	var err error
+	if input.ActorID, err = optionalUintQuery(c, "actor_id"); err != nil {
+		apierrors.BadRequest(c, "Invalid actor_id")
+		return
+	}
+	if input.TargetID, err = optionalUintQuery(c, "target_id"); err != nil {
+		apierrors.BadRequest(c, "Invalid target_id")
+		return
+	}
+	if input.Cursor, err = optionalUintQuery(c, "cursor"); err != nil {
+		apierrors.BadRequest(c, "Invalid cursor")
+		return
+	}
+	if input.Since, err = optionalTimeQuery(c, "since"); err != nil {
+		apierrors.BadRequest(c, "Invalid since, expected an RFC 3339 timestamp")
+		return
+	}
+	if input.Until, err = optionalTimeQuery(c, "until"); err != nil {
+		apierrors.BadRequest(c, "Invalid until, expected an RFC 3339 timestamp")
+		return
+	}
+	if action := c.Query("action"); action != "" {
+		auditAction := models.AuditAction(action)
+		input.Action = &auditAction
+	}
+	if targetType := c.Query("target_type"); targetType != "" {
+		auditTargetType := models.AuditTargetType(targetType)
+		input.TargetType = &auditTargetType
+	}
+
+	page, err := h.auditService.ListEntries(input)
+	if err != nil {
+		respondAuditLogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAuditLogResponse(page.Entries, page.NextCursor))
+}
+
+// requestMeta describes the current request for the audit log.
+func requestMeta(c *gin.Context) services.RequestMeta {
+	meta := services.RequestMeta{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if value, exists := c.Get(constants.ContextKeyAccessToken); exists {
+		if token, ok := value.(models.PersonalAccessToken); ok {
+			meta.AccessTokenID = &token.ID
+		}
+	}
+	return meta
+}
+
+// optionalUintQuery parses an optional unsigned integer query parameter.
+func optionalUintQuery(c *gin.Context, key string) (*uint64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// optionalTimeQuery parses an optional RFC 3339 timestamp query parameter.
+func optionalTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// respondAuditLogError maps domain errors to API responses.
+func respondAuditLogError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAuditAction),
+		errors.Is(err, services.ErrInvalidAuditTargetType),
+		errors.Is(err, services.ErrInvalidAuditTimeRange):
+		apierrors.BadRequest(c, err.Error())
+	default:
+		apierrors.InternalError(c, "Failed to list audit log")
+	}
+}
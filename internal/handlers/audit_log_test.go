@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+func TestAuditLogHandler_ListAuditLog(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+	r.GET("/api/organizations/:id/audit-log", middleware.RequireOrganizationAccess(),
+		middleware.RequireOrganizationPermission(policy.ActionViewAuditLog), NewAuditLogHandler(env.auditService).ListAuditLog)
+	taskService := services.NewTaskService(repository.NewTaskRepository(env.db), repository.NewOrganizationRepository(env.db), nil, env.auditService)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	member := createTestOrganizationUser(t, env.db, "member")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	orgPath := fmt.Sprintf("/api/organizations/%d", org.ID)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations/join",
+		strings.NewReader(fmt.Sprintf(`{"invite_code":%q}`, org.InviteCode)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "audit-test/1.0")
+	req.Header.Set("X-Test-User", strconv.FormatUint(member.ID, 10))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = orgRoleRequest(t, r, http.MethodPut, orgPath, owner.ID, map[string]string{"name": "Renamed"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/regenerate-code", owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var regenerated dto.OrganizationDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &regenerated))
+
+	task, err := taskService.CreateTask(services.CreateTaskInput{Title: "Draft", OrganizationID: org.ID, CreatorID: member.ID})
+	require.NoError(t, err)
+	title := "Final"
+	_, err = taskService.UpdateTask(task.ID, services.UpdateTaskInput{ActorID: member.ID, Title: &title})
+	require.NoError(t, err)
+	require.NoError(t, taskService.AssignUsers(services.AssignUsersInput{TaskID: task.ID, ActorID: member.ID, UserIDs: []uint64{owner.ID}}))
+	_, err = taskService.ToggleTaskStatus(task.ID, owner.ID)
+	require.NoError(t, err)
+
+	list := func(userID uint64, query string) (*httptest.ResponseRecorder, dto.AuditLogResponse) {
+		t.Helper()
+		w := orgRoleRequest(t, r, http.MethodGet, orgPath+"/audit-log"+query, userID, nil)
+		var response dto.AuditLogResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response
+	}
+
+	t.Run("only owners can read the log", func(t *testing.T) {
+		w, _ := list(member.ID, "")
+		require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	})
+
+	t.Run("lists every change newest first", func(t *testing.T) {
+		w, response := list(owner.ID, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Nil(t, response.NextCursor)
+
+		actions := make([]models.AuditAction, len(response.Entries))
+		for i, entry := range response.Entries {
+			actions[i] = entry.Action
+		}
+		require.Equal(t, []models.AuditAction{
+			models.AuditActionTaskToggleStatus,
+			models.AuditActionTaskAssign,
+			models.AuditActionTaskUpdate,
+			models.AuditActionTaskCreate,
+			models.AuditActionOrganizationRegenerateCode,
+			models.AuditActionOrganizationRename,
+			models.AuditActionMemberJoin,
+		}, actions)
+
+		join := response.Entries[6]
+		require.Equal(t, member.ID, join.Actor.ID)
+		require.Equal(t, models.AuditTargetMember, join.TargetType)
+		require.Equal(t, member.ID, join.TargetID)
+		require.Equal(t, "audit-test/1.0", join.UserAgent)
+		require.NotEmpty(t, join.IPAddress)
+		require.JSONEq(t, `{"role":{"before":null,"after":"member"}}`, string(join.Changes))
+
+		require.JSONEq(t, `{"name":{"before":"Org","after":"Renamed"}}`, string(response.Entries[5].Changes))
+
+		// Invite codes grant access and never end up in the log
+		require.JSONEq(t, `{}`, string(response.Entries[4].Changes))
+		require.NotContains(t, w.Body.String(), org.InviteCode)
+		require.NotContains(t, w.Body.String(), regenerated.InviteCode)
+
+		require.JSONEq(t, `{"title":{"before":"Draft","after":"Final"}}`, string(response.Entries[2].Changes))
+		require.JSONEq(t, fmt.Sprintf(`{"assignee_ids":{"before":[%d],"after":[%d,%d]}}`, member.ID, member.ID, owner.ID),
+			string(response.Entries[1].Changes))
+		require.JSONEq(t, `{"status":{"before":"TODO","after":"DONE"}}`, string(response.Entries[0].Changes))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		_, response := list(owner.ID, "?action=task.update")
+		require.Len(t, response.Entries, 1)
+		require.Equal(t, task.ID, response.Entries[0].TargetID)
+
+		_, response = list(owner.ID, fmt.Sprintf("?target_type=task&target_id=%d", task.ID))
+		require.Len(t, response.Entries, 4)
+
+		_, response = list(owner.ID, fmt.Sprintf("?actor_id=%d", owner.ID))
+		require.Len(t, response.Entries, 3)
+
+		_, response = list(owner.ID, "?until=2000-01-01T00:00:00Z")
+		require.Empty(t, response.Entries)
+		require.NotNil(t, response.Entries)
+	})
+
+	t.Run("cursor pagination walks the whole log once", func(t *testing.T) {
+		seen := map[uint64]bool{}
+		query := "?limit=3"
+		pages := 0
+		for {
+			w, response := list(owner.ID, query)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			pages++
+			for _, entry := range response.Entries {
+				require.False(t, seen[entry.ID], "entry %d listed twice", entry.ID)
+				seen[entry.ID] = true
+			}
+			if response.NextCursor == nil {
+				break
+			}
+			query = "?limit=3&cursor=" + *response.NextCursor
+		}
+		require.Equal(t, 3, pages)
+		require.Len(t, seen, 7)
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		for _, query := range []string{
+			"?action=task.explode",
+			"?target_type=user",
+			"?actor_id=abc",
+			"?cursor=-1",
+			"?since=yesterday",
+			"?since=2030-01-01T00:00:00Z&until=2020-01-01T00:00:00Z",
+		} {
+			w, _ := list(owner.ID, query)
+			require.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("removed members stay in the log", func(t *testing.T) {
+		w := orgRoleRequest(t, r, http.MethodDelete, fmt.Sprintf("%s/members/%d", orgPath, member.ID), owner.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		_, response := list(owner.ID, fmt.Sprintf("?actor_id=%d", member.ID))
+		require.Len(t, response.Entries, 4)
+		require.Equal(t, "member", response.Entries[0].Actor.Username)
+
+		_, response = list(owner.ID, "?action=member.remove")
+		require.Len(t, response.Entries, 1)
+		require.Equal(t, owner.ID, response.Entries[0].Actor.ID)
+		require.JSONEq(t, `{"role":{"before":"member","after":null}}`, string(response.Entries[0].Changes))
+	})
+}
@@ -3,7 +3,10 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -16,13 +19,21 @@ import (
 
 // AuthHandler coordinates authentication-related HTTP handlers.
 type AuthHandler struct {
-	authService *services.AuthService
+	authService      *services.AuthService
+	twoFactorService *services.TwoFactorService
+	sessionService   *services.SessionService
 }
 
 // NewAuthHandler creates a new AuthHandler.
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(
+	authService *services.AuthService,
+	twoFactorService *services.TwoFactorService,
+	sessionService *services.SessionService,
+) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:      authService,
+		twoFactorService: twoFactorService,
+		sessionService:   sessionService,
 	}
 }
 
@@ -52,7 +63,9 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 	c.JSON(http.StatusCreated, userDTO)
 }
 
-// Login authenticates a user and initializes the session.
+// Login authenticates a user and initializes the session. Users with
+// two-factor authentication enabled get a pending session instead and must
+// complete the login with VerifyTwoFactorLogin.
 func (h *AuthHandler) Login(c *gin.Context) {
 	type LoginRequest struct {
 		Username string `json:"username" binding:"required"`
@@ -66,17 +79,103 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	user, err := h.authService.Login(services.LoginInput{
-		Username: req.Username,
-		Password: req.Password,
+		Username:  req.Username,
+		Password:  req.Password,
+		IPAddress: c.ClientIP(),
 	})
 	if err != nil {
 		respondAuthError(c, err)
 		return
 	}
 
+	twoFactorEnabled, err := h.twoFactorService.IsEnabled(user.ID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if twoFactorEnabled {
+		respondTwoFactorRequired(c, user.ID)
+		return
+	}
+
+	if err := h.authService.CompleteLogin(user); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if err := startUserSession(c, h.sessionService, user.ID); err != nil {
+		apierrors.InternalError(c, "Failed to save session")
+		return
+	}
+
+	userDTO := dto.ToUserDTO(*user)
+	c.JSON(http.StatusOK, userDTO)
+}
+
+// VerifyTwoFactorLogin completes a pending login with a TOTP or recovery code.
+func (h *AuthHandler) VerifyTwoFactorLogin(c *gin.Context) {
+	type VerifyTwoFactorRequest struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	var req VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
 	session := sessions.Default(c)
-	session.Set(constants.ContextKeyUserID, user.ID)
-	if err := session.Save(); err != nil {
+	userID, ok := pendingTwoFactorUserID(session)
+	if !ok {
+		apierrors.Unauthorized(c, "No pending two-factor login")
+		return
+	}
+
+	user, err := h.authService.GetUser(userID)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if err := h.authService.CheckLoginLockout(user, c.ClientIP()); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if err := h.twoFactorService.Verify(userID, req.Code); err != nil {
+		if !errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			respondTwoFactorError(c, err)
+			return
+		}
+		if err := h.authService.RecordFailedSecondFactor(user, c.ClientIP()); err != nil {
+			respondAuthError(c, err)
+			return
+		}
+
+		// Too many wrong codes discard the pending login so the password must be entered again
+		attempts, _ := session.Get(constants.SessionKeyTwoFactorAttempts).(int)
+		attempts++
+		if attempts >= constants.TwoFactorMaxAttempts {
+			clearTwoFactorLogin(session)
+		} else {
+			session.Set(constants.SessionKeyTwoFactorAttempts, attempts)
+		}
+		if err := session.Save(); err != nil {
+			apierrors.InternalError(c, "Failed to save session")
+			return
+		}
+
+		apierrors.Unauthorized(c, err.Error())
+		return
+	}
+
+	if err := h.authService.CompleteLogin(user); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	if err := startUserSession(c, h.sessionService, user.ID); err != nil {
 		apierrors.InternalError(c, "Failed to save session")
 		return
 	}
@@ -88,6 +187,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // Logout removes the authentication session.
 func (h *AuthHandler) Logout(c *gin.Context) {
 	session := sessions.Default(c)
+	if token, ok := session.Get(constants.SessionKeySessionToken).(string); ok {
+		if err := h.sessionService.RevokeSessionByToken(token); err != nil {
+			apierrors.InternalError(c, "Failed to logout")
+			return
+		}
+	}
 	session.Clear()
 	if err := session.Save(); err != nil {
 		apierrors.InternalError(c, "Failed to logout")
@@ -117,10 +222,170 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	c.JSON(http.StatusOK, userDTO)
 }
 
+// ChangePassword replaces the current user's password and signs out their other sessions.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	type ChangePasswordRequest struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+	currentSessionID, _ := middleware.GetSessionID(c)
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.authService.ChangePassword(services.ChangePasswordInput{
+		UserID:           userID,
+		CurrentPassword:  req.CurrentPassword,
+		NewPassword:      req.NewPassword,
+		CurrentSessionID: currentSessionID,
+	}); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully",
+	})
+}
+
+// ForgotPassword sends a password reset token. The response is the same
+// whether or not the username exists.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	type ForgotPasswordRequest struct {
+		Username string `json:"username" binding:"required"`
+	}
+
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), req.Username); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If the account exists, password reset instructions have been sent",
+	})
+}
+
+// ResetPassword sets a new password using a reset token.
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	type ResetPasswordRequest struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset",
+	})
+}
+
+// startUserSession registers a new login session for the authenticated user
+// and replaces any existing session data with it.
+func startUserSession(c *gin.Context, sessionService *services.SessionService, userID uint64) error {
+	_, token, err := sessionService.CreateSession(services.CreateSessionInput{
+		UserID:    userID,
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		return err
+	}
+
+	session := sessions.Default(c)
+	session.Clear()
+	session.Set(constants.ContextKeyUserID, userID)
+	session.Set(constants.SessionKeySessionToken, token)
+	return session.Save()
+}
+
+// startTwoFactorLogin stores a half-authenticated session. It does not set
+// the user ID, so RequireAuth keeps rejecting the session until the second
+// factor is verified.
+func startTwoFactorLogin(c *gin.Context, userID uint64) error {
+	session := sessions.Default(c)
+	session.Clear()
+	session.Set(constants.SessionKeyTwoFactorUserID, userID)
+	session.Set(constants.SessionKeyTwoFactorStartedAt, time.Now().Unix())
+	session.Set(constants.SessionKeyTwoFactorAttempts, 0)
+	return session.Save()
+}
+
+// respondTwoFactorRequired starts a pending login and asks the client for the
+// second factor, to be sent to VerifyTwoFactorLogin.
+func respondTwoFactorRequired(c *gin.Context, userID uint64) {
+	if err := startTwoFactorLogin(c, userID); err != nil {
+		apierrors.InternalError(c, "Failed to save session")
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"two_factor_required": true,
+		"message":             "Two-factor code required",
+	})
+}
+
+// pendingTwoFactorUserID returns the user awaiting a second factor, if the
+// pending login has not expired.
+func pendingTwoFactorUserID(session sessions.Session) (uint64, bool) {
+	userID, ok := session.Get(constants.SessionKeyTwoFactorUserID).(uint64)
+	if !ok {
+		return 0, false
+	}
+	startedAt, ok := session.Get(constants.SessionKeyTwoFactorStartedAt).(int64)
+	if !ok || time.Since(time.Unix(startedAt, 0)) > constants.TwoFactorLoginTimeout {
+		return 0, false
+	}
+	return userID, true
+}
+
+func clearTwoFactorLogin(session sessions.Session) {
+	session.Delete(constants.SessionKeyTwoFactorUserID)
+	session.Delete(constants.SessionKeyTwoFactorStartedAt)
+	session.Delete(constants.SessionKeyTwoFactorAttempts)
+}
+
 func respondAuthError(c *gin.Context, err error) {
+	var lockedErr *services.AccountLockedError
 	switch {
+	case errors.As(err, &lockedErr):
+		retryAfter := int(math.Ceil(lockedErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		apierrors.AccountLocked(c, err.Error())
+	case errors.Is(err, services.ErrUsernameReserved):
+		apierrors.BadRequest(c, "Username is reserved")
 	case errors.Is(err, services.ErrPasswordTooShort):
 		apierrors.BadRequest(c, fmt.Sprintf("Password must be at least %d characters", constants.MinPasswordLength))
+	case errors.Is(err, services.ErrPasswordTooLong):
+		apierrors.BadRequest(c, fmt.Sprintf("Password must be at most %d bytes", constants.MaxPasswordLength))
+	case errors.Is(err, services.ErrPasswordTooCommon):
+		apierrors.BadRequest(c, "Password is too common; choose a less guessable password")
+	case errors.Is(err, services.ErrInvalidResetToken):
+		apierrors.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrIncorrectPassword):
+		apierrors.Forbidden(c, err.Error())
 	case errors.Is(err, services.ErrUsernameTaken):
 		apierrors.Conflict(c, err.Error())
 	case errors.Is(err, services.ErrInvalidCredentials):
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
@@ -14,7 +17,9 @@ import (
 	"github.com/yukikurage/task-management-api/internal/constants"
 	"github.com/yukikurage/task-management-api/internal/database"
 	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/notifier"
 	"github.com/yukikurage/task-management-api/internal/repository"
 	"github.com/yukikurage/task-management-api/internal/services"
 	"gorm.io/driver/sqlite"
@@ -25,6 +30,28 @@ type authTestEnv struct {
 	db          *gorm.DB
 	handler     *AuthHandler
 	authService *services.AuthService
+	notifier    *recordingNotifier
+}
+
+// recordingNotifier captures notifications instead of delivering them.
+type recordingNotifier struct {
+	resets []notifier.PasswordResetNotification
+}
+
+func (n *recordingNotifier) SendPasswordReset(_ context.Context, notification notifier.PasswordResetNotification) error {
+	n.resets = append(n.resets, notification)
+	return nil
+}
+
+// newTestAuthService wires an AuthService with an in-memory login throttle.
+func newTestAuthService(db *gorm.DB, sessionService *services.SessionService, accountNotifier notifier.Notifier) *services.AuthService {
+	return services.NewAuthService(
+		repository.NewUserRepository(db),
+		repository.NewPasswordResetRepository(db),
+		services.NewLoginThrottle(repository.NewMemoryLoginAttemptStore()),
+		sessionService,
+		accountNotifier,
+	)
 }
 
 func setupAuthTestEnv(t *testing.T) authTestEnv {
@@ -37,14 +64,21 @@ func setupAuthTestEnv(t *testing.T) authTestEnv {
 		&models.User{},
 		&models.Organization{},
 		&models.OrganizationMember{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.UserSession{},
+		&models.PasswordResetToken{},
 	)
 	require.NoError(t, err)
 
 	database.SetDB(db)
 
 	userRepo := repository.NewUserRepository(db)
-	authService := services.NewAuthService(userRepo)
-	handler := NewAuthHandler(authService)
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db))
+	recorder := &recordingNotifier{}
+	authService := newTestAuthService(db, sessionService, recorder)
+	twoFactorService := services.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo)
+	handler := NewAuthHandler(authService, twoFactorService, sessionService)
 
 	sqlDB, err := db.DB()
 	require.NoError(t, err)
@@ -56,6 +90,7 @@ func setupAuthTestEnv(t *testing.T) authTestEnv {
 		db:          db,
 		handler:     handler,
 		authService: authService,
+		notifier:    recorder,
 	}
 }
 
@@ -145,3 +180,106 @@ func TestAuthHandler_GetCurrentUser(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
 	require.Equal(t, user.Username, response.Username)
 }
+
+func TestAuthHandler_LoginLockout(t *testing.T) {
+	env := setupAuthTestEnv(t)
+
+	_, err := env.authService.Signup(services.SignupInput{
+		Username: "target",
+		Password: "supersecret",
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	store := cookie.NewStore([]byte("secret"))
+	r.Use(sessions.Sessions(constants.SessionCookieName, store))
+	r.POST("/api/auth/login", env.handler.Login)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]string{
+			"username": "target",
+			"password": password,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < constants.LoginMaxFailuresPerUsername; i++ {
+		require.Equal(t, http.StatusUnauthorized, login("wrong-password").Code)
+	}
+
+	// Even the correct password is rejected while the account is locked
+	w := login("supersecret")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var response apierrors.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, apierrors.ErrCodeAccountLocked, response.Code)
+}
+
+func TestAuthHandler_LoginLockoutIgnoresForwardedFor(t *testing.T) {
+	env := setupAuthTestEnv(t)
+
+	// Without configured proxies X-Forwarded-For is ignored, as in the server
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(nil))
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.POST("/api/auth/login", env.handler.Login)
+
+	login := func(username, forwardedFor string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]string{"username": username, "password": "wrong-password"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.RemoteAddr = "203.0.113.5:4321"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Spraying usernames with a new forwarded address each time still counts
+	// against the connecting address
+	for i := 0; i < constants.LoginMaxFailuresPerIP; i++ {
+		w := login(fmt.Sprintf("spray-%d", i), fmt.Sprintf("198.51.100.%d", i))
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	require.Equal(t, http.StatusTooManyRequests, login("someone-else", "192.0.2.99").Code)
+}
+
+func TestLoginThrottle_ExponentialBackoff(t *testing.T) {
+	throttle := services.NewLoginThrottle(repository.NewMemoryLoginAttemptStore())
+
+	lockedFor := func() time.Duration {
+		var lockedErr *services.AccountLockedError
+		require.ErrorAs(t, throttle.Check("victim", "192.0.2.1"), &lockedErr)
+		return lockedErr.RetryAfter
+	}
+
+	for i := 0; i < constants.LoginMaxFailuresPerUsername; i++ {
+		require.NoError(t, throttle.Check("victim", "192.0.2.1"))
+		require.NoError(t, throttle.RecordFailure("victim", "192.0.2.1"))
+	}
+	first := lockedFor()
+	require.InDelta(t, constants.LoginLockoutBaseDuration.Seconds(), first.Seconds(), 1)
+
+	require.NoError(t, throttle.RecordFailure("victim", "192.0.2.1"))
+	require.InDelta(t, (2 * constants.LoginLockoutBaseDuration).Seconds(), lockedFor().Seconds(), 1)
+
+	// Usernames are matched case-insensitively; other users from other addresses are unaffected
+	require.Error(t, throttle.Check("VICTIM", "198.51.100.7"))
+	require.NoError(t, throttle.Check("someone-else", "198.51.100.7"))
+
+	// Failures across many usernames from one address lock the address
+	for i := 0; i < constants.LoginMaxFailuresPerIP; i++ {
+		require.NoError(t, throttle.RecordFailure(fmt.Sprintf("spray-%d", i), "203.0.113.5"))
+	}
+	require.ErrorIs(t, throttle.Check("someone-else", "203.0.113.5"), services.ErrAccountLocked)
+}
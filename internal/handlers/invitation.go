@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// InvitationHandler handles invitations of specific users to organizations.
+type InvitationHandler struct {
+	invitationService *services.InvitationService
+}
+
+// NewInvitationHandler creates a new InvitationHandler.
+func NewInvitationHandler(invitationService *services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// CreateInvitation invites an existing user to the organization by username.
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	type CreateInvitationRequest struct {
+		Username string                  `json:"username" binding:"required"`
+		Role     models.OrganizationRole `json:"role"`
+	}
+
+	var req CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	invitation, err := h.invitationService.CreateInvitation(services.CreateInvitationInput{
+		OrganizationID: org.ID,
+		InviterID:      userID,
+		Username:       req.Username,
+		Role:           req.Role,
+	})
+	if err != nil {
+		respondInvitationError(c, err, "Failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToInvitationDTO(*invitation, time.Now()))
+}
+
+// ListOrganizationInvitations returns the invitations the organization has sent.
+func (h *InvitationHandler) ListOrganizationInvitations(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	invitations, err := h.invitationService.ListOrganizationInvitations(org.ID)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": toInvitationDTOs(invitations),
+	})
+}
+
+// CancelInvitation withdraws a pending invitation of the organization.
+func (h *InvitationHandler) CancelInvitation(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitation_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid invitation ID")
+		return
+	}
+
+	if err := h.invitationService.CancelInvitation(org.ID, invitationID); err != nil {
+		respondInvitationError(c, err, "Failed to cancel invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation canceled successfully",
+	})
+}
+
+// ListInvitations returns the invitations the current user received.
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	status := models.InvitationStatus(c.Query("status"))
+	switch status {
+	case "",
+		models.InvitationStatusPending,
+		models.InvitationStatusAccepted,
+		models.InvitationStatusDeclined,
+		models.InvitationStatusCanceled,
+		models.InvitationStatusExpired:
+	default:
+		apierrors.BadRequest(c, "Invalid status filter")
+		return
+	}
+
+	invitations, err := h.invitationService.ListInbox(userID, status)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to list invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": toInvitationDTOs(invitations),
+	})
+}
+
+// AcceptInvitation joins the organization the current user was invited to.
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	h.answerInvitation(c, h.invitationService.WithRequestMeta(requestMeta(c)).AcceptInvitation, "Invitation accepted successfully")
+}
+
+// DeclineInvitation turns down an invitation of the current user.
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	h.answerInvitation(c, h.invitationService.DeclineInvitation, "Invitation declined successfully")
+}
+
+func (h *InvitationHandler) answerInvitation(
+	c *gin.Context,
+	answer func(userID, invitationID uint64) (*models.OrganizationInvitation, error),
+	message string,
+) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitation_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid invitation ID")
+		return
+	}
+
+	invitation, err := answer(userID, invitationID)
+	if err != nil {
+		respondInvitationError(c, err, "Failed to answer invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    message,
+		"invitation": dto.ToInvitationDTO(*invitation, time.Now()),
+	})
+}
+
+func toInvitationDTOs(invitations []models.OrganizationInvitation) []dto.InvitationDTO {
+	now := time.Now()
+	invitationDTOs := make([]dto.InvitationDTO, len(invitations))
+	for i, invitation := range invitations {
+		invitationDTOs[i] = dto.ToInvitationDTO(invitation, now)
+	}
+	return invitationDTOs
+}
+
+// respondInvitationError maps domain errors to API responses.
+func respondInvitationError(c *gin.Context, err error, defaultMessage string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidInviteRole):
+		apierrors.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrInsufficientRole):
+		apierrors.Forbidden(c, err.Error())
+	case errors.Is(err, services.ErrInvitationNotFound),
+		errors.Is(err, services.ErrInviteeNotFound),
+		errors.Is(err, services.ErrOrganizationMemberNotFound):
+		apierrors.NotFound(c, err.Error())
+	case errors.Is(err, services.ErrAlreadyOrganizationMember),
+		errors.Is(err, services.ErrInvitationAlreadyPending),
+		errors.Is(err, services.ErrInvitationAnswered),
+		errors.Is(err, services.ErrOrganizationArchived):
+		apierrors.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrInvitationExpired):
+		apierrors.Gone(c, err.Error())
+	case errors.Is(err, services.ErrMemberQuotaExceeded):
+		apierrors.QuotaExceeded(c, err.Error())
+	default:
+		apierrors.InternalError(c, defaultMessage)
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// invitationTestRouter serves the invitation routes, authenticating requests
+// as the user ID in the X-Test-User header.
+func invitationTestRouter(env organizationTestEnv) *gin.Engine {
+	handler := NewInvitationHandler(services.NewInvitationService(
+		repository.NewInvitationRepository(env.db),
+		repository.NewOrganizationRepository(env.db),
+		repository.NewUserRepository(env.db),
+		env.auditService,
+	))
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id, err := strconv.ParseUint(c.GetHeader("X-Test-User"), 10, 64); err == nil {
+			c.Set(constants.ContextKeyUserID, id)
+		}
+	})
+
+	org := r.Group("/api/organizations/:id", middleware.RequireOrganizationAccess(), middleware.RequireOrganizationPermission(policy.ActionManageInvites))
+	org.GET("/invitations", handler.ListOrganizationInvitations)
+	org.POST("/invitations", handler.CreateInvitation)
+	org.DELETE("/invitations/:invitation_id", handler.CancelInvitation)
+
+	r.GET("/api/invitations", handler.ListInvitations)
+	r.POST("/api/invitations/:invitation_id/accept", handler.AcceptInvitation)
+	r.POST("/api/invitations/:invitation_id/decline", handler.DeclineInvitation)
+	return r
+}
+
+func TestInvitationHandler_AcceptAndDecline(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := invitationTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	admin := createTestOrganizationUser(t, env.db, "admin")
+	alice := createTestOrganizationUser(t, env.db, "alice")
+	bob := createTestOrganizationUser(t, env.db, "bob")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(admin.ID, org.InviteCode)
+	require.NoError(t, err)
+	_, err = env.orgService.ChangeMemberRole(org.ID, owner.ID, admin.ID, models.RoleAdmin)
+	require.NoError(t, err)
+
+	invitationsPath := fmt.Sprintf("/api/organizations/%d/invitations", org.ID)
+	invite := func(inviterID uint64, username, role string) *dto.InvitationDTO {
+		t.Helper()
+		w := orgRoleRequest(t, r, http.MethodPost, invitationsPath, inviterID, map[string]string{"username": username, "role": role})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var invitation dto.InvitationDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &invitation))
+		return &invitation
+	}
+	answer := func(userID, invitationID uint64, action string) int {
+		return orgRoleRequest(t, r, http.MethodPost, fmt.Sprintf("/api/invitations/%d/%s", invitationID, action), userID, nil).Code
+	}
+
+	w := orgRoleRequest(t, r, http.MethodPost, invitationsPath, admin.ID, map[string]string{"username": "alice", "role": "admin"})
+	require.Equal(t, http.StatusForbidden, w.Code, "admins cannot invite admins")
+	w = orgRoleRequest(t, r, http.MethodPost, invitationsPath, admin.ID, map[string]string{"username": "nobody"})
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = orgRoleRequest(t, r, http.MethodPost, invitationsPath, admin.ID, map[string]string{"username": "owner"})
+	require.Equal(t, http.StatusConflict, w.Code, "members cannot be invited again")
+
+	forAlice := invite(admin.ID, "alice", "guest")
+	require.Equal(t, models.InvitationStatusPending, forAlice.Status)
+	w = orgRoleRequest(t, r, http.MethodPost, invitationsPath, owner.ID, map[string]string{"username": "alice"})
+	require.Equal(t, http.StatusConflict, w.Code, "only one pending invitation per user")
+
+	// The invitee sees the invitation in their inbox
+	w = orgRoleRequest(t, r, http.MethodGet, "/api/invitations?status=pending", alice.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var inbox map[string][]dto.InvitationDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &inbox))
+	require.Len(t, inbox["invitations"], 1)
+	require.Equal(t, "Org", inbox["invitations"][0].Organization.Name)
+	require.Equal(t, "admin", inbox["invitations"][0].Inviter.Username)
+
+	// Only the invitee can answer
+	require.Equal(t, http.StatusNotFound, answer(bob.ID, forAlice.ID, "accept"))
+	require.Equal(t, http.StatusOK, answer(alice.ID, forAlice.ID, "accept"))
+	require.Equal(t, http.StatusConflict, answer(alice.ID, forAlice.ID, "decline"))
+
+	joined, err := repository.NewOrganizationRepository(env.db).FindMember(org.ID, alice.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleGuest, joined.Role)
+
+	// Declining does not add the member and allows a new invitation later
+	forBob := invite(owner.ID, "bob", "admin")
+	require.Equal(t, http.StatusOK, answer(bob.ID, forBob.ID, "decline"))
+	_, err = repository.NewOrganizationRepository(env.db).FindMember(org.ID, bob.ID)
+	require.Error(t, err)
+
+	again := invite(owner.ID, "bob", "member")
+	require.NoError(t, env.db.Model(&models.OrganizationInvitation{}).
+		Where("id = ?", again.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	require.Equal(t, http.StatusGone, answer(bob.ID, again.ID, "accept"))
+
+	w = orgRoleRequest(t, r, http.MethodGet, "/api/invitations?status=expired", bob.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &inbox))
+	require.Len(t, inbox["invitations"], 1)
+
+	// Canceled invitations can no longer be accepted
+	third := invite(owner.ID, "bob", "member")
+	w = orgRoleRequest(t, r, http.MethodDelete, fmt.Sprintf("%s/%d", invitationsPath, third.ID), admin.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, http.StatusConflict, answer(bob.ID, third.ID, "accept"))
+
+	w = orgRoleRequest(t, r, http.MethodGet, invitationsPath, owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var sent map[string][]dto.InvitationDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sent))
+	require.Len(t, sent["invitations"], 4)
+	statuses := map[models.InvitationStatus]int{}
+	for _, invitation := range sent["invitations"] {
+		statuses[invitation.Status]++
+	}
+	require.Equal(t, map[models.InvitationStatus]int{
+		models.InvitationStatusAccepted: 1,
+		models.InvitationStatusDeclined: 1,
+		models.InvitationStatusExpired:  1,
+		models.InvitationStatusCanceled: 1,
+	}, statuses)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// OIDCHandler handles OpenID Connect single sign-on.
+type OIDCHandler struct {
+	oidcService      *services.OIDCService
+	twoFactorService *services.TwoFactorService
+	sessionService   *services.SessionService
+}
+
+// NewOIDCHandler creates a new OIDCHandler.
+func NewOIDCHandler(oidcService *services.OIDCService, twoFactorService *services.TwoFactorService, sessionService *services.SessionService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService:      oidcService,
+		twoFactorService: twoFactorService,
+		sessionService:   sessionService,
+	}
+}
+
+// Login starts the authorization code flow by redirecting to the identity provider.
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authRequest, err := h.oidcService.BeginLogin(c.Request.Context())
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Set(constants.SessionKeyOIDCState, authRequest.State)
+	session.Set(constants.SessionKeyOIDCNonce, authRequest.Nonce)
+	session.Set(constants.SessionKeyOIDCVerifier, authRequest.CodeVerifier)
+	if err := session.Save(); err != nil {
+		apierrors.InternalError(c, "Failed to save session")
+		return
+	}
+
+	c.Redirect(http.StatusFound, authRequest.URL)
+}
+
+// Callback completes the flow, signing in (and if needed provisioning) the user.
+// Users with two-factor authentication still have to verify a code, as with
+// a password login. If a user is already signed in, the external identity is
+// linked to them instead.
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		apierrors.Unauthorized(c, "Identity provider returned an error: "+providerErr)
+		return
+	}
+
+	session := sessions.Default(c)
+	expectedState, _ := session.Get(constants.SessionKeyOIDCState).(string)
+	nonce, _ := session.Get(constants.SessionKeyOIDCNonce).(string)
+	verifier, _ := session.Get(constants.SessionKeyOIDCVerifier).(string)
+
+	// The pending values are single-use regardless of the outcome
+	session.Delete(constants.SessionKeyOIDCState)
+	session.Delete(constants.SessionKeyOIDCNonce)
+	session.Delete(constants.SessionKeyOIDCVerifier)
+	if err := session.Save(); err != nil {
+		apierrors.InternalError(c, "Failed to save session")
+		return
+	}
+
+	input := services.OIDCCallbackInput{
+		Code:          c.Query("code"),
+		State:         c.Query("state"),
+		ExpectedState: expectedState,
+		Nonce:         nonce,
+		CodeVerifier:  verifier,
+	}
+	if token, ok := session.Get(constants.SessionKeySessionToken).(string); ok {
+		if current, err := h.sessionService.FindActiveSession(token); err == nil {
+			input.CurrentUserID = &current.UserID
+		}
+	}
+
+	user, err := h.oidcService.CompleteLogin(c.Request.Context(), input)
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	// Linking happens in a session that already passed the second factor
+	if input.CurrentUserID == nil {
+		twoFactorEnabled, err := h.twoFactorService.IsEnabled(user.ID)
+		if err != nil {
+			respondAuthError(c, err)
+			return
+		}
+		if twoFactorEnabled {
+			respondTwoFactorRequired(c, user.ID)
+			return
+		}
+	}
+
+	if err := startUserSession(c, h.sessionService, user.ID); err != nil {
+		apierrors.InternalError(c, "Failed to save session")
+		return
+	}
+
+	userDTO := dto.ToUserDTO(*user)
+	c.JSON(http.StatusOK, userDTO)
+}
+
+func respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrOIDCNotConfigured),
+		errors.Is(err, services.ErrOIDCProviderUnavailable):
+		apierrors.ServiceUnavailable(c, err.Error())
+	case errors.Is(err, services.ErrOIDCInvalidState):
+		apierrors.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrOIDCExchangeFailed),
+		errors.Is(err, services.ErrOIDCInvalidIDToken):
+		apierrors.Unauthorized(c, "Single sign-on failed")
+	case errors.Is(err, services.ErrOIDCIdentityLinked):
+		apierrors.Conflict(c, err.Error())
+	default:
+		respondAuthError(c, err)
+	}
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+	"github.com/yukikurage/task-management-api/internal/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const oidcTestClientID = "task-api"
+
+// stubIdentityProvider is a minimal OpenID provider that issues ID tokens for
+// authorization codes registered by the test.
+type stubIdentityProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]stubAuthorization
+}
+
+type stubAuthorization struct {
+	subject       string
+	username      string
+	nonce         string
+	codeChallenge string
+}
+
+func newStubIdentityProvider(t *testing.T) *stubIdentityProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdentityProvider{t: t, key: key, codes: map[string]stubAuthorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeStubJSON(w, map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeStubJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key:       &key.PublicKey,
+			KeyID:     "test-key",
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}}})
+	})
+	mux.HandleFunc("/token", idp.handleToken)
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// authorize simulates the user approving the request and returns the code.
+func (idp *stubIdentityProvider) authorize(authURL, subject, username string) string {
+	parsed, err := url.Parse(authURL)
+	require.NoError(idp.t, err)
+	query := parsed.Query()
+	require.Equal(idp.t, "S256", query.Get("code_challenge_method"))
+
+	code := "code-" + subject + "-" + query.Get("state")[:8]
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.codes[code] = stubAuthorization{
+		subject:       subject,
+		username:      username,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	return code
+}
+
+func (idp *stubIdentityProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	require.NoError(idp.t, r.ParseForm())
+
+	idp.mu.Lock()
+	authz, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+
+	verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifierHash[:]) != authz.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeStubJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: idp.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test-key"),
+	)
+	require.NoError(idp.t, err)
+
+	now := time.Now()
+	idToken, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   idp.server.URL,
+		Subject:  authz.subject,
+		Audience: jwt.Audience{oidcTestClientID},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}).Claims(map[string]interface{}{
+		"nonce":              authz.nonce,
+		"email":              authz.username + "@example.com",
+		"preferred_username": authz.username,
+	}).Serialize()
+	require.NoError(idp.t, err)
+
+	writeStubJSON(w, map[string]interface{}{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func writeStubJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func setupOIDCTestEnv(t *testing.T) (*gorm.DB, *gin.Engine, *stubIdentityProvider) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.UserIdentity{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.UserSession{},
+	)
+	require.NoError(t, err)
+
+	database.SetDB(db)
+
+	idp := newStubIdentityProvider(t)
+	userRepo := repository.NewUserRepository(db)
+	oidcHandler := NewOIDCHandler(services.NewOIDCService(services.OIDCConfig{
+		IssuerURL:    idp.server.URL,
+		ClientID:     oidcTestClientID,
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/api/auth/oidc/callback",
+	}, userRepo),
+		services.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo),
+		services.NewSessionService(repository.NewSessionRepository(db)))
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.GET("/api/auth/oidc/login", oidcHandler.Login)
+	r.GET("/api/auth/oidc/callback", oidcHandler.Callback)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return db, r, idp
+}
+
+// oidcLogin runs the full redirect flow and returns the callback response.
+func oidcLogin(t *testing.T, r *gin.Engine, idp *stubIdentityProvider, subject, username string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+
+	authURL := w.Header().Get("Location")
+	code := idp.authorize(authURL, subject, username)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	callback := "/api/auth/oidc/callback?" + url.Values{
+		"code":  {code},
+		"state": {parsed.Query().Get("state")},
+	}.Encode()
+	req = httptest.NewRequest(http.MethodGet, callback, nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOIDCHandler_FirstLoginProvisionsUser(t *testing.T) {
+	db, r, idp := setupOIDCTestEnv(t)
+
+	w := oidcLogin(t, r, idp, "sub-123", "alice")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var user dto.UserDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	require.Equal(t, "alice", user.Username)
+
+	var identity models.UserIdentity
+	require.NoError(t, db.Where("subject = ?", "sub-123").First(&identity).Error)
+	require.Equal(t, user.ID, identity.UserID)
+	require.Equal(t, idp.server.URL, identity.Issuer)
+
+	var memberships int64
+	require.NoError(t, db.Model(&models.OrganizationMember{}).Where("user_id = ?", user.ID).Count(&memberships).Error)
+	require.Equal(t, int64(1), memberships)
+
+	// Logging in again resolves to the same user
+	w = oidcLogin(t, r, idp, "sub-123", "alice")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var again dto.UserDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &again))
+	require.Equal(t, user.ID, again.ID)
+
+	var users int64
+	require.NoError(t, db.Model(&models.User{}).Count(&users).Error)
+	require.Equal(t, int64(1), users)
+}
+
+func TestOIDCHandler_TwoFactorUserNeedsCode(t *testing.T) {
+	db, r, idp := setupOIDCTestEnv(t)
+
+	w := oidcLogin(t, r, idp, "sub-2fa", "alice")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var user dto.UserDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+
+	twoFactorService := services.NewTwoFactorService(repository.NewTwoFactorRepository(db), repository.NewUserRepository(db))
+	enrollment, err := twoFactorService.Enroll(user.ID)
+	require.NoError(t, err)
+	code, err := utils.TOTPCode(enrollment.Secret, utils.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	_, err = twoFactorService.Activate(user.ID, code)
+	require.NoError(t, err)
+
+	var sessionsBefore int64
+	require.NoError(t, db.Model(&models.UserSession{}).Count(&sessionsBefore).Error)
+
+	// Single sign-on only gets as far as the pending second step
+	w = oidcLogin(t, r, idp, "sub-2fa", "alice")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), "two_factor_required")
+
+	var sessionsAfter int64
+	require.NoError(t, db.Model(&models.UserSession{}).Count(&sessionsAfter).Error)
+	require.Equal(t, sessionsBefore, sessionsAfter)
+}
+
+func TestOIDCHandler_UsernameCollisionGetsSuffix(t *testing.T) {
+	db, r, idp := setupOIDCTestEnv(t)
+
+	require.NoError(t, db.Create(&models.User{Username: "alice", PasswordHash: "hashed"}).Error)
+
+	w := oidcLogin(t, r, idp, "sub-456", "alice")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var user dto.UserDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	require.NotEqual(t, "alice", user.Username)
+	require.Contains(t, user.Username, "alice-")
+}
+
+func TestOIDCHandler_CallbackRejectsStateMismatch(t *testing.T) {
+	_, r, idp := setupOIDCTestEnv(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+	code := idp.authorize(w.Header().Get("Location"), "sub-789", "mallory")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback?code="+code+"&state=forged", nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOIDCHandler_NotConfigured(t *testing.T) {
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.GET("/api/auth/oidc/login", NewOIDCHandler(services.NewOIDCService(services.OIDCConfig{}, nil), nil, nil).Login)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yukikurage/task-management-api/internal/constants"
@@ -12,6 +13,7 @@ import (
 	"github.com/yukikurage/task-management-api/internal/middleware"
 	"github.com/yukikurage/task-management-api/internal/models"
 	"github.com/yukikurage/task-management-api/internal/services"
+	"github.com/yukikurage/task-management-api/internal/utils"
 )
 
 // OrganizationHandler handles HTTP requests for organizations.
@@ -113,6 +115,12 @@ func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
 		return
 	}
 
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
 	type UpdateOrgRequest struct {
 		Name string `json:"name" binding:"required"`
 	}
@@ -123,7 +131,7 @@ func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
 		return
 	}
 
-	updatedOrg, err := h.orgService.UpdateOrganizationName(org.ID, req.Name)
+	updatedOrg, err := h.orgService.WithRequestMeta(requestMeta(c)).UpdateOrganizationName(org.ID, userID, req.Name)
 	if err != nil {
 		respondOrganizationError(c, err, "Failed to update organization")
 		return
@@ -141,7 +149,13 @@ func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 		return
 	}
 
-	if err := h.orgService.DeleteOrganization(org.ID); err != nil {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.orgService.WithRequestMeta(requestMeta(c)).DeleteOrganization(org.ID, userID); err != nil {
 		respondOrganizationError(c, err, "Failed to delete organization")
 		return
 	}
@@ -151,6 +165,52 @@ func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
 	})
 }
 
+// ArchiveOrganization makes an organization read-only.
+func (h *OrganizationHandler) ArchiveOrganization(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	archivedOrg, err := h.orgService.WithRequestMeta(requestMeta(c)).ArchiveOrganization(org.ID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to archive organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationDTO(*archivedOrg, true))
+}
+
+// RestoreOrganization makes an archived organization writable again.
+func (h *OrganizationHandler) RestoreOrganization(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	restoredOrg, err := h.orgService.WithRequestMeta(requestMeta(c)).RestoreOrganization(org.ID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to restore organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationDTO(*restoredOrg, true))
+}
+
 // JoinOrganization allows a user to join via invite code.
 func (h *OrganizationHandler) JoinOrganization(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
@@ -169,7 +229,7 @@ func (h *OrganizationHandler) JoinOrganization(c *gin.Context) {
 		return
 	}
 
-	org, err := h.orgService.JoinOrganizationByInvite(userID, req.InviteCode)
+	org, err := h.orgService.WithRequestMeta(requestMeta(c)).JoinOrganizationByInvite(userID, req.InviteCode)
 	if err != nil {
 		respondOrganizationError(c, err, "Failed to join organization")
 		return
@@ -190,7 +250,13 @@ func (h *OrganizationHandler) RegenerateInviteCode(c *gin.Context) {
 		return
 	}
 
-	updatedOrg, err := h.orgService.RegenerateInviteCode(org.ID)
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	updatedOrg, err := h.orgService.WithRequestMeta(requestMeta(c)).RegenerateInviteCode(org.ID, userID)
 	if err != nil {
 		respondOrganizationError(c, err, "Failed to regenerate invite code")
 		return
@@ -200,6 +266,53 @@ func (h *OrganizationHandler) RegenerateInviteCode(c *gin.Context) {
 	c.JSON(http.StatusOK, orgDTO)
 }
 
+// ListMembers returns a page of organization members. Members can be
+// searched by username, filtered by role and sorted by join date.
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	var rolePtr *models.OrganizationRole
+	if roleStr := c.Query("role"); roleStr != "" {
+		role := models.OrganizationRole(roleStr)
+		if !role.IsValid() {
+			apierrors.BadRequest(c, "Invalid role filter")
+			return
+		}
+		rolePtr = &role
+	}
+
+	var newestFirst bool
+	switch c.DefaultQuery("sort", "joined_at") {
+	case "joined_at":
+	case "-joined_at":
+		newestFirst = true
+	default:
+		apierrors.BadRequest(c, "Invalid sort, expected joined_at or -joined_at")
+		return
+	}
+
+	params := utils.GetPaginationParams(c)
+
+	list, err := h.orgService.ListMembers(services.ListMembersInput{
+		OrganizationID: org.ID,
+		Username:       c.Query("search"),
+		Role:           rolePtr,
+		NewestFirst:    newestFirst,
+		Pagination:     params,
+	})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to list members")
+		return
+	}
+
+	response := dto.ToOrganizationMemberListResponse(list.Members, list.OpenAssignedTasks, params.Page, params.Limit, list.Total)
+	c.JSON(http.StatusOK, response)
+}
+
 // RemoveMember removes a member from the organization.
 func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
 	org, ok := getOrganizationFromContext(c)
@@ -221,7 +334,7 @@ func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
 		return
 	}
 
-	if err := h.orgService.RemoveMember(org.ID, userID, targetID); err != nil {
+	if err := h.orgService.WithRequestMeta(requestMeta(c)).RemoveMember(org.ID, userID, targetID); err != nil {
 		respondOrganizationError(c, err, "Failed to remove member")
 		return
 	}
@@ -231,6 +344,509 @@ func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
 	})
 }
 
+// ChangeMemberRole sets the role of an organization member.
+func (h *OrganizationHandler) ChangeMemberRole(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	type ChangeRoleRequest struct {
+		Role models.OrganizationRole `json:"role" binding:"required"`
+	}
+
+	var req ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	member, err := h.orgService.WithRequestMeta(requestMeta(c)).ChangeMemberRole(org.ID, userID, targetID, req.Role)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to change member role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member role updated successfully",
+		"user_id": member.UserID,
+		"role":    member.Role,
+	})
+}
+
+// LeaveOrganization removes the current user from the organization.
+func (h *OrganizationHandler) LeaveOrganization(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.orgService.WithRequestMeta(requestMeta(c)).LeaveOrganization(org.ID, userID); err != nil {
+		respondOrganizationError(c, err, "Failed to leave organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Left organization successfully",
+	})
+}
+
+// TransferOwnership hands the organization to another member.
+func (h *OrganizationHandler) TransferOwnership(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	type TransferOwnershipRequest struct {
+		UserID uint64 `json:"user_id" binding:"required"`
+	}
+
+	var req TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	member, err := h.orgService.WithRequestMeta(requestMeta(c)).TransferOwnership(org.ID, userID, req.UserID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to transfer ownership")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ownership transferred successfully",
+		"user_id": member.UserID,
+		"role":    member.Role,
+	})
+}
+
+// ListInvites returns the organization's invite links.
+func (h *OrganizationHandler) ListInvites(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	invites, err := h.orgService.ListInvites(org.ID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to list invites")
+		return
+	}
+
+	now := time.Now()
+	inviteDTOs := make([]dto.InviteDTO, len(invites))
+	for i, invite := range invites {
+		inviteDTOs[i] = dto.ToInviteDTO(invite, now)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invites": inviteDTOs,
+	})
+}
+
+// CreateInvite creates a named invite link for the organization.
+func (h *OrganizationHandler) CreateInvite(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	type CreateInviteRequest struct {
+		Name           string                  `json:"name" binding:"required,max=100"`
+		Role           models.OrganizationRole `json:"role"`
+		ExpiresInHours *int                    `json:"expires_in_hours"`
+		MaxUses        *int                    `json:"max_uses"`
+	}
+
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	invite, err := h.orgService.CreateInvite(services.CreateInviteInput{
+		OrganizationID: org.ID,
+		ActorID:        userID,
+		Name:           req.Name,
+		Role:           req.Role,
+		ExpiresInHours: req.ExpiresInHours,
+		MaxUses:        req.MaxUses,
+	})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to create invite")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToInviteDTO(*invite, time.Now()))
+}
+
+// GetInvite returns an invite link with the users who joined through it.
+func (h *OrganizationHandler) GetInvite(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	inviteID, err := strconv.ParseUint(c.Param("invite_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid invite ID")
+		return
+	}
+
+	invite, err := h.orgService.GetInvite(org.ID, inviteID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to get invite")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToInviteDetailDTO(*invite, time.Now()))
+}
+
+// RevokeInvite stops an invite link from being used.
+func (h *OrganizationHandler) RevokeInvite(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	inviteID, err := strconv.ParseUint(c.Param("invite_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid invite ID")
+		return
+	}
+
+	if err := h.orgService.RevokeInvite(org.ID, inviteID); err != nil {
+		respondOrganizationError(c, err, "Failed to revoke invite")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invite revoked successfully",
+	})
+}
+
+// GetSettings returns the organization's settings and quotas.
+func (h *OrganizationHandler) GetSettings(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	settings, err := h.orgService.GetSettings(org.ID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to load organization settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationSettingsDTO(*settings))
+}
+
+// UpdateSettings replaces the organization's settings and quotas.
+func (h *OrganizationHandler) UpdateSettings(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	type QuotasRequest struct {
+		MaxMembers          *int `json:"max_members"`
+		MaxTasks            *int `json:"max_tasks"`
+		AIGenerationsPerDay *int `json:"ai_generations_per_day"`
+	}
+	type UpdateSettingsRequest struct {
+		DefaultTaskStatus   models.TaskStatus `json:"default_task_status" binding:"required"`
+		MembersCanInvite    *bool             `json:"members_can_invite" binding:"required"`
+		DefaultDueTime      string            `json:"default_due_time" binding:"required"`
+		Timezone            string            `json:"timezone" binding:"required"`
+		AIGenerationEnabled *bool             `json:"ai_generation_enabled" binding:"required"`
+		EnforceDependencies bool              `json:"enforce_dependencies"`
+		Quotas              QuotasRequest     `json:"quotas"`
+	}
+
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	settings, err := h.orgService.UpdateSettings(org.ID, models.OrganizationSettings{
+		DefaultTaskStatus:   req.DefaultTaskStatus,
+		MembersCanInvite:    *req.MembersCanInvite,
+		DefaultDueTime:      req.DefaultDueTime,
+		Timezone:            req.Timezone,
+		AIGenerationEnabled: *req.AIGenerationEnabled,
+		EnforceDependencies: req.EnforceDependencies,
+		MaxMembers:          req.Quotas.MaxMembers,
+		MaxTasks:            req.Quotas.MaxTasks,
+		AIGenerationsPerDay: req.Quotas.AIGenerationsPerDay,
+	})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update organization settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationSettingsDTO(*settings))
+}
+
+// GetWorkflow returns the organization's task workflow.
+func (h *OrganizationHandler) GetWorkflow(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	workflow, err := h.orgService.GetWorkflow(org.ID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to load workflow")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWorkflowDTO(*workflow))
+}
+
+// UpdateWorkflow replaces the organization's task workflow.
+func (h *OrganizationHandler) UpdateWorkflow(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	type WorkflowStateRequest struct {
+		Key  models.TaskStatus `json:"key" binding:"required"`
+		Name string            `json:"name" binding:"required"`
+		Done bool              `json:"done"`
+	}
+	type WorkflowTransitionRequest struct {
+		From models.TaskStatus `json:"from" binding:"required"`
+		To   models.TaskStatus `json:"to" binding:"required"`
+	}
+	type UpdateWorkflowRequest struct {
+		States      []WorkflowStateRequest      `json:"states" binding:"required,dive"`
+		Transitions []WorkflowTransitionRequest `json:"transitions" binding:"dive"`
+	}
+
+	var req UpdateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	var workflow models.Workflow
+	for _, state := range req.States {
+		workflow.States = append(workflow.States, models.WorkflowState{Key: state.Key, Name: state.Name, Done: state.Done})
+	}
+	for _, transition := range req.Transitions {
+		workflow.Transitions = append(workflow.Transitions, models.WorkflowTransition{FromStatus: transition.From, ToStatus: transition.To})
+	}
+
+	updated, err := h.orgService.UpdateWorkflow(org.ID, workflow)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update workflow")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWorkflowDTO(*updated))
+}
+
+// ListLabels returns the organization's labels ordered by name.
+func (h *OrganizationHandler) ListLabels(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	labels, err := h.orgService.ListLabels(org.ID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to list labels")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToLabelListResponse(labels))
+}
+
+// CreateLabel adds a label to the organization.
+func (h *OrganizationHandler) CreateLabel(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	var req labelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	label, err := h.orgService.CreateLabel(org.ID, services.LabelInput{Name: req.Name, Color: req.Color})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to create label")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToLabelDTO(*label))
+}
+
+// UpdateLabel renames or recolors a label of the organization.
+func (h *OrganizationHandler) UpdateLabel(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	labelID, err := strconv.ParseUint(c.Param("label_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid label ID")
+		return
+	}
+
+	var req labelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	label, err := h.orgService.UpdateLabel(org.ID, labelID, services.LabelInput{Name: req.Name, Color: req.Color})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update label")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToLabelDTO(*label))
+}
+
+// DeleteLabel deletes a label of the organization and removes it from its tasks.
+func (h *OrganizationHandler) DeleteLabel(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	labelID, err := strconv.ParseUint(c.Param("label_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid label ID")
+		return
+	}
+
+	if err := h.orgService.DeleteLabel(org.ID, labelID); err != nil {
+		respondOrganizationError(c, err, "Failed to delete label")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Label deleted successfully",
+	})
+}
+
+// labelRequest is the body of label create and update requests
+type labelRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color" binding:"required"`
+}
+
+// GetPolicy returns the organization's effective permission policy.
+func (h *OrganizationHandler) GetPolicy(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	p, err := h.orgService.GetPolicy(org.ID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to load organization policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationPolicyDTO(p))
+}
+
+// UpdatePolicy replaces the organization's permission overrides.
+func (h *OrganizationHandler) UpdatePolicy(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	type PolicyRuleRequest struct {
+		Action string                  `json:"action" binding:"required"`
+		Role   models.OrganizationRole `json:"role" binding:"required"`
+		Scope  string                  `json:"scope" binding:"required"`
+	}
+	type UpdatePolicyRequest struct {
+		Rules []PolicyRuleRequest `json:"rules" binding:"required,dive"`
+	}
+
+	var req UpdatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	rules := make([]models.OrganizationPolicyRule, len(req.Rules))
+	for i, rule := range req.Rules {
+		rules[i] = models.OrganizationPolicyRule{
+			Action: rule.Action,
+			Role:   rule.Role,
+			Scope:  rule.Scope,
+		}
+	}
+
+	p, err := h.orgService.UpdatePolicy(org.ID, rules)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update organization policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrganizationPolicyDTO(p))
+}
+
 func getOrganizationFromContext(c *gin.Context) (models.Organization, bool) {
 	orgInterface, exists := c.Get(constants.ContextKeyOrganization)
 	if !exists {
@@ -256,14 +872,44 @@ func respondOrganizationError(c *gin.Context, err error, defaultMessage string)
 	case err == nil:
 		return
 	case errors.Is(err, services.ErrInvalidOrganizationName),
-		errors.Is(err, services.ErrCannotRemoveYourself):
+		errors.Is(err, services.ErrCannotRemoveYourself),
+		errors.Is(err, services.ErrCannotTransferToYourself),
+		errors.Is(err, services.ErrInvalidOrganizationRole),
+		errors.Is(err, services.ErrInvalidPolicyRule),
+		errors.Is(err, services.ErrInvalidInviteName),
+		errors.Is(err, services.ErrInvalidInviteRole),
+		errors.Is(err, services.ErrInvalidInviteLifetime),
+		errors.Is(err, services.ErrInvalidInviteMaxUses),
+		errors.Is(err, services.ErrInvalidDefaultTaskStatus),
+		errors.Is(err, services.ErrInvalidDefaultDueTime),
+		errors.Is(err, services.ErrInvalidTimezone),
+		errors.Is(err, services.ErrInvalidQuota),
+		errors.Is(err, services.ErrInvalidWorkflow),
+		errors.Is(err, services.ErrInvalidLabelName),
+		errors.Is(err, services.ErrInvalidLabelColor):
 		apierrors.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrMemberQuotaExceeded):
+		apierrors.QuotaExceeded(c, err.Error())
+	case errors.Is(err, services.ErrInsufficientRole):
+		apierrors.Forbidden(c, err.Error())
+	case errors.Is(err, services.ErrLastOwner),
+		errors.Is(err, services.ErrOrganizationArchived),
+		errors.Is(err, services.ErrWorkflowStateInUse),
+		errors.Is(err, services.ErrLabelNameTaken),
+		errors.Is(err, services.ErrLabelLimitReached):
+		apierrors.Conflict(c, err.Error())
 	case errors.Is(err, services.ErrAlreadyOrganizationMember):
 		apierrors.Conflict(c, err.Error())
 	case errors.Is(err, services.ErrOrganizationNotFound),
 		errors.Is(err, services.ErrOrganizationMemberNotFound),
-		errors.Is(err, services.ErrInvalidInviteCode):
+		errors.Is(err, services.ErrInvalidInviteCode),
+		errors.Is(err, services.ErrInviteNotFound),
+		errors.Is(err, services.ErrLabelNotFound):
 		apierrors.NotFound(c, err.Error())
+	case errors.Is(err, services.ErrInviteExpired),
+		errors.Is(err, services.ErrInviteRevoked),
+		errors.Is(err, services.ErrInviteUsedUp):
+		apierrors.Gone(c, err.Error())
 	case errors.Is(err, services.ErrInviteCodeGenerationFailed):
 		apierrors.InternalError(c, err.Error())
 	default:
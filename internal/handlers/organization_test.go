@@ -3,16 +3,23 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 	"github.com/yukikurage/task-management-api/internal/constants"
 	"github.com/yukikurage/task-management-api/internal/database"
 	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/middleware"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
 	"github.com/yukikurage/task-management-api/internal/repository"
 	"github.com/yukikurage/task-management-api/internal/services"
 	"gorm.io/driver/sqlite"
@@ -20,9 +27,10 @@ import (
 )
 
 type organizationTestEnv struct {
-	db         *gorm.DB
-	handler    *OrganizationHandler
-	orgService *services.OrganizationService
+	db           *gorm.DB
+	handler      *OrganizationHandler
+	orgService   *services.OrganizationService
+	auditService *services.AuditService
 }
 
 func setupOrganizationTestEnv(t *testing.T) organizationTestEnv {
@@ -35,15 +43,28 @@ func setupOrganizationTestEnv(t *testing.T) organizationTestEnv {
 		&models.User{},
 		&models.Organization{},
 		&models.OrganizationMember{},
+		&models.OrganizationPolicyRule{},
+		&models.OrganizationInvite{},
+		&models.OrganizationInviteUse{},
+		&models.OrganizationInvitation{},
+		&models.OrganizationSettings{},
+		&models.OrganizationAIUsage{},
+		&models.WorkflowState{},
+		&models.WorkflowTransition{},
+		&models.Label{},
+		&models.TaskLabel{},
+		&models.TaskDependency{},
 		&models.Task{},
 		&models.TaskAssignment{},
+		&models.AuditLogEntry{},
 	)
 	require.NoError(t, err)
 
 	database.SetDB(db)
 
 	orgRepo := repository.NewOrganizationRepository(db)
-	orgService := services.NewOrganizationService(orgRepo)
+	auditService := services.NewAuditService(repository.NewAuditLogRepository(db))
+	orgService := services.NewOrganizationService(orgRepo, repository.NewInviteRepository(db), auditService)
 	handler := NewOrganizationHandler(orgService)
 
 	sqlDB, err := db.DB()
@@ -53,9 +74,10 @@ func setupOrganizationTestEnv(t *testing.T) organizationTestEnv {
 	})
 
 	return organizationTestEnv{
-		db:         db,
-		handler:    handler,
-		orgService: orgService,
+		db:           db,
+		handler:      handler,
+		orgService:   orgService,
+		auditService: auditService,
 	}
 }
 
@@ -143,3 +165,699 @@ func TestOrganizationHandler_JoinOrganization_InvalidCode(t *testing.T) {
 
 	require.Equal(t, http.StatusNotFound, w.Code)
 }
+
+// orgRoleTestRouter serves the organization member routes, authenticating
+// requests as the user ID in the X-Test-User header.
+func orgRoleTestRouter(env organizationTestEnv) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id, err := strconv.ParseUint(c.GetHeader("X-Test-User"), 10, 64); err == nil {
+			c.Set(constants.ContextKeyUserID, id)
+		}
+	})
+
+	r.POST("/api/organizations/join", env.handler.JoinOrganization)
+	org := r.Group("/api/organizations/:id", middleware.RequireOrganizationAccess())
+	org.DELETE("", middleware.RequireOrganizationPermission(policy.ActionOrganizationDelete), env.handler.DeleteOrganization)
+	org.POST("/archive", middleware.RequireOrganizationPermission(policy.ActionOrganizationArchive), env.handler.ArchiveOrganization)
+	org.POST("/restore", middleware.RequireOrganizationPermission(policy.ActionOrganizationArchive), env.handler.RestoreOrganization)
+	org.GET("/members", env.handler.ListMembers)
+	org.GET("/invites", middleware.RequireOrganizationPermission(policy.ActionManageInvites), env.handler.ListInvites)
+	org.GET("/invites/:invite_id", middleware.RequireOrganizationPermission(policy.ActionManageInvites), env.handler.GetInvite)
+	org.GET("/policy", env.handler.GetPolicy)
+	org.GET("/settings", env.handler.GetSettings)
+	org.GET("/workflow", env.handler.GetWorkflow)
+	org.GET("/labels", env.handler.ListLabels)
+
+	active := org.Group("", middleware.RequireActiveOrganization())
+	active.PUT("", middleware.RequireOrganizationPermission(policy.ActionOrganizationUpdate), env.handler.UpdateOrganization)
+	active.POST("/regenerate-code", middleware.RequireOrganizationPermission(policy.ActionManageInvites), env.handler.RegenerateInviteCode)
+	active.DELETE("/members/:user_id", middleware.RequireOrganizationPermission(policy.ActionRemoveMembers), env.handler.RemoveMember)
+	active.PUT("/members/:user_id/role", middleware.RequireOrganizationPermission(policy.ActionChangeRoles), env.handler.ChangeMemberRole)
+	active.POST("/transfer-ownership", middleware.RequireOrganizationPermission(policy.ActionTransferOwnership), env.handler.TransferOwnership)
+	active.POST("/leave", env.handler.LeaveOrganization)
+	active.POST("/invites", env.handler.CreateInvite)
+	active.DELETE("/invites/:invite_id", middleware.RequireOrganizationPermission(policy.ActionManageInvites), env.handler.RevokeInvite)
+	active.PUT("/policy", middleware.RequireOrganizationPermission(policy.ActionManagePolicy), env.handler.UpdatePolicy)
+	active.PUT("/settings", middleware.RequireOrganizationPermission(policy.ActionManageSettings), env.handler.UpdateSettings)
+	active.PUT("/workflow", middleware.RequireOrganizationPermission(policy.ActionManageWorkflow), env.handler.UpdateWorkflow)
+	active.POST("/labels", middleware.RequireOrganizationPermission(policy.ActionManageLabels), env.handler.CreateLabel)
+	active.PUT("/labels/:label_id", middleware.RequireOrganizationPermission(policy.ActionManageLabels), env.handler.UpdateLabel)
+	active.DELETE("/labels/:label_id", middleware.RequireOrganizationPermission(policy.ActionManageLabels), env.handler.DeleteLabel)
+	return r
+}
+
+func orgRoleRequest(t *testing.T, r *gin.Engine, method, path string, userID uint64, payload interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	if payload != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+	}
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", strconv.FormatUint(userID, 10))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrganizationHandler_ChangeMemberRole(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	member := createTestOrganizationUser(t, env.db, "member")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(member.ID, org.InviteCode)
+	require.NoError(t, err)
+
+	rolePath := func(userID uint64) string {
+		return fmt.Sprintf("/api/organizations/%d/members/%d/role", org.ID, userID)
+	}
+
+	w := orgRoleRequest(t, r, http.MethodPut, rolePath(member.ID), owner.ID, map[string]string{"role": "superuser"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPut, rolePath(member.ID), owner.ID, map[string]string{"role": "admin"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	found, err := repository.NewOrganizationRepository(env.db).FindMember(org.ID, member.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleAdmin, found.Role)
+
+	// Admins cannot change roles
+	w = orgRoleRequest(t, r, http.MethodPut, rolePath(owner.ID), member.ID, map[string]string{"role": "member"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// The last owner cannot step down
+	w = orgRoleRequest(t, r, http.MethodPut, rolePath(owner.ID), owner.ID, map[string]string{"role": "admin"})
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestOrganizationHandler_AdminManagesMembers(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	admin := createTestOrganizationUser(t, env.db, "admin")
+	member := createTestOrganizationUser(t, env.db, "member")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	for _, u := range []*models.User{admin, member} {
+		_, err = env.orgService.JoinOrganizationByInvite(u.ID, org.InviteCode)
+		require.NoError(t, err)
+	}
+	_, err = env.orgService.ChangeMemberRole(org.ID, owner.ID, admin.ID, models.RoleAdmin)
+	require.NoError(t, err)
+
+	orgPath := fmt.Sprintf("/api/organizations/%d", org.ID)
+
+	// Plain members cannot manage invites
+	w := orgRoleRequest(t, r, http.MethodPost, orgPath+"/regenerate-code", member.ID, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/regenerate-code", admin.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodDelete, fmt.Sprintf("%s/members/%d", orgPath, owner.ID), admin.ID, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodDelete, fmt.Sprintf("%s/members/%d", orgPath, member.ID), admin.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodDelete, orgPath, admin.ID, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestOrganizationHandler_LeaveAndTransferOwnership(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	member := createTestOrganizationUser(t, env.db, "member")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(member.ID, org.InviteCode)
+	require.NoError(t, err)
+
+	task := &models.Task{Title: "Shared", CreatorID: owner.ID, OrganizationID: org.ID}
+	require.NoError(t, env.db.Create(task).Error)
+	require.NoError(t, env.db.Create(&models.TaskAssignment{TaskID: task.ID, UserID: owner.ID}).Error)
+
+	orgPath := fmt.Sprintf("/api/organizations/%d", org.ID)
+
+	// The only owner has to hand the organization off first
+	w := orgRoleRequest(t, r, http.MethodPost, orgPath+"/leave", owner.ID, nil)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/transfer-ownership", member.ID, map[string]uint64{"user_id": member.ID})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/transfer-ownership", owner.ID, map[string]uint64{"user_id": owner.ID})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/transfer-ownership", owner.ID, map[string]uint64{"user_id": member.ID})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	orgRepo := repository.NewOrganizationRepository(env.db)
+	previous, err := orgRepo.FindMember(org.ID, owner.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleAdmin, previous.Role)
+	current, err := orgRepo.FindMember(org.ID, member.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleOwner, current.Role)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/leave", owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	_, err = orgRepo.FindMember(org.ID, owner.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	var assignments int64
+	require.NoError(t, env.db.Model(&models.TaskAssignment{}).Where("user_id = ?", owner.ID).Count(&assignments).Error)
+	require.Zero(t, assignments)
+
+	// Former members no longer see the organization
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/leave", owner.ID, nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOrganizationHandler_ListMembers(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+
+	joinedAt := time.Now()
+	users := map[string]*models.User{}
+	for i, name := range []string{"alice", "Alicia", "bob", "al_x"} {
+		users[name] = createTestOrganizationUser(t, env.db, name)
+		role := models.RoleMember
+		if name == "bob" {
+			role = models.RoleGuest
+		}
+		require.NoError(t, env.db.Create(&models.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         users[name].ID,
+			Role:           role,
+			JoinedAt:       joinedAt.Add(time.Duration(i+1) * time.Minute),
+		}).Error)
+	}
+
+	// alice has one open task; done and deleted tasks are not counted
+	taskRepo := repository.NewTaskRepository(env.db)
+	assignAlice := func(status models.TaskStatus) *models.Task {
+		task := &models.Task{Title: "Task", Status: status, CreatorID: owner.ID, OrganizationID: org.ID}
+		require.NoError(t, taskRepo.Create(task, nil))
+		require.NoError(t, taskRepo.AssignUsers(task.ID, []uint64{users["alice"].ID}))
+		return task
+	}
+	assignAlice(models.TaskStatusTodo)
+	assignAlice(models.TaskStatusDone)
+	require.NoError(t, taskRepo.Delete(assignAlice(models.TaskStatusTodo).ID))
+
+	list := func(query string) dto.OrganizationMemberListResponse {
+		t.Helper()
+		w := orgRoleRequest(t, r, http.MethodGet, fmt.Sprintf("/api/organizations/%d/members%s", org.ID, query), owner.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dto.OrganizationMemberListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	usernames := func(response dto.OrganizationMemberListResponse) []string {
+		names := make([]string, len(response.Members))
+		for i, member := range response.Members {
+			names[i] = member.User.Username
+		}
+		return names
+	}
+
+	all := list("")
+	require.Equal(t, int64(5), all.TotalCount)
+	require.Equal(t, []string{"owner", "alice", "Alicia", "bob", "al_x"}, usernames(all))
+	require.Equal(t, int64(1), all.Members[1].OpenAssignedTasks)
+	require.Zero(t, all.Members[0].OpenAssignedTasks)
+	require.False(t, all.Members[1].RegisteredAt.IsZero())
+
+	page := list("?sort=-joined_at&limit=2&page=2")
+	require.Equal(t, []string{"Alicia", "alice"}, usernames(page))
+	require.Equal(t, 3, page.TotalPages)
+
+	// Search ignores case and treats wildcards literally
+	require.Equal(t, []string{"alice", "Alicia"}, usernames(list("?search=ALI")))
+	require.Equal(t, []string{"al_x"}, usernames(list("?search=l_")))
+	require.Equal(t, []string{"bob"}, usernames(list("?role=guest")))
+	require.Empty(t, usernames(list("?role=guest&search=ali")))
+
+	for _, query := range []string{"?role=superuser", "?sort=username"} {
+		w := orgRoleRequest(t, r, http.MethodGet, fmt.Sprintf("/api/organizations/%d/members%s", org.ID, query), owner.ID, nil)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	// Only members can list members
+	outsider := createTestOrganizationUser(t, env.db, "outsider")
+	w := orgRoleRequest(t, r, http.MethodGet, fmt.Sprintf("/api/organizations/%d/members", org.ID), outsider.ID, nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOrganizationHandler_ArchiveAndRestore(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	member := createTestOrganizationUser(t, env.db, "member")
+	newcomer := createTestOrganizationUser(t, env.db, "newcomer")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(member.ID, org.InviteCode)
+	require.NoError(t, err)
+
+	orgPath := fmt.Sprintf("/api/organizations/%d", org.ID)
+
+	w := orgRoleRequest(t, r, http.MethodPost, orgPath+"/archive", member.ID, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/archive", owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var archived dto.OrganizationDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &archived))
+	require.NotNil(t, archived.ArchivedAt)
+
+	// Archived organizations are read-only and closed to new members
+	w = orgRoleRequest(t, r, http.MethodPut, orgPath, owner.ID, map[string]string{"name": "Renamed"})
+	require.Equal(t, http.StatusConflict, w.Code)
+	w = orgRoleRequest(t, r, http.MethodPost, "/api/organizations/join", newcomer.ID, map[string]string{"invite_code": org.InviteCode})
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, orgPath+"/restore", owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var restored dto.OrganizationDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	require.Nil(t, restored.ArchivedAt)
+
+	w = orgRoleRequest(t, r, http.MethodPut, orgPath, owner.ID, map[string]string{"name": "Renamed"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestOrganizationHandler_UpdatePolicy(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+	taskService := services.NewTaskService(repository.NewTaskRepository(env.db), repository.NewOrganizationRepository(env.db), nil, env.auditService)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	author := createTestOrganizationUser(t, env.db, "author")
+	editor := createTestOrganizationUser(t, env.db, "editor")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	for _, u := range []*models.User{author, editor} {
+		_, err = env.orgService.JoinOrganizationByInvite(u.ID, org.InviteCode)
+		require.NoError(t, err)
+	}
+
+	task, err := taskService.CreateTask(services.CreateTaskInput{Title: "Draft", OrganizationID: org.ID, CreatorID: author.ID})
+	require.NoError(t, err)
+	title := "Edited"
+	_, err = taskService.UpdateTask(task.ID, services.UpdateTaskInput{ActorID: editor.ID, Title: &title})
+	require.ErrorIs(t, err, services.ErrNotTaskCreator)
+
+	policyPath := fmt.Sprintf("/api/organizations/%d/policy", org.ID)
+	anyMemberMayEdit := map[string]interface{}{
+		"rules": []map[string]string{{"action": "task.update", "role": "member", "scope": "any"}},
+	}
+
+	// Members can read the policy but not change it
+	w := orgRoleRequest(t, r, http.MethodGet, policyPath, editor.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = orgRoleRequest(t, r, http.MethodPut, policyPath, editor.ID, anyMemberMayEdit)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPut, policyPath, owner.ID, map[string]interface{}{
+		"rules": []map[string]string{{"action": "organization.delete", "role": "member", "scope": "any"}},
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPut, policyPath, owner.ID, anyMemberMayEdit)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response dto.OrganizationPolicyDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Contains(t, response.Rules, dto.PolicyRuleDTO{
+		Action:     policy.ActionTaskUpdate,
+		Role:       models.RoleMember,
+		Scope:      policy.ScopeAny,
+		Customized: true,
+	})
+
+	_, err = taskService.UpdateTask(task.ID, services.UpdateTaskInput{ActorID: editor.ID, Title: &title})
+	require.NoError(t, err)
+
+	// Deleting is still limited to the creator
+	require.ErrorIs(t, taskService.DeleteTask(task.ID, editor.ID), services.ErrNotTaskCreator)
+
+	// An empty rule set restores the defaults
+	w = orgRoleRequest(t, r, http.MethodPut, policyPath, owner.ID, map[string]interface{}{"rules": []interface{}{}})
+	require.Equal(t, http.StatusOK, w.Code)
+	_, err = taskService.UpdateTask(task.ID, services.UpdateTaskInput{ActorID: editor.ID, Title: &title})
+	require.ErrorIs(t, err, services.ErrNotTaskCreator)
+}
+
+func TestOrganizationHandler_Settings(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	member := createTestOrganizationUser(t, env.db, "member")
+	newcomer := createTestOrganizationUser(t, env.db, "newcomer")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(member.ID, org.InviteCode)
+	require.NoError(t, err)
+
+	settingsPath := fmt.Sprintf("/api/organizations/%d/settings", org.ID)
+	invitesPath := fmt.Sprintf("/api/organizations/%d/invites", org.ID)
+
+	// Every member sees the defaults
+	w := orgRoleRequest(t, r, http.MethodGet, settingsPath, member.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var settings dto.OrganizationSettingsDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &settings))
+	require.Equal(t, models.TaskStatusTodo, settings.DefaultTaskStatus)
+	require.Equal(t, "UTC", settings.Timezone)
+	require.True(t, settings.AIGenerationEnabled)
+	require.False(t, settings.MembersCanInvite)
+	require.Nil(t, settings.Quotas.MaxMembers)
+
+	// Members cannot invite by default
+	w = orgRoleRequest(t, r, http.MethodPost, invitesPath, member.ID, map[string]interface{}{"name": "Friends"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	update := map[string]interface{}{
+		"default_task_status":   "DONE",
+		"members_can_invite":    true,
+		"default_due_time":      "09:30",
+		"timezone":              "Asia/Tokyo",
+		"ai_generation_enabled": false,
+		"quotas":                map[string]interface{}{"max_members": 2, "ai_generations_per_day": 5},
+	}
+	require.Equal(t, http.StatusForbidden, orgRoleRequest(t, r, http.MethodPut, settingsPath, member.ID, update).Code)
+
+	for field, value := range map[string]interface{}{
+		"default_task_status": "DOING",
+		"default_due_time":    "25:00",
+		"timezone":            "Mars/Olympus_Mons",
+		"quotas":              map[string]interface{}{"max_tasks": 0},
+	} {
+		invalid := map[string]interface{}{}
+		for k, v := range update {
+			invalid[k] = v
+		}
+		invalid[field] = value
+		w = orgRoleRequest(t, r, http.MethodPut, settingsPath, owner.ID, invalid)
+		require.Equal(t, http.StatusBadRequest, w.Code, field)
+	}
+
+	w = orgRoleRequest(t, r, http.MethodPut, settingsPath, owner.ID, update)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &settings))
+	require.Equal(t, models.TaskStatusDone, settings.DefaultTaskStatus)
+	require.Equal(t, "Asia/Tokyo", settings.Timezone)
+	require.False(t, settings.AIGenerationEnabled)
+	require.Equal(t, 2, *settings.Quotas.MaxMembers)
+	require.Nil(t, settings.Quotas.MaxTasks)
+
+	// Members may now invite members and guests, but not admins
+	w = orgRoleRequest(t, r, http.MethodPost, invitesPath, member.ID, map[string]interface{}{"name": "Friends"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var invite dto.InviteDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &invite))
+	w = orgRoleRequest(t, r, http.MethodPost, invitesPath, member.ID, map[string]interface{}{"name": "Admins", "role": "admin"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// The organization is full
+	for _, code := range []string{org.InviteCode, invite.Code} {
+		w = orgRoleRequest(t, r, http.MethodPost, "/api/organizations/join", newcomer.ID, map[string]string{"invite_code": code})
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Contains(t, w.Body.String(), apierrors.ErrCodeQuotaExceeded)
+	}
+}
+
+func TestOrganizationHandler_InviteLinks(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	admin := createTestOrganizationUser(t, env.db, "admin")
+	first := createTestOrganizationUser(t, env.db, "first")
+	second := createTestOrganizationUser(t, env.db, "second")
+	legacy := createTestOrganizationUser(t, env.db, "legacy")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(admin.ID, org.InviteCode)
+	require.NoError(t, err)
+	_, err = env.orgService.ChangeMemberRole(org.ID, owner.ID, admin.ID, models.RoleAdmin)
+	require.NoError(t, err)
+
+	invitesPath := fmt.Sprintf("/api/organizations/%d/invites", org.ID)
+	join := func(userID uint64, code string) *httptest.ResponseRecorder {
+		return orgRoleRequest(t, r, http.MethodPost, "/api/organizations/join", userID, map[string]string{"invite_code": code})
+	}
+
+	// Admins cannot hand out their own role, nobody can hand out ownership
+	w := orgRoleRequest(t, r, http.MethodPost, invitesPath, admin.ID, map[string]interface{}{"name": "Admins", "role": "admin"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+	w = orgRoleRequest(t, r, http.MethodPost, invitesPath, owner.ID, map[string]interface{}{"name": "Owners", "role": "owner"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = orgRoleRequest(t, r, http.MethodPost, invitesPath, admin.ID, map[string]interface{}{
+		"name":             "Reviewers",
+		"role":             "guest",
+		"max_uses":         1,
+		"expires_in_hours": 24,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var invite dto.InviteDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &invite))
+	require.Equal(t, models.InviteStatusActive, invite.Status)
+	require.NotNil(t, invite.ExpiresAt)
+
+	// The invite grants its role and runs out after one use
+	require.Equal(t, http.StatusOK, join(first.ID, invite.Code).Code)
+	joined, err := repository.NewOrganizationRepository(env.db).FindMember(org.ID, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleGuest, joined.Role)
+	require.Equal(t, http.StatusGone, join(second.ID, invite.Code).Code)
+
+	w = orgRoleRequest(t, r, http.MethodGet, fmt.Sprintf("%s/%d", invitesPath, invite.ID), owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var detail dto.InviteDetailDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	require.Equal(t, models.InviteStatusUsedUp, detail.Status)
+	require.Equal(t, 1, detail.UseCount)
+	require.Len(t, detail.Uses, 1)
+	require.Equal(t, first.ID, detail.Uses[0].User.ID)
+
+	// Guests cannot see invites
+	w = orgRoleRequest(t, r, http.MethodGet, invitesPath, first.ID, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Revoked and expired invites stop working
+	revoked, err := env.orgService.CreateInvite(services.CreateInviteInput{OrganizationID: org.ID, ActorID: owner.ID, Name: "Revoked"})
+	require.NoError(t, err)
+	w = orgRoleRequest(t, r, http.MethodDelete, fmt.Sprintf("%s/%d", invitesPath, revoked.ID), admin.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, http.StatusGone, join(second.ID, revoked.Code).Code)
+
+	expired, err := env.orgService.CreateInvite(services.CreateInviteInput{OrganizationID: org.ID, ActorID: owner.ID, Name: "Expired"})
+	require.NoError(t, err)
+	require.NoError(t, env.db.Model(expired).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	require.Equal(t, http.StatusGone, join(second.ID, expired.Code).Code)
+
+	w = orgRoleRequest(t, r, http.MethodGet, invitesPath, owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed map[string][]dto.InviteDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed["invites"], 3)
+
+	// The permanent organization code keeps working
+	require.Equal(t, http.StatusOK, join(legacy.ID, org.InviteCode).Code)
+}
+
+func TestOrganizationHandler_Workflow(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	member := createTestOrganizationUser(t, env.db, "member")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	_, err = env.orgService.JoinOrganizationByInvite(member.ID, org.InviteCode)
+	require.NoError(t, err)
+
+	workflowPath := fmt.Sprintf("/api/organizations/%d/workflow", org.ID)
+
+	// Organizations start with the TODO/DONE workflow
+	w := orgRoleRequest(t, r, http.MethodGet, workflowPath, member.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var workflow dto.WorkflowDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &workflow))
+	require.Equal(t, []dto.WorkflowStateDTO{
+		{Key: models.TaskStatusTodo, Name: "To do"},
+		{Key: models.TaskStatusDone, Name: "Done", Done: true},
+	}, workflow.States)
+	require.Len(t, workflow.Transitions, 2)
+
+	state := func(key, name string, done bool) map[string]interface{} {
+		return map[string]interface{}{"key": key, "name": name, "done": done}
+	}
+	move := func(from, to string) map[string]string {
+		return map[string]string{"from": from, "to": to}
+	}
+	valid := map[string]interface{}{
+		"states": []interface{}{
+			state("TODO", "To do", false),
+			state("IN_PROGRESS", "In progress", false),
+			state("DONE", "Done", true),
+		},
+		"transitions": []interface{}{move("TODO", "IN_PROGRESS"), move("IN_PROGRESS", "DONE")},
+	}
+	require.Equal(t, http.StatusForbidden, orgRoleRequest(t, r, http.MethodPut, workflowPath, member.ID, valid).Code)
+
+	for name, body := range map[string]interface{}{
+		"single state":      map[string]interface{}{"states": []interface{}{state("DONE", "Done", true)}},
+		"no open state":     map[string]interface{}{"states": []interface{}{state("A", "A", true), state("B", "B", true)}},
+		"no done state":     map[string]interface{}{"states": []interface{}{state("A", "A", false), state("B", "B", false)}},
+		"malformed key":     map[string]interface{}{"states": []interface{}{state("in-progress", "A", false), state("DONE", "Done", true)}},
+		"duplicate key":     map[string]interface{}{"states": []interface{}{state("DONE", "A", false), state("DONE", "Done", true)}},
+		"blank name":        map[string]interface{}{"states": []interface{}{state("TODO", " ", false), state("DONE", "Done", true)}},
+		"unknown state":     map[string]interface{}{"states": valid["states"], "transitions": []interface{}{move("TODO", "REVIEW")}},
+		"self transition":   map[string]interface{}{"states": valid["states"], "transitions": []interface{}{move("TODO", "TODO")}},
+		"repeat transition": map[string]interface{}{"states": valid["states"], "transitions": []interface{}{move("TODO", "DONE"), move("TODO", "DONE")}},
+	} {
+		w = orgRoleRequest(t, r, http.MethodPut, workflowPath, owner.ID, body)
+		require.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+
+	w = orgRoleRequest(t, r, http.MethodPut, workflowPath, owner.ID, valid)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &workflow))
+	require.Equal(t, models.TaskStatus("IN_PROGRESS"), workflow.States[1].Key)
+	require.Equal(t, []dto.WorkflowTransitionDTO{
+		{From: models.TaskStatusTodo, To: "IN_PROGRESS"},
+		{From: "IN_PROGRESS", To: models.TaskStatusDone},
+	}, workflow.Transitions)
+
+	// The default task status has to be one of the states
+	settingsPath := fmt.Sprintf("/api/organizations/%d/settings", org.ID)
+	settings := map[string]interface{}{
+		"default_task_status":   "IN_PROGRESS",
+		"members_can_invite":    false,
+		"default_due_time":      "23:59",
+		"timezone":              "UTC",
+		"ai_generation_enabled": true,
+	}
+	require.Equal(t, http.StatusOK, orgRoleRequest(t, r, http.MethodPut, settingsPath, owner.ID, settings).Code)
+	settings["default_task_status"] = "REVIEW"
+	require.Equal(t, http.StatusBadRequest, orgRoleRequest(t, r, http.MethodPut, settingsPath, owner.ID, settings).Code)
+
+	// Open task counts follow the done flags of the workflow
+	taskService := services.NewTaskService(repository.NewTaskRepository(env.db), repository.NewOrganizationRepository(env.db), nil, nil)
+	for _, status := range []models.TaskStatus{models.TaskStatusTodo, "IN_PROGRESS", models.TaskStatusDone} {
+		_, err := taskService.CreateTask(services.CreateTaskInput{Title: string(status), Status: status, OrganizationID: org.ID, CreatorID: owner.ID})
+		require.NoError(t, err)
+	}
+	w = orgRoleRequest(t, r, http.MethodGet, fmt.Sprintf("/api/organizations/%d/members", org.ID), owner.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var members dto.OrganizationMemberListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &members))
+	require.Equal(t, owner.ID, members.Members[0].User.ID)
+	require.Equal(t, int64(2), members.Members[0].OpenAssignedTasks)
+
+	// States that tasks are in cannot be dropped
+	w = orgRoleRequest(t, r, http.MethodPut, workflowPath, owner.ID, map[string]interface{}{
+		"states": []interface{}{state("TODO", "To do", false), state("DONE", "Done", true)},
+	})
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
+
+func TestOrganizationHandler_Labels(t *testing.T) {
+	env := setupOrganizationTestEnv(t)
+	r := orgRoleTestRouter(env)
+
+	owner := createTestOrganizationUser(t, env.db, "owner")
+	admin := createTestOrganizationUser(t, env.db, "admin")
+	member := createTestOrganizationUser(t, env.db, "member")
+	org, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Org", OwnerID: owner.ID})
+	require.NoError(t, err)
+	other, err := env.orgService.CreateOrganization(services.CreateOrganizationInput{Name: "Other", OwnerID: owner.ID})
+	require.NoError(t, err)
+	for _, u := range []*models.User{admin, member} {
+		_, err = env.orgService.JoinOrganizationByInvite(u.ID, org.InviteCode)
+		require.NoError(t, err)
+	}
+	_, err = env.orgService.ChangeMemberRole(org.ID, owner.ID, admin.ID, models.RoleAdmin)
+	require.NoError(t, err)
+
+	labelsPath := fmt.Sprintf("/api/organizations/%d/labels", org.ID)
+	label := func(name, color string) map[string]string {
+		return map[string]string{"name": name, "color": color}
+	}
+
+	// Members can see labels but not manage them
+	require.Equal(t, http.StatusForbidden, orgRoleRequest(t, r, http.MethodPost, labelsPath, member.ID, label("bug", "#d73a4a")).Code)
+
+	w := orgRoleRequest(t, r, http.MethodPost, labelsPath, admin.ID, label(" bug ", "#D73A4A"))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var bug dto.LabelDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+	require.Equal(t, "bug", bug.Name)
+	require.Equal(t, "#d73a4a", bug.Color)
+
+	w = orgRoleRequest(t, r, http.MethodPost, labelsPath, owner.ID, label("Feature", "#1f883d"))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var feature dto.LabelDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &feature))
+
+	for name, body := range map[string]interface{}{
+		"blank name":    label("  ", "#000000"),
+		"long name":     label(strings.Repeat("x", 51), "#000000"),
+		"named color":   label("docs", "blue"),
+		"short color":   label("docs", "#fff"),
+		"missing color": map[string]string{"name": "docs"},
+	} {
+		require.Equal(t, http.StatusBadRequest, orgRoleRequest(t, r, http.MethodPost, labelsPath, owner.ID, body).Code, name)
+	}
+
+	// Names are unique per organization, ignoring case
+	require.Equal(t, http.StatusConflict, orgRoleRequest(t, r, http.MethodPost, labelsPath, owner.ID, label("BUG", "#000000")).Code)
+	otherPath := fmt.Sprintf("/api/organizations/%d/labels", other.ID)
+	require.Equal(t, http.StatusCreated, orgRoleRequest(t, r, http.MethodPost, otherPath, owner.ID, label("bug", "#000000")).Code)
+
+	bugPath := fmt.Sprintf("%s/%d", labelsPath, bug.ID)
+	require.Equal(t, http.StatusConflict, orgRoleRequest(t, r, http.MethodPut, bugPath, owner.ID, label("feature", "#000000")).Code)
+	w = orgRoleRequest(t, r, http.MethodPut, bugPath, owner.ID, label("Bug", "#b60205"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bug))
+	require.Equal(t, "Bug", bug.Name)
+	require.Equal(t, "#b60205", bug.Color)
+
+	// Labels of another organization are not found through this one
+	require.Equal(t, http.StatusNotFound, orgRoleRequest(t, r, http.MethodPut, fmt.Sprintf("%s/%d", otherPath, bug.ID), owner.ID, label("x", "#000000")).Code)
+
+	w = orgRoleRequest(t, r, http.MethodGet, labelsPath, member.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list dto.LabelListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, []dto.LabelDTO{bug, feature}, list.Labels)
+
+	require.Equal(t, http.StatusForbidden, orgRoleRequest(t, r, http.MethodDelete, bugPath, member.ID, nil).Code)
+	require.Equal(t, http.StatusOK, orgRoleRequest(t, r, http.MethodDelete, bugPath, admin.ID, nil).Code)
+	require.Equal(t, http.StatusNotFound, orgRoleRequest(t, r, http.MethodDelete, bugPath, admin.ID, nil).Code)
+
+	w = orgRoleRequest(t, r, http.MethodGet, labelsPath, member.ID, nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, []dto.LabelDTO{feature}, list.Labels)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+func setupPasswordTestRouter(t *testing.T) (authTestEnv, *gin.Engine) {
+	t.Helper()
+
+	env := setupAuthTestEnv(t)
+
+	_, err := env.authService.Signup(services.SignupInput{Username: "secure", Password: "supersecret"})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.POST("/api/auth/login", env.handler.Login)
+	r.GET("/api/auth/me", middleware.RequireAuth(), env.handler.GetCurrentUser)
+	r.POST("/api/auth/password", middleware.RequireAuth(), middleware.RequireSessionAuth(), env.handler.ChangePassword)
+	r.POST("/api/auth/password/forgot", env.handler.ForgotPassword)
+	r.POST("/api/auth/password/reset", env.handler.ResetPassword)
+
+	return env, r
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	_, r := setupPasswordTestRouter(t)
+
+	current := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusOK, current.login().Code)
+	other := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusOK, other.login().Code)
+
+	w := current.do(http.MethodPost, "/api/auth/password", map[string]string{
+		"current_password": "wrong-password",
+		"new_password":     "a much better passphrase",
+	})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = current.do(http.MethodPost, "/api/auth/password", map[string]string{
+		"current_password": "supersecret",
+		"new_password":     "password123",
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code, "common passwords are rejected")
+
+	w = current.do(http.MethodPost, "/api/auth/password", map[string]string{
+		"current_password": "supersecret",
+		"new_password":     "a much better passphrase",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The session that changed the password stays signed in; the others do not
+	require.Equal(t, http.StatusOK, current.do(http.MethodGet, "/api/auth/me", nil).Code)
+	require.Equal(t, http.StatusUnauthorized, other.do(http.MethodGet, "/api/auth/me", nil).Code)
+
+	// Only the new password works from now on
+	fresh := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusUnauthorized, fresh.login().Code)
+	w = fresh.do(http.MethodPost, "/api/auth/login", map[string]string{
+		"username": "secure",
+		"password": "a much better passphrase",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthHandler_PasswordReset(t *testing.T) {
+	env, r := setupPasswordTestRouter(t)
+
+	signedIn := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusOK, signedIn.login().Code)
+
+	anonymous := &sessionClient{t: t, router: r}
+
+	// Unknown usernames get the same response and no notification
+	w := anonymous.do(http.MethodPost, "/api/auth/password/forgot", map[string]string{"username": "nobody"})
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Empty(t, env.notifier.resets)
+
+	w = anonymous.do(http.MethodPost, "/api/auth/password/forgot", map[string]string{"username": "secure"})
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, env.notifier.resets, 1)
+	token := env.notifier.resets[0].Token
+
+	w = anonymous.do(http.MethodPost, "/api/auth/password/reset", map[string]string{
+		"token":        "not-a-real-token",
+		"new_password": "a much better passphrase",
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = anonymous.do(http.MethodPost, "/api/auth/password/reset", map[string]string{
+		"token":        token,
+		"new_password": "a much better passphrase",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Tokens are single use
+	w = anonymous.do(http.MethodPost, "/api/auth/password/reset", map[string]string{
+		"token":        token,
+		"new_password": "yet another passphrase",
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Existing sessions are signed out
+	require.Equal(t, http.StatusUnauthorized, signedIn.do(http.MethodGet, "/api/auth/me", nil).Code)
+
+	w = anonymous.do(http.MethodPost, "/api/auth/login", map[string]string{
+		"username": "secure",
+		"password": "a much better passphrase",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthHandler_SignupRejectsWeakPasswords(t *testing.T) {
+	env := setupAuthTestEnv(t)
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.POST("/api/auth/signup", env.handler.Signup)
+	client := &sessionClient{t: t, router: r}
+
+	for _, payload := range []map[string]string{
+		{"username": "weakling", "password": "password123"},
+		{"username": "mirrorname", "password": "mirrorname"},
+	} {
+		w := client.do(http.MethodPost, "/api/auth/signup", payload)
+		require.Equal(t, http.StatusBadRequest, w.Code, payload["password"])
+	}
+}
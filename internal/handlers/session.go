@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// SessionHandler lists and revokes a user's login sessions.
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler creates a new SessionHandler.
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions returns the current user's active sessions.
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+	currentSessionID, _ := middleware.GetSessionID(c)
+
+	userSessions, err := h.sessionService.ListSessions(userID)
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	sessionDTOs := make([]dto.SessionDTO, len(userSessions))
+	for i, s := range userSessions {
+		sessionDTOs[i] = dto.ToSessionDTO(s, currentSessionID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessionDTOs,
+	})
+}
+
+// RevokeSession revokes one of the current user's sessions. Revoking the
+// current session logs the caller out.
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid session ID")
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(userID, sessionID); err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	if currentSessionID, ok := middleware.GetSessionID(c); ok && currentSessionID == sessionID {
+		session := sessions.Default(c)
+		session.Clear()
+		if err := session.Save(); err != nil {
+			apierrors.InternalError(c, "Failed to clear session")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RevokeOtherSessions revokes every session of the current user except this one.
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	currentSessionID, ok := middleware.GetSessionID(c)
+	if !ok {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	count, err := h.sessionService.RevokeOtherSessions(userID, currentSessionID)
+	if err != nil {
+		respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Other sessions revoked successfully",
+		"revoked_count": count,
+	})
+}
+
+func respondSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		apierrors.NotFound(c, err.Error())
+	default:
+		apierrors.InternalError(c, "Internal server error")
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSessionTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.UserSession{},
+	)
+	require.NoError(t, err)
+
+	database.SetDB(db)
+
+	userRepo := repository.NewUserRepository(db)
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db))
+	authService := newTestAuthService(db, sessionService, &recordingNotifier{})
+	authHandler := NewAuthHandler(
+		authService,
+		services.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo),
+		sessionService,
+	)
+	sessionHandler := NewSessionHandler(sessionService)
+
+	_, err = authService.Signup(services.SignupInput{Username: "secure", Password: "supersecret"})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.POST("/api/auth/login", authHandler.Login)
+	r.POST("/api/auth/logout", authHandler.Logout)
+	r.GET("/api/auth/me", middleware.RequireAuth(), authHandler.GetCurrentUser)
+	userSessions := r.Group("/api/auth/sessions", middleware.RequireAuth(), middleware.RequireSessionAuth())
+	userSessions.GET("", sessionHandler.ListSessions)
+	userSessions.POST("/revoke-others", sessionHandler.RevokeOtherSessions)
+	userSessions.DELETE("/:session_id", sessionHandler.RevokeSession)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return r
+}
+
+func listSessions(t *testing.T, client *sessionClient) []dto.SessionDTO {
+	t.Helper()
+
+	w := client.do(http.MethodGet, "/api/auth/sessions", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Sessions []dto.SessionDTO `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Sessions
+}
+
+func TestSessionHandler_ListAndRevoke(t *testing.T) {
+	r := setupSessionTestRouter(t)
+
+	laptop := &sessionClient{t: t, router: r, userAgent: "Laptop Browser"}
+	phone := &sessionClient{t: t, router: r, userAgent: "Phone App"}
+	require.Equal(t, http.StatusOK, laptop.login().Code)
+	require.Equal(t, http.StatusOK, phone.login().Code)
+
+	list := listSessions(t, laptop)
+	require.Len(t, list, 2)
+
+	var phoneSessionID uint64
+	for _, s := range list {
+		if s.UserAgent == "Laptop Browser" {
+			require.True(t, s.Current)
+		} else {
+			require.False(t, s.Current)
+			phoneSessionID = s.ID
+		}
+	}
+	require.NotZero(t, phoneSessionID)
+
+	w := laptop.do(http.MethodDelete, "/api/auth/sessions/"+strconv.FormatUint(phoneSessionID, 10), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The revoked cookie is rejected even though it is still a valid signed cookie
+	require.Equal(t, http.StatusUnauthorized, phone.do(http.MethodGet, "/api/auth/me", nil).Code)
+	require.Equal(t, http.StatusOK, laptop.do(http.MethodGet, "/api/auth/me", nil).Code)
+
+	w = laptop.do(http.MethodDelete, "/api/auth/sessions/"+strconv.FormatUint(phoneSessionID, 10), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSessionHandler_RevokeOthers(t *testing.T) {
+	r := setupSessionTestRouter(t)
+
+	current := &sessionClient{t: t, router: r}
+	others := []*sessionClient{{t: t, router: r}, {t: t, router: r}}
+	require.Equal(t, http.StatusOK, current.login().Code)
+	for _, other := range others {
+		require.Equal(t, http.StatusOK, other.login().Code)
+	}
+
+	w := current.do(http.MethodPost, "/api/auth/sessions/revoke-others", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"revoked_count":2`)
+
+	for _, other := range others {
+		require.Equal(t, http.StatusUnauthorized, other.do(http.MethodGet, "/api/auth/me", nil).Code)
+	}
+	require.Len(t, listSessions(t, current), 1)
+}
+
+func TestAuthHandler_LogoutRevokesSession(t *testing.T) {
+	r := setupSessionTestRouter(t)
+
+	client := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusOK, client.login().Code)
+	loggedInCookies := client.cookies
+
+	require.Equal(t, http.StatusOK, client.do(http.MethodPost, "/api/auth/logout", nil).Code)
+
+	// Replaying the cookie captured before logout no longer works
+	replay := &sessionClient{t: t, router: r, cookies: loggedInCookies}
+	require.Equal(t, http.StatusUnauthorized, replay.do(http.MethodGet, "/api/auth/me", nil).Code)
+}
@@ -5,6 +5,7 @@ import (
 	stdErrors "errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -49,18 +50,40 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 
 	assignedToMe := c.Query("assigned_to_me") == "true"
 	dueToday := c.Query("due_today") == "true"
-	sortByDueDate := c.Query("sort") == "due_date"
+	topLevelOnly := c.Query("top_level") == "true"
 
 	var statusPtr *models.TaskStatus
 	if statusStr := c.Query("status"); statusStr != "" {
 		status := models.TaskStatus(statusStr)
-		if status != models.TaskStatusTodo && status != models.TaskStatusDone {
+		if !status.IsValid() {
 			apierrors.BadRequest(c, "Invalid status filter")
 			return
 		}
 		statusPtr = &status
 	}
 
+	var labelIDs []uint64
+	if labelsStr := c.Query("labels"); labelsStr != "" {
+		for _, idStr := range strings.Split(labelsStr, ",") {
+			labelID, err := strconv.ParseUint(strings.TrimSpace(idStr), 10, 64)
+			if err != nil {
+				apierrors.BadRequest(c, "Invalid labels, expected comma separated label IDs")
+				return
+			}
+			labelIDs = append(labelIDs, labelID)
+		}
+	}
+
+	var matchAllLabels bool
+	switch c.DefaultQuery("label_match", "any") {
+	case "any":
+	case "all":
+		matchAllLabels = true
+	default:
+		apierrors.BadRequest(c, "Invalid label_match, expected any or all")
+		return
+	}
+
 	params := utils.GetPaginationParams(c)
 
 	tasks, total, err := h.taskService.ListTasks(services.ListTasksInput{
@@ -69,12 +92,17 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 		AssignedToMe:   assignedToMe,
 		DueToday:       dueToday,
 		Status:         statusPtr,
-		SortByDueDate:  sortByDueDate,
+		TopLevelOnly:   topLevelOnly,
+		LabelIDs:       labelIDs,
+		MatchAllLabels: matchAllLabels,
+		Sort:           c.Query("sort"),
 		Page:           params.Page,
 		PageSize:       params.Limit,
 	})
 	if err != nil {
 		switch {
+		case stdErrors.Is(err, services.ErrInvalidTaskSort):
+			apierrors.BadRequest(c, err.Error())
 		case stdErrors.Is(err, services.ErrNotOrganizationMember):
 			apierrors.Forbidden(c, err.Error())
 		default:
@@ -83,8 +111,26 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 		return
 	}
 
-	response := dto.ToTaskListResponse(tasks, params.Page, params.Limit, total)
-	c.JSON(http.StatusOK, response)
+	h.respondTaskList(c, tasks, params, total)
+}
+
+// ListSubtasks returns the direct subtasks of a task.
+func (h *TaskHandler) ListSubtasks(c *gin.Context) {
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	params := utils.GetPaginationParams(c)
+
+	tasks, total, err := h.taskService.ListSubtasks(task.ID, c.Query("sort"), params.Page, params.Limit)
+	if err != nil {
+		respondTaskError(c, err, "Failed to list subtasks")
+		return
+	}
+
+	h.respondTaskList(c, tasks, params, total)
 }
 
 // GetTask returns a task by ID.
@@ -101,8 +147,7 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
-	taskDTO := dto.ToTaskDTO(*fullTask)
-	c.JSON(http.StatusOK, taskDTO)
+	h.respondTask(c, http.StatusOK, *fullTask)
 }
 
 // CreateTask creates a new task.
@@ -117,8 +162,11 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		Title          string     `json:"title" binding:"required"`
 		Description    string     `json:"description"`
 		Status         *string    `json:"status"`
+		Priority       string     `json:"priority"`
 		DueDate        *time.Time `json:"due_date"`
+		DueOn          string     `json:"due_on"`
 		OrganizationID uint64     `json:"organization_id" binding:"required"`
+		ParentID       *uint64    `json:"parent_id"`
 	}
 
 	var req CreateTaskRequest
@@ -130,18 +178,21 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	var status models.TaskStatus
 	if req.Status != nil && *req.Status != "" {
 		status = models.TaskStatus(*req.Status)
-		if status != models.TaskStatusTodo && status != models.TaskStatusDone {
+		if !status.IsValid() {
 			apierrors.BadRequest(c, "Invalid status value")
 			return
 		}
 	}
 
-	task, err := h.taskService.CreateTask(services.CreateTaskInput{
+	task, err := h.taskService.WithRequestMeta(requestMeta(c)).CreateTask(services.CreateTaskInput{
 		Title:          req.Title,
 		Description:    req.Description,
 		Status:         status,
+		Priority:       models.TaskPriority(req.Priority),
 		DueDate:        req.DueDate,
+		DueOn:          req.DueOn,
 		OrganizationID: req.OrganizationID,
+		ParentID:       req.ParentID,
 		CreatorID:      userID,
 	})
 	if err != nil {
@@ -149,8 +200,7 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
-	taskDTO := dto.ToTaskDTO(*task)
-	c.JSON(http.StatusCreated, taskDTO)
+	h.respondTask(c, http.StatusCreated, *task)
 }
 
 // UpdateTask updates an existing task.
@@ -206,13 +256,23 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 			return
 		}
 		status := models.TaskStatus(statusStr)
-		if status != models.TaskStatusTodo && status != models.TaskStatusDone {
+		if !status.IsValid() {
 			apierrors.BadRequest(c, "Invalid status value")
 			return
 		}
 		updateInput.Status = &status
 	}
 
+	if priorityVal, exists := raw["priority"]; exists {
+		priorityStr, ok := priorityVal.(string)
+		if !ok {
+			apierrors.BadRequest(c, "Priority must be a string")
+			return
+		}
+		priority := models.TaskPriority(priorityStr)
+		updateInput.Priority = &priority
+	}
+
 	if dueVal, exists := raw["due_date"]; exists {
 		if dueVal == nil {
 			updateInput.ClearDueDate = true
@@ -229,14 +289,25 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		}
 	}
 
-	updatedTask, err := h.taskService.UpdateTask(task.ID, updateInput)
+	if parentVal, exists := raw["parent_id"]; exists {
+		if parentVal == nil {
+			updateInput.ClearParent = true
+		} else if parentNum, ok := parentVal.(float64); ok && parentNum >= 1 && parentNum == float64(uint64(parentNum)) {
+			parentID := uint64(parentNum)
+			updateInput.ParentID = &parentID
+		} else {
+			apierrors.BadRequest(c, "Invalid parent_id value")
+			return
+		}
+	}
+
+	updatedTask, err := h.taskService.WithRequestMeta(requestMeta(c)).UpdateTask(task.ID, updateInput)
 	if err != nil {
 		respondTaskError(c, err, "Failed to update task")
 		return
 	}
 
-	taskDTO := dto.ToTaskDTO(*updatedTask)
-	c.JSON(http.StatusOK, taskDTO)
+	h.respondTask(c, http.StatusOK, *updatedTask)
 }
 
 // DeleteTask deletes a task.
@@ -253,7 +324,7 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.taskService.DeleteTask(task.ID, userID); err != nil {
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).DeleteTask(task.ID, userID); err != nil {
 		respondTaskError(c, err, "Failed to delete task")
 		return
 	}
@@ -287,7 +358,7 @@ func (h *TaskHandler) AssignTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.taskService.AssignUsers(services.AssignUsersInput{
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).AssignUsers(services.AssignUsersInput{
 		TaskID:  task.ID,
 		ActorID: userID,
 		UserIDs: req.UserIDs,
@@ -333,7 +404,7 @@ func (h *TaskHandler) UnassignTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.taskService.UnassignUsers(task.ID, userID, req.UserIDs); err != nil {
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).UnassignUsers(task.ID, userID, req.UserIDs); err != nil {
 		respondTaskError(c, err, "Failed to unassign users")
 		return
 	}
@@ -351,6 +422,182 @@ func (h *TaskHandler) UnassignTask(c *gin.Context) {
 	})
 }
 
+// AddTaskLabels attaches labels of the task's organization to a task.
+func (h *TaskHandler) AddTaskLabels(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	type AddLabelsRequest struct {
+		LabelIDs []uint64 `json:"label_ids" binding:"required"`
+	}
+
+	var req AddLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).AddLabels(task.ID, userID, req.LabelIDs); err != nil {
+		respondTaskError(c, err, "Failed to add labels")
+		return
+	}
+
+	h.respondTaskLabels(c, task.ID, "Labels added successfully")
+}
+
+// RemoveTaskLabel detaches a label from a task.
+func (h *TaskHandler) RemoveTaskLabel(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	labelID, err := strconv.ParseUint(c.Param("label_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid label ID")
+		return
+	}
+
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).RemoveLabels(task.ID, userID, []uint64{labelID}); err != nil {
+		respondTaskError(c, err, "Failed to remove label")
+		return
+	}
+
+	h.respondTaskLabels(c, task.ID, "Label removed successfully")
+}
+
+// ListTaskDependencies returns the tasks blocking a task and the ones it blocks.
+func (h *TaskHandler) ListTaskDependencies(c *gin.Context) {
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	h.respondTaskDependencies(c, task.ID, "")
+}
+
+// AddTaskDependency marks a task as blocked by another task.
+func (h *TaskHandler) AddTaskDependency(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	type AddDependencyRequest struct {
+		BlockerID uint64 `json:"blocker_id" binding:"required"`
+	}
+
+	var req AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).AddDependency(task.ID, userID, req.BlockerID); err != nil {
+		respondTaskError(c, err, "Failed to add dependency")
+		return
+	}
+
+	h.respondTaskDependencies(c, task.ID, "Dependency added successfully")
+}
+
+// RemoveTaskDependency stops a task from being blocked by another task.
+func (h *TaskHandler) RemoveTaskDependency(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	blockerID, err := strconv.ParseUint(c.Param("blocker_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid blocker ID")
+		return
+	}
+
+	if err := h.taskService.WithRequestMeta(requestMeta(c)).RemoveDependency(task.ID, userID, blockerID); err != nil {
+		respondTaskError(c, err, "Failed to remove dependency")
+		return
+	}
+
+	h.respondTaskDependencies(c, task.ID, "Dependency removed successfully")
+}
+
+// respondTaskDependencies responds with the tasks blocking a task and the
+// ones it blocks, along with a message after a change.
+func (h *TaskHandler) respondTaskDependencies(c *gin.Context, taskID uint64, message string) {
+	blockers, dependents, err := h.taskService.ListDependencies(taskID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to load task dependencies")
+		return
+	}
+
+	blockedBy, err := h.taskListItems(blockers)
+	if err != nil {
+		apierrors.InternalError(c, "Failed to load task dependencies")
+		return
+	}
+	blocking, err := h.taskListItems(dependents)
+	if err != nil {
+		apierrors.InternalError(c, "Failed to load task dependencies")
+		return
+	}
+
+	response := gin.H{
+		"blocked_by": blockedBy,
+		"blocking":   blocking,
+	}
+	if message != "" {
+		response["message"] = message
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// respondTaskLabels responds with the labels a task has after a change.
+func (h *TaskHandler) respondTaskLabels(c *gin.Context, taskID uint64, message string) {
+	updatedTask, err := h.taskService.GetTask(taskID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to load task labels")
+		return
+	}
+
+	taskDTO := dto.ToTaskDTO(*updatedTask)
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"labels":  taskDTO.Labels,
+	})
+}
+
 // GenerateTasks generates tasks via AI.
 func (h *TaskHandler) GenerateTasks(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
@@ -360,7 +607,8 @@ func (h *TaskHandler) GenerateTasks(c *gin.Context) {
 	}
 
 	type GenerateTasksRequest struct {
-		Text string `json:"text" binding:"required"`
+		Text           string `json:"text" binding:"required"`
+		OrganizationID uint64 `json:"organization_id" binding:"required"`
 	}
 
 	var req GenerateTasksRequest
@@ -373,8 +621,9 @@ func (h *TaskHandler) GenerateTasks(c *gin.Context) {
 	defer cancel()
 
 	tasks, err := h.taskService.GenerateTasks(ctx, services.GenerateTasksInput{
-		Text:      req.Text,
-		CreatorID: userID,
+		Text:           req.Text,
+		OrganizationID: req.OrganizationID,
+		CreatorID:      userID,
 	})
 	if err != nil {
 		respondTaskError(c, err, "Failed to generate tasks")
@@ -386,7 +635,7 @@ func (h *TaskHandler) GenerateTasks(c *gin.Context) {
 	})
 }
 
-// ToggleTaskStatus toggles the task status between TODO and DONE.
+// ToggleTaskStatus moves the task to the other state of a two-state workflow.
 func (h *TaskHandler) ToggleTaskStatus(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
@@ -400,7 +649,7 @@ func (h *TaskHandler) ToggleTaskStatus(c *gin.Context) {
 		return
 	}
 
-	updatedTask, err := h.taskService.ToggleTaskStatus(task.ID, userID)
+	updatedTask, err := h.taskService.WithRequestMeta(requestMeta(c)).ToggleTaskStatus(task.ID, userID)
 	if err != nil {
 		respondTaskError(c, err, "Failed to toggle task status")
 		return
@@ -412,6 +661,166 @@ func (h *TaskHandler) ToggleTaskStatus(c *gin.Context) {
 	})
 }
 
+// TransitionTask moves the task to another state of its organization's workflow.
+func (h *TaskHandler) TransitionTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	task, ok := getTaskFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Task not found in context")
+		return
+	}
+
+	type TransitionRequest struct {
+		Status models.TaskStatus `json:"status" binding:"required"`
+	}
+
+	var req TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	updatedTask, err := h.taskService.WithRequestMeta(requestMeta(c)).TransitionTask(task.ID, userID, req.Status)
+	if err != nil {
+		respondTaskError(c, err, "Failed to change task status")
+		return
+	}
+
+	h.respondTask(c, http.StatusOK, *updatedTask)
+}
+
+// ListTrash lists the organization's deleted tasks.
+func (h *TaskHandler) ListTrash(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	params := utils.GetPaginationParams(c)
+
+	tasks, total, err := h.taskService.ListTrash(org.ID, params)
+	if err != nil {
+		respondTaskError(c, err, "Failed to list deleted tasks")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTrashListResponse(tasks, params.Page, params.Limit, total))
+}
+
+// RestoreTask brings a deleted task back from the organization's trash.
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("task_id"), 10, 64)
+	if err != nil {
+		apierrors.BadRequest(c, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.WithRequestMeta(requestMeta(c)).RestoreTask(org.ID, taskID, userID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to restore task")
+		return
+	}
+
+	h.respondTask(c, http.StatusOK, *task)
+}
+
+// ListTaskOrder lists the organization's open tasks so that every task comes
+// after the open tasks blocking it.
+func (h *TaskHandler) ListTaskOrder(c *gin.Context) {
+	org, ok := getOrganizationFromContext(c)
+	if !ok {
+		apierrors.InternalError(c, "Organization not found in context")
+		return
+	}
+
+	tasks, err := h.taskService.TopologicalOrder(org.ID)
+	if err != nil {
+		respondTaskError(c, err, "Failed to order tasks")
+		return
+	}
+
+	items, err := h.taskListItems(tasks)
+	if err != nil {
+		apierrors.InternalError(c, "Failed to order tasks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": items})
+}
+
+// respondTask writes a task along with the progress of its subtasks and
+// whether it is blocked.
+func (h *TaskHandler) respondTask(c *gin.Context, status int, task models.Task) {
+	progress, err := h.taskService.SubtaskProgress([]models.Task{task})
+	if err != nil {
+		apierrors.InternalError(c, "Failed to count subtasks")
+		return
+	}
+	blocked, err := h.taskService.BlockedTasks([]models.Task{task})
+	if err != nil {
+		apierrors.InternalError(c, "Failed to check task dependencies")
+		return
+	}
+
+	taskDTO := dto.ToTaskDTO(task)
+	taskDTO.Subtasks = dto.ToSubtaskProgressDTO(progress[task.ID])
+	taskDTO.IsBlocked = blocked[task.ID]
+	c.JSON(status, taskDTO)
+}
+
+// respondTaskList writes a page of tasks along with the progress of their
+// subtasks and whether they are blocked.
+func (h *TaskHandler) respondTaskList(c *gin.Context, tasks []models.Task, params utils.PaginationParams, total int64) {
+	items, err := h.taskListItems(tasks)
+	if err != nil {
+		apierrors.InternalError(c, "Failed to list tasks")
+		return
+	}
+
+	response := dto.ToTaskListResponse(tasks, params.Page, params.Limit, total)
+	response.Tasks = items
+	c.JSON(http.StatusOK, response)
+}
+
+// taskListItems converts tasks to list items with the progress of their
+// subtasks and whether they are blocked.
+func (h *TaskHandler) taskListItems(tasks []models.Task) ([]dto.TaskListItemDTO, error) {
+	progress, err := h.taskService.SubtaskProgress(tasks)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := h.taskService.BlockedTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TaskListItemDTO, len(tasks))
+	for i, task := range tasks {
+		items[i] = dto.ToTaskListItemDTO(task)
+		items[i].Subtasks = dto.ToSubtaskProgressDTO(progress[task.ID])
+		items[i].IsBlocked = blocked[task.ID]
+	}
+	return items, nil
+}
+
 // getTaskFromContext retrieves the task stored by middleware.
 func getTaskFromContext(c *gin.Context) (models.Task, bool) {
 	taskInterface, exists := c.Get(constants.ContextKeyTask)
@@ -426,16 +835,42 @@ func getTaskFromContext(c *gin.Context) (models.Task, bool) {
 // respondTaskError maps domain errors to API responses.
 func respondTaskError(c *gin.Context, err error, defaultMessage string) {
 	switch {
-	case stdErrors.Is(err, services.ErrNotOrganizationMember):
+	case stdErrors.Is(err, services.ErrNotOrganizationMember),
+		stdErrors.Is(err, services.ErrActionNotPermitted):
 		apierrors.Forbidden(c, err.Error())
-	case stdErrors.Is(err, services.ErrTaskNotFound):
+	case stdErrors.Is(err, services.ErrTaskNotFound),
+		stdErrors.Is(err, services.ErrDependencyNotFound):
 		apierrors.NotFound(c, err.Error())
 	case stdErrors.Is(err, services.ErrNotTaskCreator):
 		apierrors.Forbidden(c, err.Error())
 	case stdErrors.Is(err, services.ErrTaskPermissionDenied):
 		apierrors.Forbidden(c, err.Error())
-	case stdErrors.Is(err, services.ErrTitleRequired),
+	case stdErrors.Is(err, services.ErrOrganizationArchived),
+		stdErrors.Is(err, services.ErrParentTaskDeleted),
+		stdErrors.Is(err, services.ErrTaskBlocked),
+		stdErrors.Is(err, services.ErrTaskOrderCycle),
+		stdErrors.Is(err, services.ErrTransitionNotAllowed),
+		stdErrors.Is(err, services.ErrToggleRequiresTwoStates):
+		apierrors.Conflict(c, err.Error())
+	case stdErrors.Is(err, services.ErrAIGenerationDisabled):
+		apierrors.Forbidden(c, err.Error())
+	case stdErrors.Is(err, services.ErrTaskQuotaExceeded),
+		stdErrors.Is(err, services.ErrAIQuotaExceeded):
+		apierrors.QuotaExceeded(c, err.Error())
+	case stdErrors.Is(err, services.ErrInvalidDueOn),
+		stdErrors.Is(err, services.ErrConflictingDueDates),
+		stdErrors.Is(err, services.ErrInvalidTaskPriority),
+		stdErrors.Is(err, services.ErrInvalidTaskSort),
+		stdErrors.Is(err, services.ErrInvalidTaskLabel),
+		stdErrors.Is(err, services.ErrInvalidParentTask),
+		stdErrors.Is(err, services.ErrTaskTooDeep),
+		stdErrors.Is(err, services.ErrTaskCycle),
+		stdErrors.Is(err, services.ErrInvalidTaskBlocker),
+		stdErrors.Is(err, services.ErrDependencyCycle),
+		stdErrors.Is(err, services.ErrNoLabelIDsProvided),
+		stdErrors.Is(err, services.ErrTitleRequired),
 		stdErrors.Is(err, services.ErrTitleEmpty),
+		stdErrors.Is(err, services.ErrInvalidTaskStatus),
 		stdErrors.Is(err, services.ErrInvalidTaskAssignee),
 		stdErrors.Is(err, services.ErrNoUserIDsProvided),
 		stdErrors.Is(err, services.ErrAINoTasksGenerated),
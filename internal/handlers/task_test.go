@@ -2,17 +2,22 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 	"github.com/yukikurage/task-management-api/internal/constants"
 	"github.com/yukikurage/task-management-api/internal/database"
 	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
 	"github.com/yukikurage/task-management-api/internal/models"
 	"github.com/yukikurage/task-management-api/internal/repository"
 	"github.com/yukikurage/task-management-api/internal/services"
@@ -25,9 +30,10 @@ func init() {
 }
 
 type taskHandlerTestEnv struct {
-	handler     *TaskHandler
-	taskService *services.TaskService
-	db          *gorm.DB
+	handler      *TaskHandler
+	taskService  *services.TaskService
+	auditService *services.AuditService
+	db           *gorm.DB
 }
 
 func setupTaskHandlerTestEnv(t *testing.T) taskHandlerTestEnv {
@@ -40,8 +46,17 @@ func setupTaskHandlerTestEnv(t *testing.T) taskHandlerTestEnv {
 		&models.User{},
 		&models.Organization{},
 		&models.OrganizationMember{},
+		&models.OrganizationPolicyRule{},
+		&models.OrganizationSettings{},
+		&models.OrganizationAIUsage{},
+		&models.WorkflowState{},
+		&models.WorkflowTransition{},
+		&models.Label{},
+		&models.TaskLabel{},
+		&models.TaskDependency{},
 		&models.Task{},
 		&models.TaskAssignment{},
+		&models.AuditLogEntry{},
 	)
 	require.NoError(t, err)
 
@@ -49,7 +64,8 @@ func setupTaskHandlerTestEnv(t *testing.T) taskHandlerTestEnv {
 
 	taskRepo := repository.NewTaskRepository(db)
 	orgRepo := repository.NewOrganizationRepository(db)
-	taskService := services.NewTaskService(taskRepo, orgRepo, nil)
+	auditService := services.NewAuditService(repository.NewAuditLogRepository(db))
+	taskService := services.NewTaskService(taskRepo, orgRepo, nil, auditService)
 	handler := NewTaskHandler(taskService)
 
 	sqlDB, err := db.DB()
@@ -59,9 +75,10 @@ func setupTaskHandlerTestEnv(t *testing.T) taskHandlerTestEnv {
 	})
 
 	return taskHandlerTestEnv{
-		handler:     handler,
-		taskService: taskService,
-		db:          db,
+		handler:      handler,
+		taskService:  taskService,
+		auditService: auditService,
+		db:           db,
 	}
 }
 
@@ -87,6 +104,7 @@ func addMember(t *testing.T, db *gorm.DB, orgID, userID uint64) {
 	member := &models.OrganizationMember{
 		OrganizationID: orgID,
 		UserID:         userID,
+		Role:           models.RoleMember,
 	}
 	require.NoError(t, db.Create(member).Error)
 }
@@ -235,3 +253,853 @@ func TestTaskHandler_ToggleTaskStatus_AssignedUser(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
 	require.Equal(t, string(models.TaskStatusDone), response["status"])
 }
+
+func TestTaskHandler_ListTasks_SortByDueDate(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	user := createUser(t, env.db, "member")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, user.ID)
+
+	later := time.Now().Add(48 * time.Hour)
+	sooner := time.Now().Add(24 * time.Hour)
+	for _, input := range []services.CreateTaskInput{
+		{Title: "No due date", DueDate: nil},
+		{Title: "Later", DueDate: &later},
+		{Title: "Sooner", DueDate: &sooner},
+	} {
+		input.OrganizationID = org.ID
+		input.CreatorID = user.ID
+		_, err := env.taskService.CreateTask(input)
+		require.NoError(t, err)
+	}
+
+	c, w := newTestContext(http.MethodGet, "/api/tasks?sort=due_date", nil, user.ID)
+
+	env.handler.ListTasks(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Tasks, 3)
+	require.Equal(t, "Sooner", response.Tasks[0].Title)
+	require.Equal(t, "Later", response.Tasks[1].Title)
+	require.Equal(t, "No due date", response.Tasks[2].Title)
+}
+
+func TestTaskHandler_ListTasks_MultiKeySort(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	user := createUser(t, env.db, "member")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, user.ID)
+
+	later := time.Now().Add(48 * time.Hour)
+	sooner := time.Now().Add(24 * time.Hour)
+	for _, input := range []services.CreateTaskInput{
+		{Title: "b normal", DueDate: &sooner},
+		{Title: "Low", Priority: models.TaskPriorityLow, DueDate: &sooner},
+		{Title: "A normal", DueDate: &sooner},
+		{Title: "Urgent later", Priority: models.TaskPriorityUrgent, DueDate: &later},
+		{Title: "Urgent undated", Priority: models.TaskPriorityUrgent},
+		{Title: "Urgent sooner", Priority: models.TaskPriorityUrgent, DueDate: &sooner},
+		{Title: "High", Priority: models.TaskPriorityHigh},
+	} {
+		input.OrganizationID = org.ID
+		input.CreatorID = user.ID
+		_, err := env.taskService.CreateTask(input)
+		require.NoError(t, err)
+	}
+
+	listTitles := func(sort string) []string {
+		c, w := newTestContext(http.MethodGet, "/api/tasks?sort="+url.QueryEscape(sort), nil, user.ID)
+		env.handler.ListTasks(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response dto.TaskListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		titles := make([]string, 0, len(response.Tasks))
+		for _, task := range response.Tasks {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+
+	require.Equal(t, []string{
+		"Urgent later", "Urgent sooner", "Urgent undated", "High", "A normal", "b normal", "Low",
+	}, listTitles("priority,-due_date,title"))
+	require.Equal(t, []string{
+		"Low", "A normal", "b normal", "High", "Urgent sooner", "Urgent later", "Urgent undated",
+	}, listTitles("-priority, due_date,title"))
+
+	for _, sort := range []string{"status", "priority,priority", "-", "title,", "priority;title"} {
+		c, w := newTestContext(http.MethodGet, "/api/tasks?sort="+url.QueryEscape(sort), nil, user.ID)
+		env.handler.ListTasks(c)
+		require.Equal(t, http.StatusBadRequest, w.Code, sort)
+	}
+
+	_, err := env.taskService.CreateTask(services.CreateTaskInput{Title: "Bad", Priority: "critical", OrganizationID: org.ID, CreatorID: user.ID})
+	require.ErrorIs(t, err, services.ErrInvalidTaskPriority)
+}
+
+func TestTaskHandler_GuestIsReadOnly(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	guest := createUser(t, env.db, "guest")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+	addMember(t, env.db, org.ID, guest.ID)
+
+	task, err := env.taskService.CreateTask(services.CreateTaskInput{
+		Title:          "Shared task",
+		OrganizationID: org.ID,
+		CreatorID:      creator.ID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, env.taskService.AssignUsers(services.AssignUsersInput{
+		TaskID:  task.ID,
+		ActorID: creator.ID,
+		UserIDs: []uint64{guest.ID},
+	}))
+
+	require.NoError(t, env.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", org.ID, guest.ID).
+		Update("role", models.RoleGuest).Error)
+
+	// Guests can still read tasks
+	c, w := newTestContext(http.MethodGet, "/api/tasks", nil, guest.ID)
+	env.handler.ListTasks(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body, err := json.Marshal(map[string]any{"title": "Not allowed", "organization_id": org.ID})
+	require.NoError(t, err)
+	c, w = newTestContext(http.MethodPost, "/api/tasks", body, guest.ID)
+	env.handler.CreateTask(c)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Being assigned does not let a guest change the task
+	c, w = newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/toggle-status", nil, guest.ID)
+	c.Set(constants.ContextKeyTask, *task)
+	env.handler.ToggleTaskStatus(c)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestTaskHandler_TrashAndRestore(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	teammate := createUser(t, env.db, "teammate")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+	addMember(t, env.db, org.ID, teammate.ID)
+
+	task, err := env.taskService.CreateTask(services.CreateTaskInput{
+		Title:          "Recoverable",
+		OrganizationID: org.ID,
+		CreatorID:      creator.ID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, env.taskService.AssignUsers(services.AssignUsersInput{
+		TaskID:  task.ID,
+		ActorID: creator.ID,
+		UserIDs: []uint64{teammate.ID},
+	}))
+	require.NoError(t, env.taskService.DeleteTask(task.ID, creator.ID))
+
+	c, w := newTestContext(http.MethodGet, "/api/organizations/"+strconv.FormatUint(org.ID, 10)+"/trash", nil, teammate.ID)
+	c.Set(constants.ContextKeyOrganization, *org)
+	env.handler.ListTrash(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var trash dto.TrashListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	require.Len(t, trash.Tasks, 1)
+	require.Equal(t, int64(1), trash.TotalCount)
+	require.Equal(t, task.ID, trash.Tasks[0].ID)
+	require.False(t, trash.Tasks[0].DeletedAt.IsZero())
+
+	c, w = newTestContext(http.MethodGet, "/api/organizations/"+strconv.FormatUint(org.ID, 10)+"/trash?page=2", nil, teammate.ID)
+	c.Set(constants.ContextKeyOrganization, *org)
+	env.handler.ListTrash(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	require.Empty(t, trash.Tasks)
+	require.Equal(t, 2, trash.Page)
+	require.Equal(t, int64(1), trash.TotalCount)
+
+	restore := func(userID uint64) *httptest.ResponseRecorder {
+		taskID := strconv.FormatUint(task.ID, 10)
+		c, w := newTestContext(http.MethodPost, "/api/organizations/"+strconv.FormatUint(org.ID, 10)+"/trash/"+taskID+"/restore", nil, userID)
+		c.Params = gin.Params{{Key: "task_id", Value: taskID}}
+		c.Set(constants.ContextKeyOrganization, *org)
+		env.handler.RestoreTask(c)
+		return w
+	}
+
+	// Restoring needs the same permission as deleting
+	require.Equal(t, http.StatusForbidden, restore(teammate.ID).Code)
+
+	w = restore(creator.ID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var restored dto.TaskDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	require.Equal(t, task.ID, restored.ID)
+	require.Len(t, restored.Assignments, 2)
+
+	require.Equal(t, http.StatusNotFound, restore(creator.ID).Code)
+
+	// Deleted tasks are purged once the retention period has passed
+	require.NoError(t, env.taskService.DeleteTask(task.ID, creator.ID))
+	retention := services.NewRetentionService(repository.NewRetentionRepository(env.db), time.Hour)
+
+	result, err := retention.PurgeExpired(time.Now())
+	require.NoError(t, err)
+	require.Zero(t, result.Tasks)
+
+	result, err = retention.PurgeExpired(time.Now().Add(2 * time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Tasks)
+	require.Equal(t, int64(2), result.TaskAssignments)
+
+	var count int64
+	require.NoError(t, env.db.Unscoped().Model(&models.TaskAssignment{}).Where("task_id = ?", task.ID).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestTaskHandler_ArchivedOrganizationIsReadOnly(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+
+	task, err := env.taskService.CreateTask(services.CreateTaskInput{
+		Title:          "Frozen",
+		OrganizationID: org.ID,
+		CreatorID:      creator.ID,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repository.NewOrganizationRepository(env.db).SetArchivedAt(org.ID, &task.CreatedAt))
+
+	body, err := json.Marshal(map[string]any{"title": "Too late", "organization_id": org.ID})
+	require.NoError(t, err)
+	c, w := newTestContext(http.MethodPost, "/api/tasks", body, creator.ID)
+	env.handler.CreateTask(c)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	c, w = newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/toggle-status", nil, creator.ID)
+	c.Set(constants.ContextKeyTask, *task)
+	env.handler.ToggleTaskStatus(c)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	// Reading still works
+	c, w = newTestContext(http.MethodGet, "/api/tasks/"+strconv.FormatUint(task.ID, 10), nil, creator.ID)
+	c.Set(constants.ContextKeyTask, *task)
+	env.handler.GetTask(c)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTaskHandler_RejoiningDoesNotRestoreAuthorship(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+
+	task, err := env.taskService.CreateTask(services.CreateTaskInput{
+		Title:          "Written before leaving",
+		OrganizationID: org.ID,
+		CreatorID:      creator.ID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, env.taskService.AssignUsers(services.AssignUsersInput{
+		TaskID:  task.ID,
+		ActorID: creator.ID,
+		UserIDs: []uint64{creator.ID},
+	}))
+
+	orgRepo := repository.NewOrganizationRepository(env.db)
+	require.NoError(t, orgRepo.RemoveMember(org.ID, creator.ID))
+	require.NoError(t, orgRepo.AddMember(&models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         creator.ID,
+		Role:           models.RoleMember,
+		JoinedAt:       time.Now(),
+	}, nil))
+
+	found, err := env.taskService.GetTask(task.ID)
+	require.NoError(t, err)
+	require.Empty(t, found.Assignments, "assignments end with the membership")
+
+	body, err := json.Marshal(map[string]any{"title": "Edited"})
+	require.NoError(t, err)
+	c, w := newTestContext(http.MethodPatch, "/api/tasks/"+strconv.FormatUint(task.ID, 10), body, creator.ID)
+	c.Set(constants.ContextKeyTask, *found)
+	env.handler.UpdateTask(c)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Tasks created during the new membership are theirs again
+	fresh, err := env.taskService.CreateTask(services.CreateTaskInput{
+		Title:          "Written after rejoining",
+		OrganizationID: org.ID,
+		CreatorID:      creator.ID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, env.taskService.DeleteTask(fresh.ID, creator.ID))
+}
+
+func TestTaskHandler_OrganizationSettingsAndQuotas(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+
+	maxTasks := 2
+	settings := models.DefaultOrganizationSettings(org.ID)
+	settings.DefaultTaskStatus = models.TaskStatusDone
+	settings.DefaultDueTime = "09:30"
+	settings.Timezone = "Asia/Tokyo"
+	settings.AIGenerationEnabled = false
+	settings.MaxTasks = &maxTasks
+	require.NoError(t, repository.NewOrganizationRepository(env.db).SaveSettings(&settings))
+
+	create := func(payload map[string]any) *httptest.ResponseRecorder {
+		payload["organization_id"] = org.ID
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		c, w := newTestContext(http.MethodPost, "/api/tasks", body, creator.ID)
+		env.handler.CreateTask(c)
+		return w
+	}
+
+	// A date without a time is due at the default time in the organization's time zone
+	w := create(map[string]any{"title": "Dated", "due_on": "2025-03-01"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created dto.TaskDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.Equal(t, models.TaskStatusDone, created.Status)
+	require.True(t, created.DueDate.Equal(time.Date(2025, 3, 1, 0, 30, 0, 0, time.UTC)), created.DueDate.String())
+
+	require.Equal(t, http.StatusBadRequest, create(map[string]any{"title": "Bad date", "due_on": "March 1st"}).Code)
+	require.Equal(t, http.StatusBadRequest, create(map[string]any{
+		"title": "Both", "due_on": "2025-03-01", "due_date": "2025-03-01T10:00:00Z",
+	}).Code)
+
+	// The task quota counts active tasks, so deleting one frees a slot and
+	// restoring it needs one
+	require.Equal(t, http.StatusCreated, create(map[string]any{"title": "Second", "status": "TODO"}).Code)
+	w = create(map[string]any{"title": "Third"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "QUOTA_EXCEEDED")
+
+	require.NoError(t, env.taskService.DeleteTask(created.ID, creator.ID))
+	require.Equal(t, http.StatusCreated, create(map[string]any{"title": "Third"}).Code)
+	_, err := env.taskService.RestoreTask(org.ID, created.ID, creator.ID)
+	require.ErrorIs(t, err, services.ErrTaskQuotaExceeded)
+}
+
+func TestTaskHandler_GenerateTasksRespectsOrganizationSettings(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+
+	orgRepo := repository.NewOrganizationRepository(env.db)
+	// Both checks happen before the AI provider is called
+	taskService := services.NewTaskService(repository.NewTaskRepository(env.db), orgRepo, services.NewAIService("test-key"), env.auditService)
+	handler := NewTaskHandler(taskService)
+
+	generate := func() *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]any{"text": "Plan the release", "organization_id": org.ID})
+		require.NoError(t, err)
+		c, w := newTestContext(http.MethodPost, "/api/tasks/generate", body, creator.ID)
+		handler.GenerateTasks(c)
+		return w
+	}
+
+	settings := models.DefaultOrganizationSettings(org.ID)
+	settings.AIGenerationEnabled = false
+	require.NoError(t, orgRepo.SaveSettings(&settings))
+	require.Equal(t, http.StatusForbidden, generate().Code)
+
+	limit := 1
+	settings.AIGenerationEnabled = true
+	settings.AIGenerationsPerDay = &limit
+	require.NoError(t, orgRepo.SaveSettings(&settings))
+
+	// A generation that fails before producing tasks does not use up the quota
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := taskService.GenerateTasks(canceled, services.GenerateTasksInput{
+		Text: "Plan the release", OrganizationID: org.ID, CreatorID: creator.ID,
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	today := time.Now().In(settings.Location()).Format(time.DateOnly)
+	consumed, err := orgRepo.ConsumeAIGeneration(org.ID, today, &limit)
+	require.NoError(t, err)
+	require.True(t, consumed)
+
+	w := generate()
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "QUOTA_EXCEEDED")
+}
+
+func TestTaskHandler_WorkflowTransitions(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+	orgService := services.NewOrganizationService(repository.NewOrganizationRepository(env.db), repository.NewInviteRepository(env.db), nil)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	addMember(t, env.db, org.ID, creator.ID)
+
+	todo, err := env.taskService.CreateTask(services.CreateTaskInput{Title: "Old", OrganizationID: org.ID, CreatorID: creator.ID})
+	require.NoError(t, err)
+
+	// TODO is still in use, so the workflow has to keep it
+	kanban := models.Workflow{
+		States: []models.WorkflowState{
+			{Key: "BACKLOG", Name: "Backlog"},
+			{Key: "IN_PROGRESS", Name: "In progress"},
+			{Key: "REVIEW", Name: "Review"},
+			{Key: models.TaskStatusDone, Name: "Done", Done: true},
+		},
+		Transitions: []models.WorkflowTransition{
+			{FromStatus: "BACKLOG", ToStatus: "IN_PROGRESS"},
+			{FromStatus: "IN_PROGRESS", ToStatus: "REVIEW"},
+			{FromStatus: "REVIEW", ToStatus: "IN_PROGRESS"},
+			{FromStatus: "REVIEW", ToStatus: models.TaskStatusDone},
+		},
+	}
+	_, err = orgService.UpdateWorkflow(org.ID, kanban)
+	require.ErrorIs(t, err, services.ErrWorkflowStateInUse)
+
+	require.NoError(t, env.taskService.DeleteTask(todo.ID, creator.ID))
+	_, err = orgService.UpdateWorkflow(org.ID, kanban)
+	require.ErrorIs(t, err, services.ErrWorkflowStateInUse, "tasks in the trash keep their state too")
+
+	require.NoError(t, env.db.Unscoped().Delete(&models.Task{}, todo.ID).Error)
+	_, err = orgService.UpdateWorkflow(org.ID, kanban)
+	require.NoError(t, err)
+
+	// The removed default status falls back to the first state
+	task, err := env.taskService.CreateTask(services.CreateTaskInput{Title: "Feature", OrganizationID: org.ID, CreatorID: creator.ID})
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatus("BACKLOG"), task.Status)
+
+	_, err = env.taskService.CreateTask(services.CreateTaskInput{Title: "Bad", Status: models.TaskStatusTodo, OrganizationID: org.ID, CreatorID: creator.ID})
+	require.ErrorIs(t, err, services.ErrInvalidTaskStatus)
+
+	transition := func(status string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]string{"status": status})
+		require.NoError(t, err)
+		c, w := newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/transition", body, creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.TransitionTask(c)
+		return w
+	}
+
+	w := transition("REVIEW")
+	require.Equal(t, http.StatusConflict, w.Code, "BACKLOG cannot skip to REVIEW")
+	w = transition("ARCHIVED")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = transition("in progress")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	for _, status := range []string{"IN_PROGRESS", "REVIEW", "DONE"} {
+		w = transition(status)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dto.TaskDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Equal(t, models.TaskStatus(status), response.Status)
+	}
+
+	// Updates go through the same transitions
+	back := models.TaskStatus("BACKLOG")
+	_, err = env.taskService.UpdateTask(task.ID, services.UpdateTaskInput{ActorID: creator.ID, Status: &back})
+	require.ErrorIs(t, err, services.ErrTransitionNotAllowed)
+
+	// Toggling needs a two-state workflow
+	c, w := newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/toggle-status", nil, creator.ID)
+	c.Set(constants.ContextKeyTask, *task)
+	env.handler.ToggleTaskStatus(c)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	_, err = orgService.UpdateWorkflow(org.ID, models.Workflow{
+		States: []models.WorkflowState{
+			{Key: "OPEN", Name: "Open"},
+			{Key: models.TaskStatusDone, Name: "Closed", Done: true},
+		},
+		Transitions: []models.WorkflowTransition{
+			{FromStatus: models.TaskStatusDone, ToStatus: "OPEN"},
+			{FromStatus: "OPEN", ToStatus: models.TaskStatusDone},
+		},
+	})
+	require.NoError(t, err)
+
+	toggled, err := env.taskService.ToggleTaskStatus(task.ID, creator.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatus("OPEN"), toggled.Status)
+	toggled, err = env.taskService.ToggleTaskStatus(task.ID, creator.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusDone, toggled.Status)
+}
+
+func TestTaskHandler_Labels(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+	orgService := services.NewOrganizationService(repository.NewOrganizationRepository(env.db), repository.NewInviteRepository(env.db), nil)
+
+	creator := createUser(t, env.db, "creator")
+	other := createUser(t, env.db, "other")
+	org := createOrganization(t, env.db, "Org")
+	otherOrg := createOrganization(t, env.db, "OtherOrg")
+	addMember(t, env.db, org.ID, creator.ID)
+	addMember(t, env.db, org.ID, other.ID)
+
+	newLabel := func(orgID uint64, name string) *models.Label {
+		label, err := orgService.CreateLabel(orgID, services.LabelInput{Name: name, Color: "#000000"})
+		require.NoError(t, err)
+		return label
+	}
+	bug := newLabel(org.ID, "bug")
+	feature := newLabel(org.ID, "Feature")
+	alien := newLabel(otherOrg.ID, "alien")
+
+	newTask := func(title string) *models.Task {
+		task, err := env.taskService.CreateTask(services.CreateTaskInput{Title: title, OrganizationID: org.ID, CreatorID: creator.ID})
+		require.NoError(t, err)
+		return task
+	}
+	bugOnly := newTask("bug only")
+	both := newTask("both")
+	newTask("none")
+
+	addLabels := func(task *models.Task, userID uint64, labelIDs ...uint64) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string][]uint64{"label_ids": labelIDs})
+		require.NoError(t, err)
+		c, w := newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/labels", body, userID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.AddTaskLabels(c)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, addLabels(bugOnly, creator.ID, bug.ID).Code)
+	w := addLabels(both, creator.ID, feature.ID, bug.ID, bug.ID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var added struct {
+		Labels []dto.LabelDTO `json:"labels"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+	require.Equal(t, []dto.LabelDTO{dto.ToLabelDTO(*bug), dto.ToLabelDTO(*feature)}, added.Labels)
+
+	// Adding a label twice is a no-op
+	require.Equal(t, http.StatusOK, addLabels(both, creator.ID, bug.ID).Code)
+
+	require.Equal(t, http.StatusBadRequest, addLabels(bugOnly, creator.ID, alien.ID).Code)
+	require.Equal(t, http.StatusBadRequest, addLabels(bugOnly, creator.ID).Code)
+	require.Equal(t, http.StatusForbidden, addLabels(bugOnly, other.ID, feature.ID).Code)
+
+	listTitles := func(query string) []string {
+		c, w := newTestContext(http.MethodGet, "/api/tasks?"+query, nil, creator.ID)
+		env.handler.ListTasks(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response dto.TaskListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		titles := make([]string, 0, len(response.Tasks))
+		for _, task := range response.Tasks {
+			titles = append(titles, task.Title)
+			require.NotNil(t, task.Labels)
+		}
+		return titles
+	}
+
+	labelsQuery := fmt.Sprintf("sort=title&labels=%d,%d", bug.ID, feature.ID)
+	require.Equal(t, []string{"both", "bug only"}, listTitles(labelsQuery))
+	require.Equal(t, []string{"both"}, listTitles(labelsQuery+"&label_match=all"))
+	require.Equal(t, []string{"both"}, listTitles(fmt.Sprintf("labels=%d,%d&label_match=all", feature.ID, feature.ID)))
+	require.Equal(t, []string{"both", "bug only", "none"}, listTitles("sort=title"))
+
+	for _, query := range []string{"labels=bug", "labels=1,", fmt.Sprintf("labels=%d&label_match=some", bug.ID)} {
+		c, w := newTestContext(http.MethodGet, "/api/tasks?"+query, nil, creator.ID)
+		env.handler.ListTasks(c)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	c, w := newTestContext(http.MethodDelete, fmt.Sprintf("/api/tasks/%d/labels/%d", both.ID, feature.ID), nil, creator.ID)
+	c.Set(constants.ContextKeyTask, *both)
+	c.Params = gin.Params{{Key: "label_id", Value: strconv.FormatUint(feature.ID, 10)}}
+	env.handler.RemoveTaskLabel(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	task, err := env.taskService.GetTask(both.ID)
+	require.NoError(t, err)
+	require.Equal(t, []dto.LabelDTO{dto.ToLabelDTO(*bug)}, dto.ToTaskDTO(*task).Labels)
+
+	// Deleting a label detaches it from its tasks
+	require.NoError(t, orgService.DeleteLabel(org.ID, bug.ID))
+	require.Empty(t, listTitles(fmt.Sprintf("labels=%d", bug.ID)))
+	task, err = env.taskService.GetTask(bugOnly.ID)
+	require.NoError(t, err)
+	require.Empty(t, dto.ToTaskDTO(*task).Labels)
+}
+
+func TestTaskHandler_Subtasks(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	otherOrg := createOrganization(t, env.db, "OtherOrg")
+	addMember(t, env.db, org.ID, creator.ID)
+	addMember(t, env.db, otherOrg.ID, creator.ID)
+
+	create := func(title string, orgID uint64, parentID *uint64) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]any{"title": title, "organization_id": orgID, "parent_id": parentID})
+		require.NoError(t, err)
+		c, w := newTestContext(http.MethodPost, "/api/tasks", body, creator.ID)
+		env.handler.CreateTask(c)
+		return w
+	}
+	newTask := func(title string, parentID *uint64) *models.Task {
+		w := create(title, org.ID, parentID)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created dto.TaskDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		require.Equal(t, parentID, created.ParentID)
+		task, err := env.taskService.GetTask(created.ID)
+		require.NoError(t, err)
+		return task
+	}
+
+	root := newTask("root", nil)
+	child := newTask("child", &root.ID)
+	grandchild := newTask("grandchild", &child.ID)
+	sibling := newTask("sibling", &root.ID)
+
+	// Depth is bounded and parents must be active tasks of the same organization
+	require.Equal(t, http.StatusBadRequest, create("too deep", org.ID, &grandchild.ID).Code)
+	require.Equal(t, http.StatusBadRequest, create("foreign", otherOrg.ID, &root.ID).Code)
+	missing := uint64(9999)
+	require.Equal(t, http.StatusBadRequest, create("orphan", org.ID, &missing).Code)
+
+	done := models.TaskStatusDone
+	_, err := env.taskService.UpdateTask(sibling.ID, services.UpdateTaskInput{ActorID: creator.ID, Status: &done})
+	require.NoError(t, err)
+
+	get := func(task *models.Task) dto.TaskDTO {
+		c, w := newTestContext(http.MethodGet, "/api/tasks/"+strconv.FormatUint(task.ID, 10), nil, creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.GetTask(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var taskDTO dto.TaskDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &taskDTO))
+		return taskDTO
+	}
+	require.Equal(t, &dto.SubtaskProgressDTO{Done: 1, Total: 2}, get(root).Subtasks)
+	require.Nil(t, get(grandchild).Subtasks)
+
+	c, w := newTestContext(http.MethodGet, "/api/tasks/"+strconv.FormatUint(root.ID, 10)+"/subtasks?sort=title", nil, creator.ID)
+	c.Set(constants.ContextKeyTask, *root)
+	env.handler.ListSubtasks(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var subtasks dto.TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &subtasks))
+	require.Len(t, subtasks.Tasks, 2)
+	require.Equal(t, child.ID, subtasks.Tasks[0].ID)
+	require.Equal(t, &dto.SubtaskProgressDTO{Done: 0, Total: 1}, subtasks.Tasks[0].Subtasks)
+	require.Equal(t, sibling.ID, subtasks.Tasks[1].ID)
+
+	c, w = newTestContext(http.MethodGet, "/api/tasks?top_level=true", nil, creator.ID)
+	env.handler.ListTasks(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var topLevel dto.TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &topLevel))
+	require.Len(t, topLevel.Tasks, 1)
+	require.Equal(t, root.ID, topLevel.Tasks[0].ID)
+
+	update := func(task *models.Task, payload string) *httptest.ResponseRecorder {
+		c, w := newTestContext(http.MethodPatch, "/api/tasks/"+strconv.FormatUint(task.ID, 10), []byte(payload), creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.UpdateTask(c)
+		return w
+	}
+
+	// Moving a task under itself or its subtasks is rejected, as is nesting too deep
+	require.Equal(t, http.StatusBadRequest, update(root, fmt.Sprintf(`{"parent_id": %d}`, grandchild.ID)).Code)
+	require.Equal(t, http.StatusBadRequest, update(root, fmt.Sprintf(`{"parent_id": %d}`, root.ID)).Code)
+	require.Equal(t, http.StatusBadRequest, update(child, fmt.Sprintf(`{"parent_id": %d}`, sibling.ID)).Code)
+	require.Equal(t, http.StatusBadRequest, update(child, `{"parent_id": "root"}`).Code)
+
+	w = update(sibling, `{"parent_id": null}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = update(sibling, fmt.Sprintf(`{"parent_id": %d}`, child.ID))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, &dto.SubtaskProgressDTO{Done: 1, Total: 2}, get(child).Subtasks)
+
+	// Deleting a task moves its whole subtree to the trash
+	require.NoError(t, env.taskService.DeleteTask(child.ID, creator.ID))
+	for _, id := range []uint64{child.ID, grandchild.ID, sibling.ID} {
+		_, err := env.taskService.GetTask(id)
+		require.ErrorIs(t, err, services.ErrTaskNotFound)
+	}
+	require.Nil(t, get(root).Subtasks)
+
+	restore := func(task *models.Task) *httptest.ResponseRecorder {
+		taskID := strconv.FormatUint(task.ID, 10)
+		c, w := newTestContext(http.MethodPost, "/api/organizations/"+strconv.FormatUint(org.ID, 10)+"/trash/"+taskID+"/restore", nil, creator.ID)
+		c.Params = gin.Params{{Key: "task_id", Value: taskID}}
+		c.Set(constants.ContextKeyOrganization, *org)
+		env.handler.RestoreTask(c)
+		return w
+	}
+
+	// A subtask waits for its parent to come back first
+	require.Equal(t, http.StatusConflict, restore(grandchild).Code)
+	w = restore(child)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var restored dto.TaskDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	require.Equal(t, &dto.SubtaskProgressDTO{Done: 1, Total: 2}, restored.Subtasks)
+}
+
+func TestTaskHandler_Dependencies(t *testing.T) {
+	env := setupTaskHandlerTestEnv(t)
+	orgService := services.NewOrganizationService(repository.NewOrganizationRepository(env.db), repository.NewInviteRepository(env.db), nil)
+
+	creator := createUser(t, env.db, "creator")
+	org := createOrganization(t, env.db, "Org")
+	otherOrg := createOrganization(t, env.db, "OtherOrg")
+	addMember(t, env.db, org.ID, creator.ID)
+	addMember(t, env.db, otherOrg.ID, creator.ID)
+
+	newTask := func(title string, orgID uint64, priority models.TaskPriority) *models.Task {
+		task, err := env.taskService.CreateTask(services.CreateTaskInput{
+			Title: title, Priority: priority, OrganizationID: orgID, CreatorID: creator.ID,
+		})
+		require.NoError(t, err)
+		return task
+	}
+	design := newTask("design", org.ID, models.TaskPriorityNormal)
+	build := newTask("build", org.ID, models.TaskPriorityHigh)
+	ship := newTask("ship", org.ID, models.TaskPriorityUrgent)
+	hotfix := newTask("hotfix", org.ID, models.TaskPriorityUrgent)
+	alien := newTask("alien", otherOrg.ID, models.TaskPriorityNormal)
+
+	type dependenciesResponse struct {
+		BlockedBy []dto.TaskListItemDTO `json:"blocked_by"`
+		Blocking  []dto.TaskListItemDTO `json:"blocking"`
+	}
+	addBlocker := func(task, blocker *models.Task) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]uint64{"blocker_id": blocker.ID})
+		require.NoError(t, err)
+		c, w := newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/dependencies", body, creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.AddTaskDependency(c)
+		return w
+	}
+
+	w := addBlocker(build, design)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var added dependenciesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+	require.Len(t, added.BlockedBy, 1)
+	require.Equal(t, design.ID, added.BlockedBy[0].ID)
+	require.False(t, added.BlockedBy[0].IsBlocked)
+	require.Empty(t, added.Blocking)
+	require.Equal(t, http.StatusOK, addBlocker(ship, build).Code)
+
+	// Cycles, self-dependencies and tasks of other organizations are rejected
+	require.Equal(t, http.StatusBadRequest, addBlocker(design, ship).Code)
+	require.Equal(t, http.StatusBadRequest, addBlocker(design, design).Code)
+	require.Equal(t, http.StatusBadRequest, addBlocker(design, alien).Code)
+
+	c, w := newTestContext(http.MethodGet, "/api/tasks/"+strconv.FormatUint(build.ID, 10)+"/dependencies", nil, creator.ID)
+	c.Set(constants.ContextKeyTask, *build)
+	env.handler.ListTaskDependencies(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listed dependenciesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.BlockedBy, 1)
+	require.Len(t, listed.Blocking, 1)
+	require.Equal(t, ship.ID, listed.Blocking[0].ID)
+	require.True(t, listed.Blocking[0].IsBlocked)
+
+	isBlocked := func(task *models.Task) bool {
+		c, w := newTestContext(http.MethodGet, "/api/tasks/"+strconv.FormatUint(task.ID, 10), nil, creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.GetTask(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var taskDTO dto.TaskDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &taskDTO))
+		return taskDTO.IsBlocked
+	}
+	require.False(t, isBlocked(design))
+	require.True(t, isBlocked(build))
+
+	order := func() []uint64 {
+		c, w := newTestContext(http.MethodGet, "/api/organizations/"+strconv.FormatUint(org.ID, 10)+"/tasks/order", nil, creator.ID)
+		c.Set(constants.ContextKeyOrganization, *org)
+		env.handler.ListTaskOrder(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response struct {
+			Tasks []dto.TaskListItemDTO `json:"tasks"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ids := make([]uint64, len(response.Tasks))
+		for i, task := range response.Tasks {
+			ids[i] = task.ID
+		}
+		return ids
+	}
+	// Blockers come first, then priority decides among the tasks that are ready
+	require.Equal(t, []uint64{hotfix.ID, design.ID, build.ID, ship.ID}, order())
+
+	toggle := func(task *models.Task) *httptest.ResponseRecorder {
+		c, w := newTestContext(http.MethodPost, "/api/tasks/"+strconv.FormatUint(task.ID, 10)+"/toggle-status", nil, creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		env.handler.ToggleTaskStatus(c)
+		return w
+	}
+
+	// Organizations enforcing dependencies keep blocked tasks from being completed
+	settings := models.DefaultOrganizationSettings(org.ID)
+	settings.EnforceDependencies = true
+	_, err := orgService.UpdateSettings(org.ID, settings)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, toggle(build).Code)
+
+	require.Equal(t, http.StatusOK, toggle(design).Code)
+	require.False(t, isBlocked(build))
+	w = toggle(build)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, []uint64{ship.ID, hotfix.ID}, order())
+
+	removeBlocker := func(task, blocker *models.Task) *httptest.ResponseRecorder {
+		c, w := newTestContext(http.MethodDelete, fmt.Sprintf("/api/tasks/%d/dependencies/%d", task.ID, blocker.ID), nil, creator.ID)
+		c.Set(constants.ContextKeyTask, *task)
+		c.Params = gin.Params{{Key: "blocker_id", Value: strconv.FormatUint(blocker.ID, 10)}}
+		env.handler.RemoveTaskDependency(c)
+		return w
+	}
+	require.Equal(t, http.StatusOK, removeBlocker(ship, build).Code)
+	require.Equal(t, http.StatusNotFound, removeBlocker(ship, build).Code)
+
+	// Open tasks waiting on each other cannot be ordered and the cycle is named
+	require.NoError(t, env.db.Create(&[]models.TaskDependency{
+		{TaskID: ship.ID, BlockerID: hotfix.ID},
+		{TaskID: hotfix.ID, BlockerID: ship.ID},
+	}).Error)
+	c, w = newTestContext(http.MethodGet, "/api/organizations/"+strconv.FormatUint(org.ID, 10)+"/tasks/order", nil, creator.ID)
+	c.Set(constants.ContextKeyOrganization, *org)
+	env.handler.ListTaskOrder(c)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	var apiErr apierrors.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+	require.Contains(t, apiErr.Message, fmt.Sprintf("%d -> %d -> %d", ship.ID, hotfix.ID, ship.ID))
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/services"
+)
+
+// TwoFactorHandler manages TOTP two-factor authentication settings.
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+	authService      *services.AuthService
+}
+
+// NewTwoFactorHandler creates a new TwoFactorHandler.
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService, authService *services.AuthService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		authService:      authService,
+	}
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GetStatus reports whether two-factor authentication is enabled.
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	status, err := h.twoFactorService.Status(userID)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TwoFactorStatusDTO{
+		Enabled:                status.Enabled,
+		EnabledAt:              status.EnabledAt,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	})
+}
+
+// Enroll generates a TOTP secret and returns it as an otpauth URI.
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(userID)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TwoFactorEnrollmentDTO{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	})
+}
+
+// Activate enables two-factor authentication once the first code is verified.
+func (h *TwoFactorHandler) Activate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	codes, err := h.twoFactorService.Activate(userID, req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes.
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	user, ok := h.checkCodeLockout(c, userID)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		h.respondCodeError(c, user, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// Disable turns off two-factor authentication.
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		apierrors.Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	user, ok := h.checkCodeLockout(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactorService.Disable(userID, req.Code); err != nil {
+		h.respondCodeError(c, user, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// checkCodeLockout loads the user and rejects the request while their logins
+// are locked. Wrong codes here count towards the same lockout as at login so
+// that a stolen session cannot be used to guess them.
+func (h *TwoFactorHandler) checkCodeLockout(c *gin.Context, userID uint64) (*models.User, bool) {
+	user, err := h.authService.GetUser(userID)
+	if err != nil {
+		respondAuthError(c, err)
+		return nil, false
+	}
+	if err := h.authService.CheckLoginLockout(user, c.ClientIP()); err != nil {
+		respondAuthError(c, err)
+		return nil, false
+	}
+	return user, true
+}
+
+// respondCodeError records a wrong code as a failed second factor before
+// writing the error.
+func (h *TwoFactorHandler) respondCodeError(c *gin.Context, user *models.User, err error) {
+	if errors.Is(err, services.ErrInvalidTwoFactorCode) {
+		if lockErr := h.authService.RecordFailedSecondFactor(user, c.ClientIP()); lockErr != nil {
+			respondAuthError(c, lockErr)
+			return
+		}
+	}
+	respondTwoFactorError(c, err)
+}
+
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		apierrors.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnrolled),
+		errors.Is(err, services.ErrInvalidTwoFactorCode):
+		apierrors.BadRequest(c, err.Error())
+	default:
+		respondAuthError(c, err)
+	}
+}
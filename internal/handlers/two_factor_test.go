@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	"github.com/yukikurage/task-management-api/internal/dto"
+	"github.com/yukikurage/task-management-api/internal/middleware"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/services"
+	"github.com/yukikurage/task-management-api/internal/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTwoFactorTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.UserSession{},
+	)
+	require.NoError(t, err)
+
+	database.SetDB(db)
+
+	userRepo := repository.NewUserRepository(db)
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db))
+	authService := newTestAuthService(db, sessionService, &recordingNotifier{})
+	twoFactorService := services.NewTwoFactorService(repository.NewTwoFactorRepository(db), userRepo)
+	authHandler := NewAuthHandler(authService, twoFactorService, sessionService)
+	twoFactorHandler := NewTwoFactorHandler(twoFactorService, authService)
+
+	_, err = authService.Signup(services.SignupInput{Username: "secure", Password: "supersecret"})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(sessions.Sessions(constants.SessionCookieName, cookie.NewStore([]byte("secret"))))
+	r.POST("/api/auth/login", authHandler.Login)
+	r.POST("/api/auth/login/2fa", authHandler.VerifyTwoFactorLogin)
+	r.GET("/api/auth/me", middleware.RequireAuth(), authHandler.GetCurrentUser)
+	twoFactor := r.Group("/api/auth/2fa", middleware.RequireAuth())
+	twoFactor.GET("", twoFactorHandler.GetStatus)
+	twoFactor.POST("/enroll", twoFactorHandler.Enroll)
+	twoFactor.POST("/activate", twoFactorHandler.Activate)
+	twoFactor.POST("/disable", twoFactorHandler.Disable)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return r
+}
+
+// sessionClient sends requests with the session cookie from previous responses.
+type sessionClient struct {
+	t         *testing.T
+	router    *gin.Engine
+	cookies   []*http.Cookie
+	userAgent string
+}
+
+func (c *sessionClient) do(method, path string, payload interface{}) *httptest.ResponseRecorder {
+	c.t.Helper()
+
+	var body bytes.Buffer
+	if payload != nil {
+		require.NoError(c.t, json.NewEncoder(&body).Encode(payload))
+	}
+
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	c.router.ServeHTTP(w, req)
+	if cookies := w.Result().Cookies(); len(cookies) > 0 {
+		c.cookies = cookies
+	}
+	return w
+}
+
+func (c *sessionClient) login() *httptest.ResponseRecorder {
+	return c.do(http.MethodPost, "/api/auth/login", map[string]string{
+		"username": "secure",
+		"password": "supersecret",
+	})
+}
+
+// enableTwoFactor enrolls the user and returns the secret, the time step of
+// the activation code and the recovery codes.
+func enableTwoFactor(t *testing.T, r *gin.Engine) (string, int64, []string) {
+	t.Helper()
+
+	client := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusOK, client.login().Code)
+
+	w := client.do(http.MethodPost, "/api/auth/2fa/enroll", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var enrollment dto.TwoFactorEnrollmentDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &enrollment))
+	require.True(t, strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/"))
+	require.Contains(t, enrollment.OTPAuthURI, "secret="+enrollment.Secret)
+
+	// Not enabled until a code is verified
+	w = client.do(http.MethodPost, "/api/auth/2fa/activate", map[string]string{"code": "000000"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	step := utils.TOTPStep(time.Now())
+	code, err := utils.TOTPCode(enrollment.Secret, step)
+	require.NoError(t, err)
+	w = client.do(http.MethodPost, "/api/auth/2fa/activate", map[string]string{"code": code})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var recovery dto.RecoveryCodesDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &recovery))
+	require.Len(t, recovery.RecoveryCodes, constants.RecoveryCodeCount)
+
+	w = client.do(http.MethodGet, "/api/auth/2fa", nil)
+	var status dto.TwoFactorStatusDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.True(t, status.Enabled)
+	require.Equal(t, int64(constants.RecoveryCodeCount), status.RecoveryCodesRemaining)
+
+	return enrollment.Secret, step, recovery.RecoveryCodes
+}
+
+func TestTwoFactor_LoginRequiresSecondFactor(t *testing.T) {
+	r := setupTwoFactorTestRouter(t)
+	secret, step, _ := enableTwoFactor(t, r)
+
+	client := &sessionClient{t: t, router: r}
+	w := client.login()
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Contains(t, w.Body.String(), "two_factor_required")
+
+	// The half-authenticated session cannot access the API
+	require.Equal(t, http.StatusUnauthorized, client.do(http.MethodGet, "/api/auth/me", nil).Code)
+
+	// The code used for activation cannot be replayed
+	usedCode, err := utils.TOTPCode(secret, step)
+	require.NoError(t, err)
+	w = client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": usedCode})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	nextCode, err := utils.TOTPCode(secret, step+1)
+	require.NoError(t, err)
+	w = client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": nextCode})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var user dto.UserDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	require.Equal(t, "secure", user.Username)
+	require.Equal(t, http.StatusOK, client.do(http.MethodGet, "/api/auth/me", nil).Code)
+}
+
+func TestTwoFactor_RecoveryCodeIsSingleUse(t *testing.T) {
+	r := setupTwoFactorTestRouter(t)
+	_, _, recoveryCodes := enableTwoFactor(t, r)
+
+	client := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusAccepted, client.login().Code)
+	w := client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{
+		"code": strings.ToUpper(recoveryCodes[0]),
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = client.do(http.MethodGet, "/api/auth/2fa", nil)
+	var status dto.TwoFactorStatusDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.Equal(t, int64(constants.RecoveryCodeCount-1), status.RecoveryCodesRemaining)
+
+	other := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusAccepted, other.login().Code)
+	w = other.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": recoveryCodes[0]})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Disabling with a recovery code turns the second step off again
+	w = client.do(http.MethodPost, "/api/auth/2fa/disable", map[string]string{"code": recoveryCodes[1]})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, http.StatusOK, (&sessionClient{t: t, router: r}).login().Code)
+}
+
+func TestTwoFactor_TooManyWrongCodesDiscardPendingLogin(t *testing.T) {
+	r := setupTwoFactorTestRouter(t)
+	_, _, recoveryCodes := enableTwoFactor(t, r)
+
+	client := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusAccepted, client.login().Code)
+
+	for i := 0; i < constants.TwoFactorMaxAttempts; i++ {
+		w := client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": "not-a-code"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	w := client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": recoveryCodes[0]})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "No pending two-factor login")
+}
+
+func TestTwoFactor_WrongCodesCountTowardsLoginLockout(t *testing.T) {
+	r := setupTwoFactorTestRouter(t)
+	enableTwoFactor(t, r)
+
+	// A correct password starts a fresh pending login but does not reset the wrong codes
+	for i := 0; i < constants.LoginMaxFailuresPerUsername; i++ {
+		client := &sessionClient{t: t, router: r}
+		require.Equal(t, http.StatusAccepted, client.login().Code)
+		w := client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": "not-a-code"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	w := (&sessionClient{t: t, router: r}).login()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestTwoFactor_WrongCodesInSessionCountTowardsLockout(t *testing.T) {
+	r := setupTwoFactorTestRouter(t)
+	_, _, recoveryCodes := enableTwoFactor(t, r)
+
+	client := &sessionClient{t: t, router: r}
+	require.Equal(t, http.StatusAccepted, client.login().Code)
+	w := client.do(http.MethodPost, "/api/auth/login/2fa", map[string]string{"code": recoveryCodes[0]})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// A signed-in session cannot be used to keep guessing codes
+	for i := 0; i < constants.LoginMaxFailuresPerUsername; i++ {
+		w := client.do(http.MethodPost, "/api/auth/2fa/disable", map[string]string{"code": "not-a-code"})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	w = client.do(http.MethodPost, "/api/auth/2fa/disable", map[string]string{"code": recoveryCodes[1]})
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = client.do(http.MethodGet, "/api/auth/2fa", nil)
+	var status dto.TwoFactorStatusDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.True(t, status.Enabled)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
+)
+
+// RequireSessionAuth rejects requests authenticated with a personal access token.
+// Used for endpoints such as token management that must not be scriptable.
+func RequireSessionAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get(constants.ContextKeyAccessToken); exists {
+			apierrors.RespondWithError(c, http.StatusForbidden, apierrors.NewAPIError(
+				apierrors.ErrCodeInsufficientPermissions,
+				"This endpoint requires a session login",
+			))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerToken extracts a token from an "Authorization: Bearer <token>" header
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return "", false
+	}
+
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// authenticateAccessToken validates a personal access token, enforces its
+// scopes and stores the owning user in the context
+func authenticateAccessToken(c *gin.Context, rawToken string) {
+	tokenRepo := repository.NewAccessTokenRepository(database.GetDB())
+
+	token, err := tokenRepo.FindByHash(utils.HashToken(rawToken))
+	if err != nil {
+		apierrors.Unauthorized(c, "Invalid access token")
+		c.Abort()
+		return
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		apierrors.Unauthorized(c, "Access token has expired or been revoked")
+		c.Abort()
+		return
+	}
+
+	if !isSafeMethod(c.Request.Method) && !token.HasScope(models.TokenScopeWrite) {
+		apierrors.RespondWithError(c, http.StatusForbidden, apierrors.NewAPIError(
+			apierrors.ErrCodeInsufficientPermissions,
+			"Access token does not have the write scope",
+		))
+		c.Abort()
+		return
+	}
+
+	// Throttle last-used updates to avoid a write on every request
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= constants.AccessTokenLastUsedUpdateInterval {
+		if err := tokenRepo.TouchLastUsed(token.ID, now); err == nil {
+			token.LastUsedAt = &now
+		}
+	}
+
+	c.Set(constants.ContextKeyUserID, token.UserID)
+	c.Set(constants.ContextKeyAccessToken, *token)
+	c.Next()
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
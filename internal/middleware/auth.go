@@ -4,24 +4,18 @@ import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/yukikurage/task-management-api/internal/constants"
-	apierrors "github.com/yukikurage/task-management-api/internal/errors"
 )
 
-// RequireAuth checks if the user is authenticated via session
+// RequireAuth checks if the user is authenticated via a registered session
+// or a personal access token in the Authorization header
 func RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		session := sessions.Default(c)
-		userID := session.Get(constants.ContextKeyUserID)
-
-		if userID == nil {
-			apierrors.Unauthorized(c, "")
-			c.Abort()
+		if rawToken, ok := bearerToken(c); ok {
+			authenticateAccessToken(c, rawToken)
 			return
 		}
 
-		// Store user ID in context for easy access in handlers
-		c.Set(constants.ContextKeyUserID, userID)
-		c.Next()
+		authenticateSession(c, sessions.Default(c))
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/yukikurage/task-management-api/internal/database"
 	apierrors "github.com/yukikurage/task-management-api/internal/errors"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
 )
 
 // RequireOrganizationAccess checks if the user is a member of the organization
@@ -55,27 +56,45 @@ func RequireOrganizationAccess() gin.HandlerFunc {
 	}
 }
 
-// RequireOrganizationOwner checks if the user is an owner of the organization
-func RequireOrganizationOwner() gin.HandlerFunc {
+// RequireOrganizationPermission checks that the user's role may perform an
+// organization-wide action under the default policy
+func RequireOrganizationPermission(action policy.Action) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get organization member from context (set by RequireOrganizationAccess)
-		memberInterface, exists := c.Get(constants.ContextKeyOrganizationMember)
+		member, ok := organizationMemberFromContext(c)
+		if !ok {
+			return
+		}
+
+		actor := policy.Actor{UserID: member.UserID, Role: member.Role}
+		if !policy.Default().Allows(actor, action, policy.Resource{}) {
+			apierrors.Forbidden(c, "Your organization role does not allow this action")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireActiveOrganization rejects changes to an archived organization
+func RequireActiveOrganization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgInterface, exists := c.Get(constants.ContextKeyOrganization)
 		if !exists {
 			apierrors.Forbidden(c, "Organization access required")
 			c.Abort()
 			return
 		}
 
-		member, ok := memberInterface.(models.OrganizationMember)
+		org, ok := orgInterface.(models.Organization)
 		if !ok {
-			apierrors.InternalError(c, "Invalid organization member data")
+			apierrors.InternalError(c, "Invalid organization data")
 			c.Abort()
 			return
 		}
 
-		// Check if user is owner
-		if member.Role != models.RoleOwner {
-			apierrors.Forbidden(c, "Only organization owners can perform this action")
+		if org.IsArchived() {
+			apierrors.Conflict(c, "Organization is archived and read-only")
 			c.Abort()
 			return
 		}
@@ -83,3 +102,23 @@ func RequireOrganizationOwner() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// organizationMemberFromContext returns the membership set by RequireOrganizationAccess,
+// aborting the request if it is missing
+func organizationMemberFromContext(c *gin.Context) (models.OrganizationMember, bool) {
+	memberInterface, exists := c.Get(constants.ContextKeyOrganizationMember)
+	if !exists {
+		apierrors.Forbidden(c, "Organization access required")
+		c.Abort()
+		return models.OrganizationMember{}, false
+	}
+
+	member, ok := memberInterface.(models.OrganizationMember)
+	if !ok {
+		apierrors.InternalError(c, "Invalid organization member data")
+		c.Abort()
+		return models.OrganizationMember{}, false
+	}
+
+	return member, true
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/database"
+	apierrors "github.com/yukikurage/task-management-api/internal/errors"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
+)
+
+// authenticateSession checks the cookie session against the server-side
+// registry so that revoked or expired sessions are rejected immediately
+func authenticateSession(c *gin.Context, session sessions.Session) {
+	userID := session.Get(constants.ContextKeyUserID)
+	token, _ := session.Get(constants.SessionKeySessionToken).(string)
+
+	if userID == nil || token == "" {
+		rejectSession(c, session)
+		return
+	}
+
+	sessionRepo := repository.NewSessionRepository(database.GetDB())
+
+	record, err := sessionRepo.FindByHash(utils.HashToken(token))
+	now := time.Now()
+	if err != nil || !record.IsActive(now) {
+		rejectSession(c, session)
+		return
+	}
+
+	// Store user ID in context for easy access in handlers
+	c.Set(constants.ContextKeyUserID, userID)
+	if id, ok := GetUserID(c); !ok || id != record.UserID {
+		rejectSession(c, session)
+		return
+	}
+
+	// Throttle last-seen updates to avoid a write on every request
+	if now.Sub(record.LastSeenAt) >= constants.SessionLastSeenUpdateInterval {
+		sessionRepo.TouchLastSeen(record.ID, now)
+	}
+
+	c.Set(constants.ContextKeySessionID, record.ID)
+	c.Next()
+}
+
+// rejectSession clears a stale session cookie and aborts with 401
+func rejectSession(c *gin.Context, session sessions.Session) {
+	if session.Get(constants.ContextKeyUserID) != nil {
+		session.Clear()
+		session.Save()
+	}
+	apierrors.Unauthorized(c, "")
+	c.Abort()
+}
+
+// GetSessionID retrieves the registry ID of the current login session from context
+func GetSessionID(c *gin.Context) (uint64, bool) {
+	sessionID, exists := c.Get(constants.ContextKeySessionID)
+	if !exists {
+		return 0, false
+	}
+	id, ok := sessionID.(uint64)
+	return id, ok
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// AuditAction names a change recorded in an organization's audit log.
+type AuditAction string
+
+const (
+	AuditActionTaskCreate       AuditAction = "task.create"
+	AuditActionTaskUpdate       AuditAction = "task.update"
+	AuditActionTaskDelete       AuditAction = "task.delete"
+	AuditActionTaskRestore      AuditAction = "task.restore"
+	AuditActionTaskAssign       AuditAction = "task.assign"
+	AuditActionTaskUnassign     AuditAction = "task.unassign"
+	AuditActionTaskToggleStatus AuditAction = "task.toggle_status"
+	AuditActionTaskTransition   AuditAction = "task.transition"
+	AuditActionTaskLabel        AuditAction = "task.label"
+	AuditActionTaskUnlabel      AuditAction = "task.unlabel"
+	AuditActionTaskBlock        AuditAction = "task.block"
+	AuditActionTaskUnblock      AuditAction = "task.unblock"
+
+	AuditActionMemberJoin       AuditAction = "member.join"
+	AuditActionMemberRemove     AuditAction = "member.remove"
+	AuditActionMemberLeave      AuditAction = "member.leave"
+	AuditActionMemberRoleChange AuditAction = "member.role_change"
+
+	AuditActionOrganizationRename            AuditAction = "organization.rename"
+	AuditActionOrganizationDelete            AuditAction = "organization.delete"
+	AuditActionOrganizationArchive           AuditAction = "organization.archive"
+	AuditActionOrganizationRestore           AuditAction = "organization.restore"
+	AuditActionOrganizationRegenerateCode    AuditAction = "organization.regenerate_invite_code"
+	AuditActionOrganizationTransferOwnership AuditAction = "organization.transfer_ownership"
+)
+
+// AuditTargetType names the kind of object an audit log entry is about.
+type AuditTargetType string
+
+const (
+	AuditTargetTask         AuditTargetType = "task"
+	AuditTargetMember       AuditTargetType = "member"
+	AuditTargetOrganization AuditTargetType = "organization"
+)
+
+// AuditLogEntry records who changed what in an organization. Entries are
+// never updated; they go away only when the organization is purged.
+type AuditLogEntry struct {
+	ID             uint64          `gorm:"primarykey" json:"id"`
+	OrganizationID uint64          `gorm:"not null;index" json:"organization_id"`
+	ActorID        uint64          `gorm:"not null;index" json:"actor_id"`
+	Action         AuditAction     `gorm:"type:varchar(64);not null" json:"action"`
+	TargetType     AuditTargetType `gorm:"type:varchar(20);not null" json:"target_type"`
+	// TargetID is the task ID, the member's user ID or the organization ID
+	TargetID uint64 `gorm:"not null" json:"target_id"`
+	// Changes is a JSON object mapping changed fields to their before and after values
+	Changes   string `gorm:"type:text;not null" json:"changes"`
+	IPAddress string `gorm:"type:varchar(45)" json:"ip_address"`
+	UserAgent string `gorm:"type:varchar(255)" json:"user_agent"`
+	// AccessTokenID is set when the change was made with a personal access token
+	AccessTokenID *uint64   `json:"access_token_id"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+
+	// Relations
+	Actor User `gorm:"foreignKey:ActorID" json:"-"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Label categorizes tasks within an organization. Names are unique within an
+// organization, ignoring case.
+type Label struct {
+	ID             uint64 `gorm:"primarykey" json:"id"`
+	OrganizationID uint64 `gorm:"not null;uniqueIndex:idx_labels_org_name" json:"organization_id"`
+	Name           string `gorm:"type:varchar(50);not null;uniqueIndex:idx_labels_org_name" json:"name"`
+	// Color is a lower case hex RGB color such as #1f883d
+	Color     string    `gorm:"type:varchar(7);not null" json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskLabel attaches a label to a task. Tasks keep their labels while in the
+// trash.
+type TaskLabel struct {
+	TaskID    uint64    `gorm:"primarykey" json:"task_id"`
+	LabelID   uint64    `gorm:"primarykey;index" json:"label_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Label Label `gorm:"foreignKey:LabelID" json:"label,omitempty"`
+}
@@ -10,6 +10,7 @@ type Organization struct {
 	ID         uint64         `gorm:"primarykey" json:"id"`
 	Name       string         `gorm:"type:varchar(255);not null" json:"name"`
 	InviteCode string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"invite_code"`
+	ArchivedAt *time.Time     `json:"archived_at"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
@@ -18,3 +19,8 @@ type Organization struct {
 	Members []OrganizationMember `gorm:"foreignKey:OrganizationID" json:"members,omitempty"`
 	Tasks   []Task               `gorm:"foreignKey:OrganizationID" json:"tasks,omitempty"`
 }
+
+// IsArchived reports whether the organization has been archived and is read-only.
+func (o Organization) IsArchived() bool {
+	return o.ArchivedAt != nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusDeclined InvitationStatus = "declined"
+	InvitationStatusCanceled InvitationStatus = "canceled"
+	// InvitationStatusExpired is never stored; pending invitations past their
+	// expiry are reported as expired
+	InvitationStatusExpired InvitationStatus = "expired"
+)
+
+// OrganizationInvitation invites a specific user to join an organization with
+// the role chosen by the inviter
+type OrganizationInvitation struct {
+	ID             uint64           `gorm:"primarykey" json:"id"`
+	OrganizationID uint64           `gorm:"not null;index" json:"organization_id"`
+	InviteeID      uint64           `gorm:"not null;index" json:"invitee_id"`
+	InviterID      uint64           `gorm:"not null" json:"inviter_id"`
+	Role           OrganizationRole `gorm:"type:varchar(20);not null" json:"role"`
+	Status         InvitationStatus `gorm:"type:varchar(20);not null" json:"status"`
+	ExpiresAt      time.Time        `gorm:"not null" json:"expires_at"`
+	RespondedAt    *time.Time       `json:"responded_at"`
+	CreatedAt      time.Time        `json:"created_at"`
+
+	// Relations
+	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Invitee      User         `gorm:"foreignKey:InviteeID" json:"invitee,omitempty"`
+	Inviter      User         `gorm:"foreignKey:InviterID" json:"inviter,omitempty"`
+}
+
+// EffectiveStatus returns the stored status, reporting pending invitations
+// past their expiry as expired
+func (i *OrganizationInvitation) EffectiveStatus(now time.Time) InvitationStatus {
+	if i.Status == InvitationStatusPending && !now.Before(i.ExpiresAt) {
+		return InvitationStatusExpired
+	}
+	return i.Status
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+type InviteStatus string
+
+const (
+	InviteStatusActive  InviteStatus = "active"
+	InviteStatusExpired InviteStatus = "expired"
+	InviteStatusRevoked InviteStatus = "revoked"
+	InviteStatusUsedUp  InviteStatus = "used_up"
+)
+
+// OrganizationInvite is a named invite link that adds users to an organization
+// with a pre-assigned role. It can expire, be limited to a number of uses and
+// be revoked.
+type OrganizationInvite struct {
+	ID             uint64           `gorm:"primarykey" json:"id"`
+	OrganizationID uint64           `gorm:"not null;index" json:"organization_id"`
+	Name           string           `gorm:"type:varchar(100);not null" json:"name"`
+	Code           string           `gorm:"type:varchar(64);uniqueIndex;not null" json:"code"`
+	Role           OrganizationRole `gorm:"type:varchar(20);not null" json:"role"`
+	MaxUses        *int             `json:"max_uses"`
+	UseCount       int              `gorm:"not null;default:0" json:"use_count"`
+	ExpiresAt      *time.Time       `json:"expires_at"`
+	RevokedAt      *time.Time       `json:"revoked_at"`
+	CreatedByID    uint64           `gorm:"not null" json:"created_by_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+
+	// Relations
+	CreatedBy User                    `gorm:"foreignKey:CreatedByID" json:"-"`
+	Uses      []OrganizationInviteUse `gorm:"foreignKey:InviteID" json:"uses,omitempty"`
+}
+
+// Status reports whether the invite can still be used at the given time
+func (i *OrganizationInvite) Status(now time.Time) InviteStatus {
+	switch {
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case i.ExpiresAt != nil && !now.Before(*i.ExpiresAt):
+		return InviteStatusExpired
+	case i.MaxUses != nil && i.UseCount >= *i.MaxUses:
+		return InviteStatusUsedUp
+	default:
+		return InviteStatusActive
+	}
+}
+
+// OrganizationInviteUse records a user joining through an invite
+type OrganizationInviteUse struct {
+	ID       uint64    `gorm:"primarykey" json:"id"`
+	InviteID uint64    `gorm:"not null;index" json:"invite_id"`
+	UserID   uint64    `gorm:"not null;index" json:"user_id"`
+	UsedAt   time.Time `gorm:"not null" json:"used_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
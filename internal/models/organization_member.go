@@ -5,10 +5,26 @@ import "time"
 type OrganizationRole string
 
 const (
-	RoleOwner  OrganizationRole = "owner"
+	// RoleOwner has full control, including deleting the organization and changing roles
+	RoleOwner OrganizationRole = "owner"
+	// RoleAdmin manages members and invites but cannot delete the organization
+	RoleAdmin OrganizationRole = "admin"
+	// RoleMember works with tasks
 	RoleMember OrganizationRole = "member"
+	// RoleGuest has read-only access
+	RoleGuest OrganizationRole = "guest"
 )
 
+// IsValid reports whether the role is one of the known roles
+func (r OrganizationRole) IsValid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember, RoleGuest:
+		return true
+	default:
+		return false
+	}
+}
+
 type OrganizationMember struct {
 	OrganizationID uint64           `gorm:"primarykey" json:"organization_id"`
 	UserID         uint64           `gorm:"primarykey" json:"user_id"`
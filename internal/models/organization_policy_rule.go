@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// OrganizationPolicyRule overrides the default scope a role has for a policy
+// action within one organization
+type OrganizationPolicyRule struct {
+	OrganizationID uint64           `gorm:"primarykey" json:"organization_id"`
+	Action         string           `gorm:"primarykey;type:varchar(50)" json:"action"`
+	Role           OrganizationRole `gorm:"primarykey;type:varchar(20)" json:"role"`
+	Scope          string           `gorm:"type:varchar(20);not null" json:"scope"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// OrganizationSettings holds an organization's preferences and quotas.
+// Organizations without a stored row use DefaultOrganizationSettings.
+type OrganizationSettings struct {
+	OrganizationID    uint64     `gorm:"primarykey" json:"organization_id"`
+	DefaultTaskStatus TaskStatus `gorm:"type:varchar(20);not null" json:"default_task_status"`
+	// MembersCanInvite lets members invite other members and guests
+	MembersCanInvite bool `gorm:"not null" json:"members_can_invite"`
+	// DefaultDueTime is the HH:MM time of day given to due dates set without one
+	DefaultDueTime      string `gorm:"type:varchar(5);not null" json:"default_due_time"`
+	Timezone            string `gorm:"type:varchar(64);not null" json:"timezone"`
+	AIGenerationEnabled bool   `gorm:"not null" json:"ai_generation_enabled"`
+	// EnforceDependencies keeps tasks out of done states while any of their
+	// blockers is still open
+	EnforceDependencies bool `gorm:"not null;default:false" json:"enforce_dependencies"`
+
+	// Quotas, nil means unlimited
+	MaxMembers          *int `json:"max_members"`
+	MaxTasks            *int `json:"max_tasks"`
+	AIGenerationsPerDay *int `json:"ai_generations_per_day"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultOrganizationSettings returns the settings of an organization that
+// has not changed any.
+func DefaultOrganizationSettings(organizationID uint64) OrganizationSettings {
+	return OrganizationSettings{
+		OrganizationID:      organizationID,
+		DefaultTaskStatus:   TaskStatusTodo,
+		DefaultDueTime:      "23:59",
+		Timezone:            "UTC",
+		AIGenerationEnabled: true,
+	}
+}
+
+// Location returns the organization's time zone, falling back to UTC.
+func (s OrganizationSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// OrganizationAIUsage counts AI task generations of an organization on one
+// day of its time zone
+type OrganizationAIUsage struct {
+	OrganizationID uint64 `gorm:"primarykey" json:"organization_id"`
+	// Day is formatted as YYYY-MM-DD
+	Day   string `gorm:"primarykey;type:varchar(10)" json:"day"`
+	Count int    `gorm:"not null" json:"count"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type TokenScope string
+
+const (
+	// TokenScopeRead allows safe (read-only) requests
+	TokenScopeRead TokenScope = "read"
+	// TokenScopeWrite allows requests that modify data
+	TokenScopeWrite TokenScope = "write"
+)
+
+// PersonalAccessToken is a long-lived credential a user can mint for scripts and CI.
+// Only the SHA-256 hash of the token is stored.
+type PersonalAccessToken struct {
+	ID          uint64     `gorm:"primarykey" json:"id"`
+	UserID      uint64     `gorm:"not null;index" json:"user_id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	TokenHash   string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	TokenPrefix string     `gorm:"type:varchar(16);not null" json:"token_prefix"`
+	Scopes      string     `gorm:"type:varchar(255);not null" json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// ScopeList returns the token scopes as a slice
+func (t *PersonalAccessToken) ScopeList() []TokenScope {
+	if t.Scopes == "" {
+		return []TokenScope{}
+	}
+	parts := strings.Split(t.Scopes, ",")
+	scopes := make([]TokenScope, len(parts))
+	for i, p := range parts {
+		scopes[i] = TokenScope(p)
+	}
+	return scopes
+}
+
+// HasScope reports whether the token was granted the given scope
+func (t *PersonalAccessToken) HasScope(scope TokenScope) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the token is neither revoked nor expired at the given time
+func (t *PersonalAccessToken) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
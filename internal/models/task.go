@@ -13,14 +13,38 @@ const (
 	TaskStatusDone TaskStatus = "DONE"
 )
 
+type TaskPriority string
+
+const (
+	TaskPriorityUrgent TaskPriority = "urgent"
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityNormal TaskPriority = "normal"
+	TaskPriorityLow    TaskPriority = "low"
+)
+
+// TaskPriorities lists the priorities from most to least urgent
+var TaskPriorities = []TaskPriority{TaskPriorityUrgent, TaskPriorityHigh, TaskPriorityNormal, TaskPriorityLow}
+
+// IsValid reports whether the priority is one of the known priorities
+func (p TaskPriority) IsValid() bool {
+	for _, priority := range TaskPriorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
+
 type Task struct {
 	ID             uint64         `gorm:"primarykey" json:"id"`
 	Title          string         `gorm:"not null" json:"title"`
 	Description    string         `gorm:"type:text" json:"description"`
 	Status         TaskStatus     `gorm:"type:varchar(20);not null;default:'TODO'" json:"status"`
+	Priority       TaskPriority   `gorm:"type:varchar(10);not null;default:'normal'" json:"priority"`
 	DueDate        *time.Time     `json:"due_date"`
 	CreatorID      uint64         `gorm:"not null" json:"creator_id"`
 	OrganizationID uint64         `gorm:"not null" json:"organization_id"`
+	ParentID       *uint64        `gorm:"index" json:"parent_id"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -29,4 +53,12 @@ type Task struct {
 	Creator      User             `gorm:"foreignKey:CreatorID" json:"creator,omitempty"`
 	Organization Organization     `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 	Assignments  []TaskAssignment `gorm:"foreignKey:TaskID" json:"assignments,omitempty"`
+	Labels       []TaskLabel      `gorm:"foreignKey:TaskID" json:"labels,omitempty"`
+}
+
+// SubtaskProgress counts the direct subtasks of a task and how many of them
+// are in a done state of the organization's workflow
+type SubtaskProgress struct {
+	Done  int64
+	Total int64
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// TaskDependency records that a task is blocked by another task of the same
+// organization until the blocker reaches a done state. Dependencies stay in
+// place while either task is in the trash.
+type TaskDependency struct {
+	TaskID    uint64    `gorm:"primarykey" json:"task_id"`
+	BlockerID uint64    `gorm:"primarykey;index" json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
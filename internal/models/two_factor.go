@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// UserTwoFactor holds a user's TOTP secret. EnabledAt stays nil while
+// enrollment is pending, until the first code has been verified.
+type UserTwoFactor struct {
+	UserID       uint64     `gorm:"primarykey;autoIncrement:false" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// IsEnabled reports whether enrollment has been completed
+func (t *UserTwoFactor) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// RecoveryCode is a one-time code that can replace a TOTP code.
+// Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint64     `gorm:"primarykey" json:"id"`
+	UserID    uint64     `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
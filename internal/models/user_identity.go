@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to an account at an external OpenID Connect provider.
+// The (Issuer, Subject) pair uniquely identifies the external account.
+type UserIdentity struct {
+	ID        uint64    `gorm:"primarykey" json:"id"`
+	UserID    uint64    `gorm:"not null;index" json:"user_id"`
+	Issuer    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject" json:"issuer"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject" json:"subject"`
+	Email     string    `gorm:"type:varchar(255)" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// UserSession is a server-side record of a login session. The session cookie
+// carries a random token; only its SHA-256 hash is stored.
+type UserSession struct {
+	ID         uint64     `gorm:"primarykey" json:"id"`
+	UserID     uint64     `gorm:"not null;index" json:"user_id"`
+	TokenHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UserAgent  string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(45)" json:"ip_address"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// IsActive reports whether the session is neither revoked nor expired at the given time
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
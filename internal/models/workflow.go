@@ -0,0 +1,85 @@
+package models
+
+import "regexp"
+
+// taskStatusPattern is the shape of workflow state keys, like IN_PROGRESS
+var taskStatusPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,19}$`)
+
+// IsValid reports whether the status is a well-formed workflow state key.
+// Whether an organization uses it depends on its workflow.
+func (s TaskStatus) IsValid() bool {
+	return taskStatusPattern.MatchString(string(s))
+}
+
+// WorkflowState is one of the statuses tasks of an organization move through.
+type WorkflowState struct {
+	ID             uint64     `gorm:"primarykey" json:"-"`
+	OrganizationID uint64     `gorm:"not null;uniqueIndex:idx_workflow_states_org_key" json:"-"`
+	Key            TaskStatus `gorm:"type:varchar(20);not null;uniqueIndex:idx_workflow_states_org_key" json:"key"`
+	Name           string     `gorm:"type:varchar(50);not null" json:"name"`
+	Position       int        `gorm:"not null" json:"-"`
+	// Done marks states that count as finished, like DONE in the default workflow
+	Done bool `gorm:"not null" json:"done"`
+}
+
+// WorkflowTransition allows tasks to move from one state to another.
+type WorkflowTransition struct {
+	ID             uint64     `gorm:"primarykey" json:"-"`
+	OrganizationID uint64     `gorm:"not null;index" json:"-"`
+	FromStatus     TaskStatus `gorm:"type:varchar(20);not null" json:"from"`
+	ToStatus       TaskStatus `gorm:"type:varchar(20);not null" json:"to"`
+}
+
+// Workflow is an organization's ordered task states and the transitions
+// allowed between them. Organizations without stored states use
+// DefaultWorkflow.
+type Workflow struct {
+	States      []WorkflowState
+	Transitions []WorkflowTransition
+}
+
+// DefaultWorkflow returns the TODO/DONE workflow of an organization that has
+// not defined its own.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		States: []WorkflowState{
+			{Key: TaskStatusTodo, Name: "To do", Position: 0},
+			{Key: TaskStatusDone, Name: "Done", Position: 1, Done: true},
+		},
+		Transitions: []WorkflowTransition{
+			{FromStatus: TaskStatusTodo, ToStatus: TaskStatusDone},
+			{FromStatus: TaskStatusDone, ToStatus: TaskStatusTodo},
+		},
+	}
+}
+
+// State returns the state with the given key.
+func (w Workflow) State(key TaskStatus) (WorkflowState, bool) {
+	for _, state := range w.States {
+		if state.Key == key {
+			return state, true
+		}
+	}
+	return WorkflowState{}, false
+}
+
+// Allows reports whether tasks may move directly from one state to another.
+func (w Workflow) Allows(from, to TaskStatus) bool {
+	for _, transition := range w.Transitions {
+		if transition.FromStatus == from && transition.ToStatus == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DoneStatuses lists the keys of the states that count as finished.
+func (w Workflow) DoneStatuses() []TaskStatus {
+	statuses := make([]TaskStatus, 0, len(w.States))
+	for _, state := range w.States {
+		if state.Done {
+			statuses = append(statuses, state.Key)
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PasswordResetNotification carries what a user needs to reset their password.
+type PasswordResetNotification struct {
+	UserID    uint64    `json:"user_id"`
+	Username  string    `json:"username"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResetLink appends the token to the password reset page URL.
+func ResetLink(resetURL, token string) string {
+	separator := "?"
+	if strings.Contains(resetURL, "?") {
+		separator = "&"
+	}
+	return resetURL + separator + "token=" + url.QueryEscape(token)
+}
+
+// Notifier delivers account notifications to users. Implementations can
+// send email, chat messages, etc.; the log and file sinks are meant for
+// local development.
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, notification PasswordResetNotification) error
+}
+
+// LogNotifier writes notifications to the standard logger.
+type LogNotifier struct {
+	resetURL string
+}
+
+// NewLogNotifier creates a Notifier that logs every notification.
+// resetURL is the page that accepts the reset token.
+func NewLogNotifier(resetURL string) *LogNotifier {
+	return &LogNotifier{resetURL: resetURL}
+}
+
+// SendPasswordReset logs the reset link.
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, notification PasswordResetNotification) error {
+	log.Printf("Password reset for %s (user %d): %s (expires %s)",
+		notification.Username,
+		notification.UserID,
+		ResetLink(n.resetURL, notification.Token),
+		notification.ExpiresAt.Format(time.RFC3339),
+	)
+	return nil
+}
+
+// FileNotifier appends notifications as JSON lines to a file.
+type FileNotifier struct {
+	path     string
+	resetURL string
+	mu       sync.Mutex
+}
+
+// NewFileNotifier creates a Notifier that appends to the file at path.
+// resetURL is the page that accepts the reset token.
+func NewFileNotifier(path, resetURL string) *FileNotifier {
+	return &FileNotifier{path: path, resetURL: resetURL}
+}
+
+// SendPasswordReset appends the notification to the file.
+func (n *FileNotifier) SendPasswordReset(ctx context.Context, notification PasswordResetNotification) error {
+	return n.append(struct {
+		Type string `json:"type"`
+		PasswordResetNotification
+		ResetLink string    `json:"reset_link"`
+		SentAt    time.Time `json:"sent_at"`
+	}{
+		Type:                      "password_reset",
+		PasswordResetNotification: notification,
+		ResetLink:                 ResetLink(n.resetURL, notification.Token),
+		SentAt:                    time.Now(),
+	})
+}
+
+func (n *FileNotifier) append(record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// Package policy decides what organization members may do.
+//
+// A Policy maps every action and role to a scope. The scope says which
+// resources the role may act on: none, the ones the actor created, the ones
+// the actor created or is assigned to, or any. Organizations start from
+// Default and owners may override the scopes of task actions.
+package policy
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+// Action is something a member does within an organization.
+// ActionTaskToggleStatus covers toggling as well as workflow transitions.
+type Action string
+
+const (
+	ActionTaskCreate       Action = "task.create"
+	ActionTaskUpdate       Action = "task.update"
+	ActionTaskDelete       Action = "task.delete"
+	ActionTaskAssign       Action = "task.assign"
+	ActionTaskToggleStatus Action = "task.toggle_status"
+
+	ActionOrganizationUpdate  Action = "organization.update"
+	ActionOrganizationDelete  Action = "organization.delete"
+	ActionOrganizationArchive Action = "organization.archive"
+	ActionManageInvites       Action = "organization.manage_invites"
+	ActionRemoveMembers       Action = "organization.remove_members"
+	ActionChangeRoles         Action = "organization.change_roles"
+	ActionTransferOwnership   Action = "organization.transfer_ownership"
+	ActionManagePolicy        Action = "organization.manage_policy"
+	ActionManageSettings      Action = "organization.manage_settings"
+	ActionManageWorkflow      Action = "organization.manage_workflow"
+	ActionManageLabels        Action = "organization.manage_labels"
+	ActionViewAuditLog        Action = "organization.view_audit_log"
+)
+
+// Scope limits which resources a role may perform an action on.
+type Scope string
+
+const (
+	// ScopeNone denies the action
+	ScopeNone Scope = "none"
+	// ScopeOwn allows the action on resources the actor created
+	ScopeOwn Scope = "own"
+	// ScopeAssigned allows the action on resources the actor created or is assigned to
+	ScopeAssigned Scope = "assigned"
+	// ScopeAny allows the action on every resource in the organization
+	ScopeAny Scope = "any"
+)
+
+var (
+	ErrUnknownAction       = errors.New("unknown policy action")
+	ErrActionNotCustomized = errors.New("policy action cannot be customized")
+	ErrInvalidRole         = errors.New("invalid organization role")
+	ErrInvalidScope        = errors.New("invalid policy scope for this action")
+)
+
+// Actor is the member performing an action.
+type Actor struct {
+	UserID uint64
+	Role   models.OrganizationRole
+}
+
+// Resource describes the object an action targets. Organization-wide actions
+// use the zero value.
+type Resource struct {
+	CreatorID   uint64
+	AssigneeIDs []uint64
+	CreatedAt   time.Time
+}
+
+// TaskResource describes a task. Assignments must be loaded for ScopeAssigned
+// to match assignees.
+func TaskResource(task models.Task) Resource {
+	assignees := make([]uint64, len(task.Assignments))
+	for i, assignment := range task.Assignments {
+		assignees[i] = assignment.UserID
+	}
+	return Resource{
+		CreatorID:   task.CreatorID,
+		AssigneeIDs: assignees,
+		CreatedAt:   task.CreatedAt,
+	}
+}
+
+// Roles lists the organization roles from most to least privileged.
+var Roles = []models.OrganizationRole{
+	models.RoleOwner,
+	models.RoleAdmin,
+	models.RoleMember,
+	models.RoleGuest,
+}
+
+// CustomizableActions lists the actions whose scopes organizations may override.
+var CustomizableActions = []Action{
+	ActionTaskCreate,
+	ActionTaskUpdate,
+	ActionTaskDelete,
+	ActionTaskAssign,
+	ActionTaskToggleStatus,
+}
+
+type rules map[Action]map[models.OrganizationRole]Scope
+
+// defaults grants each role its built-in scopes. Roles missing from an action
+// are denied.
+var defaults = rules{
+	ActionTaskCreate: {
+		models.RoleOwner:  ScopeAny,
+		models.RoleAdmin:  ScopeAny,
+		models.RoleMember: ScopeAny,
+	},
+	ActionTaskUpdate: {
+		models.RoleOwner:  ScopeOwn,
+		models.RoleAdmin:  ScopeOwn,
+		models.RoleMember: ScopeOwn,
+	},
+	ActionTaskDelete: {
+		models.RoleOwner:  ScopeOwn,
+		models.RoleAdmin:  ScopeOwn,
+		models.RoleMember: ScopeOwn,
+	},
+	ActionTaskAssign: {
+		models.RoleOwner:  ScopeOwn,
+		models.RoleAdmin:  ScopeOwn,
+		models.RoleMember: ScopeOwn,
+	},
+	ActionTaskToggleStatus: {
+		models.RoleOwner:  ScopeAssigned,
+		models.RoleAdmin:  ScopeAssigned,
+		models.RoleMember: ScopeAssigned,
+	},
+	ActionOrganizationUpdate: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionOrganizationDelete: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionOrganizationArchive: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionManageInvites: {
+		models.RoleOwner: ScopeAny,
+		models.RoleAdmin: ScopeAny,
+	},
+	ActionRemoveMembers: {
+		models.RoleOwner: ScopeAny,
+		models.RoleAdmin: ScopeAny,
+	},
+	ActionChangeRoles: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionTransferOwnership: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionManagePolicy: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionManageSettings: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionManageWorkflow: {
+		models.RoleOwner: ScopeAny,
+	},
+	ActionManageLabels: {
+		models.RoleOwner: ScopeAny,
+		models.RoleAdmin: ScopeAny,
+	},
+	ActionViewAuditLog: {
+		models.RoleOwner: ScopeAny,
+	},
+}
+
+// Policy answers permission questions for one organization.
+type Policy struct {
+	overrides rules
+}
+
+// Default returns the built-in policy without organization overrides.
+func Default() Policy {
+	return Policy{}
+}
+
+// WithOverrides returns a copy of the policy with the organization's stored
+// rules applied. Rules that fail Validate are ignored.
+func (p Policy) WithOverrides(overrides []models.OrganizationPolicyRule) Policy {
+	merged := rules{}
+	for action, byRole := range p.overrides {
+		merged[action] = map[models.OrganizationRole]Scope{}
+		for role, scope := range byRole {
+			merged[action][role] = scope
+		}
+	}
+
+	for _, rule := range overrides {
+		if Validate(rule) != nil {
+			continue
+		}
+		action := Action(rule.Action)
+		if merged[action] == nil {
+			merged[action] = map[models.OrganizationRole]Scope{}
+		}
+		merged[action][rule.Role] = Scope(rule.Scope)
+	}
+
+	return Policy{overrides: merged}
+}
+
+// Scope returns the scope a role has for an action.
+func (p Policy) Scope(role models.OrganizationRole, action Action) Scope {
+	if scope, ok := p.overrides[action][role]; ok {
+		return scope
+	}
+	if scope, ok := defaults[action][role]; ok {
+		return scope
+	}
+	return ScopeNone
+}
+
+// Overridden reports whether the organization changed the scope of a role for an action.
+func (p Policy) Overridden(role models.OrganizationRole, action Action) bool {
+	_, ok := p.overrides[action][role]
+	return ok
+}
+
+// Allows reports whether the actor may perform the action on the resource.
+func (p Policy) Allows(actor Actor, action Action, resource Resource) bool {
+	return p.Scope(actor.Role, action).Covers(actor.UserID, resource)
+}
+
+// Covers reports whether the scope includes the resource for the given user.
+func (s Scope) Covers(userID uint64, resource Resource) bool {
+	switch s {
+	case ScopeAny:
+		return true
+	case ScopeOwn:
+		return resource.CreatorID != 0 && resource.CreatorID == userID
+	case ScopeAssigned:
+		if resource.CreatorID != 0 && resource.CreatorID == userID {
+			return true
+		}
+		for _, assigneeID := range resource.AssigneeIDs {
+			if assigneeID == userID {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// Validate checks that a stored rule names a customizable action, a known
+// role and a scope that applies to the action.
+func Validate(rule models.OrganizationPolicyRule) error {
+	action := Action(rule.Action)
+	if _, ok := defaults[action]; !ok {
+		return ErrUnknownAction
+	}
+	if !isCustomizable(action) {
+		return ErrActionNotCustomized
+	}
+	if !rule.Role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	switch Scope(rule.Scope) {
+	case ScopeNone, ScopeAny:
+		return nil
+	case ScopeOwn, ScopeAssigned:
+		// Creating a task has no existing resource to be scoped to
+		if action == ActionTaskCreate {
+			return ErrInvalidScope
+		}
+		return nil
+	default:
+		return ErrInvalidScope
+	}
+}
+
+// CanManageRole reports whether a member with the actor role may remove or
+// otherwise manage a member with the target role. Owners manage everyone;
+// admins only manage members and guests.
+func CanManageRole(actor, target models.OrganizationRole) bool {
+	switch actor {
+	case models.RoleOwner:
+		return true
+	case models.RoleAdmin:
+		return target == models.RoleMember || target == models.RoleGuest
+	default:
+		return false
+	}
+}
+
+// CanInvite reports whether a member with the actor role may invite someone
+// with the target role. Owners and admins invite the roles they manage;
+// members invite members and guests when the organization allows it.
+func CanInvite(actor, target models.OrganizationRole, membersCanInvite bool) bool {
+	if CanManageRole(actor, target) {
+		return true
+	}
+	return membersCanInvite && actor == models.RoleMember &&
+		(target == models.RoleMember || target == models.RoleGuest)
+}
+
+func isCustomizable(action Action) bool {
+	for _, customizable := range CustomizableActions {
+		if customizable == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+const (
+	creatorID  uint64 = 1
+	assigneeID uint64 = 2
+	otherID    uint64 = 3
+)
+
+var task = Resource{CreatorID: creatorID, AssigneeIDs: []uint64{creatorID, assigneeID}}
+
+func TestDefault_Allows(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   Action
+		role     models.OrganizationRole
+		userID   uint64
+		resource Resource
+		want     bool
+	}{
+		{"member creates task", ActionTaskCreate, models.RoleMember, otherID, Resource{}, true},
+		{"guest cannot create task", ActionTaskCreate, models.RoleGuest, otherID, Resource{}, false},
+		{"creator updates own task", ActionTaskUpdate, models.RoleMember, creatorID, task, true},
+		{"assignee cannot update task", ActionTaskUpdate, models.RoleMember, assigneeID, task, false},
+		{"owner cannot update others' task", ActionTaskUpdate, models.RoleOwner, otherID, task, false},
+		{"creator deletes own task", ActionTaskDelete, models.RoleAdmin, creatorID, task, true},
+		{"guest creator cannot delete", ActionTaskDelete, models.RoleGuest, creatorID, task, false},
+		{"creator assigns users", ActionTaskAssign, models.RoleMember, creatorID, task, true},
+		{"assignee cannot assign users", ActionTaskAssign, models.RoleMember, assigneeID, task, false},
+		{"assignee toggles status", ActionTaskToggleStatus, models.RoleMember, assigneeID, task, true},
+		{"creator toggles status", ActionTaskToggleStatus, models.RoleMember, creatorID, task, true},
+		{"unrelated member cannot toggle", ActionTaskToggleStatus, models.RoleMember, otherID, task, false},
+		{"assigned guest cannot toggle", ActionTaskToggleStatus, models.RoleGuest, assigneeID, task, false},
+		{"owner updates organization", ActionOrganizationUpdate, models.RoleOwner, otherID, Resource{}, true},
+		{"admin cannot update organization", ActionOrganizationUpdate, models.RoleAdmin, otherID, Resource{}, false},
+		{"owner deletes organization", ActionOrganizationDelete, models.RoleOwner, otherID, Resource{}, true},
+		{"admin cannot delete organization", ActionOrganizationDelete, models.RoleAdmin, otherID, Resource{}, false},
+		{"owner archives organization", ActionOrganizationArchive, models.RoleOwner, otherID, Resource{}, true},
+		{"admin cannot archive organization", ActionOrganizationArchive, models.RoleAdmin, otherID, Resource{}, false},
+		{"admin manages invites", ActionManageInvites, models.RoleAdmin, otherID, Resource{}, true},
+		{"member cannot manage invites", ActionManageInvites, models.RoleMember, otherID, Resource{}, false},
+		{"admin removes members", ActionRemoveMembers, models.RoleAdmin, otherID, Resource{}, true},
+		{"guest cannot remove members", ActionRemoveMembers, models.RoleGuest, otherID, Resource{}, false},
+		{"owner changes roles", ActionChangeRoles, models.RoleOwner, otherID, Resource{}, true},
+		{"admin cannot change roles", ActionChangeRoles, models.RoleAdmin, otherID, Resource{}, false},
+		{"owner transfers ownership", ActionTransferOwnership, models.RoleOwner, otherID, Resource{}, true},
+		{"admin cannot transfer ownership", ActionTransferOwnership, models.RoleAdmin, otherID, Resource{}, false},
+		{"owner manages policy", ActionManagePolicy, models.RoleOwner, otherID, Resource{}, true},
+		{"admin cannot manage policy", ActionManagePolicy, models.RoleAdmin, otherID, Resource{}, false},
+		{"unknown role is denied", ActionTaskCreate, models.OrganizationRole("superuser"), otherID, Resource{}, false},
+		{"unknown action is denied", Action("task.archive"), models.RoleOwner, creatorID, task, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := Actor{UserID: tt.userID, Role: tt.role}
+			require.Equal(t, tt.want, Default().Allows(actor, tt.action, tt.resource))
+		})
+	}
+}
+
+func TestPolicy_WithOverrides(t *testing.T) {
+	p := Default().WithOverrides([]models.OrganizationPolicyRule{
+		{Action: string(ActionTaskUpdate), Role: models.RoleMember, Scope: string(ScopeAny)},
+		{Action: string(ActionTaskToggleStatus), Role: models.RoleGuest, Scope: string(ScopeAssigned)},
+		{Action: string(ActionTaskDelete), Role: models.RoleAdmin, Scope: string(ScopeNone)},
+		// Invalid rules are ignored
+		{Action: string(ActionOrganizationDelete), Role: models.RoleMember, Scope: string(ScopeAny)},
+		{Action: string(ActionTaskCreate), Role: models.RoleGuest, Scope: string(ScopeOwn)},
+	})
+
+	tests := []struct {
+		name   string
+		action Action
+		role   models.OrganizationRole
+		userID uint64
+		want   bool
+	}{
+		{"member updates any task", ActionTaskUpdate, models.RoleMember, otherID, true},
+		{"admin keeps default update scope", ActionTaskUpdate, models.RoleAdmin, otherID, false},
+		{"assigned guest toggles status", ActionTaskToggleStatus, models.RoleGuest, assigneeID, true},
+		{"unassigned guest cannot toggle", ActionTaskToggleStatus, models.RoleGuest, otherID, false},
+		{"admin cannot delete own task", ActionTaskDelete, models.RoleAdmin, creatorID, false},
+		{"organization actions cannot be overridden", ActionOrganizationDelete, models.RoleMember, otherID, false},
+		{"invalid scope is ignored", ActionTaskCreate, models.RoleGuest, otherID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := Actor{UserID: tt.userID, Role: tt.role}
+			require.Equal(t, tt.want, p.Allows(actor, tt.action, task))
+		})
+	}
+
+	require.True(t, p.Overridden(models.RoleMember, ActionTaskUpdate))
+	require.False(t, p.Overridden(models.RoleAdmin, ActionTaskUpdate))
+	require.False(t, Default().Overridden(models.RoleMember, ActionTaskUpdate), "overrides do not leak into the defaults")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.OrganizationPolicyRule
+		want error
+	}{
+		{"customizable action", models.OrganizationPolicyRule{Action: "task.update", Role: models.RoleMember, Scope: "any"}, nil},
+		{"create with any", models.OrganizationPolicyRule{Action: "task.create", Role: models.RoleGuest, Scope: "any"}, nil},
+		{"create scoped to own", models.OrganizationPolicyRule{Action: "task.create", Role: models.RoleGuest, Scope: "own"}, ErrInvalidScope},
+		{"unknown scope", models.OrganizationPolicyRule{Action: "task.update", Role: models.RoleMember, Scope: "everyone"}, ErrInvalidScope},
+		{"unknown action", models.OrganizationPolicyRule{Action: "task.archive", Role: models.RoleMember, Scope: "any"}, ErrUnknownAction},
+		{"organization action", models.OrganizationPolicyRule{Action: "organization.delete", Role: models.RoleAdmin, Scope: "any"}, ErrActionNotCustomized},
+		{"unknown role", models.OrganizationPolicyRule{Action: "task.update", Role: "superuser", Scope: "any"}, ErrInvalidRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, Validate(tt.rule), tt.want)
+		})
+	}
+}
+
+func TestCanManageRole(t *testing.T) {
+	tests := []struct {
+		actor  models.OrganizationRole
+		target models.OrganizationRole
+		want   bool
+	}{
+		{models.RoleOwner, models.RoleOwner, true},
+		{models.RoleOwner, models.RoleAdmin, true},
+		{models.RoleAdmin, models.RoleOwner, false},
+		{models.RoleAdmin, models.RoleAdmin, false},
+		{models.RoleAdmin, models.RoleMember, true},
+		{models.RoleAdmin, models.RoleGuest, true},
+		{models.RoleMember, models.RoleGuest, false},
+		{models.RoleGuest, models.RoleGuest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.actor)+"/"+string(tt.target), func(t *testing.T) {
+			require.Equal(t, tt.want, CanManageRole(tt.actor, tt.target))
+		})
+	}
+}
+
+func TestCanInvite(t *testing.T) {
+	tests := []struct {
+		actor            models.OrganizationRole
+		target           models.OrganizationRole
+		membersCanInvite bool
+		want             bool
+	}{
+		{models.RoleAdmin, models.RoleMember, false, true},
+		{models.RoleAdmin, models.RoleAdmin, true, false},
+		{models.RoleMember, models.RoleMember, false, false},
+		{models.RoleMember, models.RoleMember, true, true},
+		{models.RoleMember, models.RoleGuest, true, true},
+		{models.RoleMember, models.RoleAdmin, true, false},
+		{models.RoleGuest, models.RoleGuest, true, false},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s/%s/members_can_invite=%t", tt.actor, tt.target, tt.membersCanInvite)
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, CanInvite(tt.actor, tt.target, tt.membersCanInvite))
+		})
+	}
+}
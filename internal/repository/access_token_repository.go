@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormAccessTokenRepository is a GORM implementation of AccessTokenRepository
+type GormAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessTokenRepository creates a new AccessTokenRepository
+func NewAccessTokenRepository(db *gorm.DB) AccessTokenRepository {
+	return &GormAccessTokenRepository{db: db}
+}
+
+// Create creates a new personal access token
+func (r *GormAccessTokenRepository) Create(token *models.PersonalAccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash finds a token by the hash of its secret value
+func (r *GormAccessTokenRepository) FindByHash(hash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByIDForUser finds a token by ID that belongs to the given user
+func (r *GormAccessTokenRepository) FindByIDForUser(id, userID uint64) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUserID lists all tokens owned by a user, newest first
+func (r *GormAccessTokenRepository) ListByUserID(userID uint64) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	if err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Revoke marks a token as revoked
+func (r *GormAccessTokenRepository) Revoke(id uint64, revokedAt time.Time) error {
+	return r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt).Error
+}
+
+// TouchLastUsed records when a token was last used
+func (r *GormAccessTokenRepository) TouchLastUsed(id uint64, usedAt time.Time) error {
+	return r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormAuditLogRepository is a GORM implementation of AuditLogRepository
+type GormAuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new AuditLogRepository
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &GormAuditLogRepository{db: db}
+}
+
+// Create appends an entry
+func (r *GormAuditLogRepository) Create(entry *models.AuditLogEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// List lists an organization's entries matching the filter with their
+// actors, newest first. IDs grow with time, so ordering by ID keeps cursors stable.
+func (r *GormAuditLogRepository) List(filter AuditLogFilter) ([]models.AuditLogEntry, error) {
+	query := r.db.Preload("Actor", includeDeletedUsers).
+		Where("organization_id = ?", filter.OrganizationID)
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != nil {
+		query = query.Where("action = ?", *filter.Action)
+	}
+	if filter.TargetType != nil {
+		query = query.Where("target_type = ?", *filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.BeforeID != nil {
+		query = query.Where("id < ?", *filter.BeforeID)
+	}
+
+	var entries []models.AuditLogEntry
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormInvitationRepository is a GORM implementation of InvitationRepository
+type GormInvitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository creates a new InvitationRepository
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &GormInvitationRepository{db: db}
+}
+
+// Create creates a new invitation
+func (r *GormInvitationRepository) Create(invitation *models.OrganizationInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+// FindByID finds an invitation with its organization and inviter
+func (r *GormInvitationRepository) FindByID(id uint64) (*models.OrganizationInvitation, error) {
+	var invitation models.OrganizationInvitation
+	if err := r.db.Preload("Organization").
+		Preload("Inviter", includeDeletedUsers).
+		First(&invitation, id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPending finds an unexpired pending invitation of a user to an organization
+func (r *GormInvitationRepository) FindPending(organizationID, inviteeID uint64, now time.Time) (*models.OrganizationInvitation, error) {
+	var invitation models.OrganizationInvitation
+	if err := r.db.Where("organization_id = ? AND invitee_id = ? AND status = ? AND expires_at > ?",
+		organizationID, inviteeID, models.InvitationStatusPending, now).
+		First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListByOrganizationID lists the invitations sent by an organization, newest first
+func (r *GormInvitationRepository) ListByOrganizationID(organizationID uint64) ([]models.OrganizationInvitation, error) {
+	var invitations []models.OrganizationInvitation
+	if err := r.db.Preload("Invitee").
+		Preload("Inviter", includeDeletedUsers).
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// ListByInviteeID lists the invitations a user received, newest first
+func (r *GormInvitationRepository) ListByInviteeID(inviteeID uint64) ([]models.OrganizationInvitation, error) {
+	var invitations []models.OrganizationInvitation
+	if err := r.db.Preload("Organization").
+		Preload("Inviter", includeDeletedUsers).
+		Where("invitee_id = ?", inviteeID).
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// Respond moves a pending invitation to the given status, returning false if
+// it was no longer pending
+func (r *GormInvitationRepository) Respond(id uint64, status models.InvitationStatus, respondedAt time.Time) (bool, error) {
+	return respondToInvitation(r.db, id, status, respondedAt)
+}
+
+// Accept marks a pending invitation as accepted and adds the member within a
+// single transaction, returning false if it was no longer pending
+func (r *GormInvitationRepository) Accept(id uint64, member *models.OrganizationMember, maxMembers *int) (bool, error) {
+	accepted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockMemberCapacity(tx, member.OrganizationID, maxMembers); err != nil {
+			return err
+		}
+
+		ok, err := respondToInvitation(tx, id, models.InvitationStatusAccepted, member.JoinedAt)
+		if err != nil || !ok {
+			return err
+		}
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+		accepted = true
+		return nil
+	})
+	return accepted, err
+}
+
+func respondToInvitation(db *gorm.DB, id uint64, status models.InvitationStatus, respondedAt time.Time) (bool, error) {
+	result := db.Model(&models.OrganizationInvitation{}).
+		Where("id = ? AND status = ?", id, models.InvitationStatusPending).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"responded_at": respondedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
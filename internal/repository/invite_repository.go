@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormInviteRepository is a GORM implementation of InviteRepository
+type GormInviteRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository creates a new InviteRepository
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &GormInviteRepository{db: db}
+}
+
+// Create creates a new invite
+func (r *GormInviteRepository) Create(invite *models.OrganizationInvite) error {
+	return r.db.Create(invite).Error
+}
+
+// FindByCode finds an invite by its code
+func (r *GormInviteRepository) FindByCode(code string) (*models.OrganizationInvite, error) {
+	var invite models.OrganizationInvite
+	if err := r.db.Where("code = ?", code).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// FindByIDForOrganization finds an invite of the given organization with the
+// users who joined through it
+func (r *GormInviteRepository) FindByIDForOrganization(id, organizationID uint64) (*models.OrganizationInvite, error) {
+	var invite models.OrganizationInvite
+	if err := r.db.Preload("Uses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("used_at")
+	}).Preload("Uses.User").
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// ListByOrganizationID lists all invites of an organization, newest first
+func (r *GormInviteRepository) ListByOrganizationID(organizationID uint64) ([]models.OrganizationInvite, error) {
+	var invites []models.OrganizationInvite
+	if err := r.db.Where("organization_id = ?", organizationID).
+		Order("created_at DESC, id DESC").
+		Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// Revoke marks an invite as revoked
+func (r *GormInviteRepository) Revoke(id uint64, revokedAt time.Time) error {
+	return r.db.Model(&models.OrganizationInvite{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt).Error
+}
+
+// Redeem counts a use of the invite and adds the member within a single
+// transaction. The use count is only incremented while the invite is still
+// usable, so concurrent joins cannot exceed the limit.
+func (r *GormInviteRepository) Redeem(invite *models.OrganizationInvite, member *models.OrganizationMember, maxMembers *int) (bool, error) {
+	redeemed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockMemberCapacity(tx, member.OrganizationID, maxMembers); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.OrganizationInvite{}).
+			Where("id = ? AND revoked_at IS NULL", invite.ID).
+			Where("expires_at IS NULL OR expires_at > ?", member.JoinedAt).
+			Where("max_uses IS NULL OR use_count < max_uses").
+			Update("use_count", gorm.Expr("use_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.OrganizationInviteUse{
+			InviteID: invite.ID,
+			UserID:   member.UserID,
+			UsedAt:   member.JoinedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		redeemed = true
+		return nil
+	})
+	return redeemed, err
+}
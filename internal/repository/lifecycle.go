@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// Every path that deletes organizations, removes members or deletes tasks goes
+// through the helpers in this file, so rows that depend on them never outlive
+// them. A table added later that hangs off one of these has to be handled here.
+// The audit log is the exception: it keeps its entries about removed members
+// and deleted tasks, and a deleted organization's log stays until the retention
+// purge removes the organization for good.
+
+// softDeleteTasks soft-deletes the active tasks matching the condition together
+// with their active assignments. Both share the deletion time so restoring a
+// task brings back exactly the assignments removed with it.
+func softDeleteTasks(tx *gorm.DB, at time.Time, query string, args ...interface{}) error {
+	taskIDs := tx.Model(&models.Task{}).Select("id").Where(query, args...)
+	if err := tx.Model(&models.TaskAssignment{}).Where("task_id IN (?)", taskIDs).
+		Update("deleted_at", at).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Task{}).Where(query, args...).Update("deleted_at", at).Error
+}
+
+// collectSubtasks returns the IDs of the tasks below the given one at any
+// depth, walking the hierarchy one level at a time. Scopes narrow down which
+// task rows are followed.
+func collectSubtasks(tx *gorm.DB, taskID uint64, scopes ...func(*gorm.DB) *gorm.DB) ([]uint64, error) {
+	var subtaskIDs []uint64
+	parentIDs := []uint64{taskID}
+	for len(parentIDs) > 0 {
+		var childIDs []uint64
+		if err := tx.Model(&models.Task{}).Scopes(scopes...).
+			Where("parent_id IN ?", parentIDs).
+			Pluck("id", &childIDs).Error; err != nil {
+			return nil, err
+		}
+		subtaskIDs = append(subtaskIDs, childIDs...)
+		parentIDs = childIDs
+	}
+	return subtaskIDs, nil
+}
+
+// removeMembership removes a member and all of their assignments to the
+// organization's tasks, including tasks in the trash, so neither a later
+// restore nor rejoining brings the assignments back.
+func removeMembership(tx *gorm.DB, organizationID, userID uint64) error {
+	orgTaskIDs := tx.Unscoped().Model(&models.Task{}).Select("id").Where("organization_id = ?", organizationID)
+	if err := tx.Unscoped().Where("user_id = ? AND task_id IN (?)", userID, orgTaskIDs).
+		Delete(&models.TaskAssignment{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&models.OrganizationMember{}).Error
+}
+
+// deleteOrganization soft-deletes an organization and its tasks and removes
+// everything else that belongs to it inside an existing transaction.
+func deleteOrganization(tx *gorm.DB, id uint64, at time.Time) error {
+	// Tasks and their assignments stay in the trash until the retention purge
+	if err := softDeleteTasks(tx, at, "organization_id = ?", id); err != nil {
+		return err
+	}
+
+	// Delete all members
+	if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+		return err
+	}
+
+	// Delete invites and their usage history
+	if err := tx.Where("invite_id IN (?)", tx.Model(&models.OrganizationInvite{}).Select("id").Where("organization_id = ?", id)).
+		Delete(&models.OrganizationInviteUse{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationInvite{}).Error; err != nil {
+		return err
+	}
+
+	// Delete targeted invitations
+	if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationInvitation{}).Error; err != nil {
+		return err
+	}
+
+	// Delete policy overrides
+	if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationPolicyRule{}).Error; err != nil {
+		return err
+	}
+
+	// Delete labels and detach them from the tasks in the trash
+	if err := tx.Where("label_id IN (?)", tx.Model(&models.Label{}).Select("id").Where("organization_id = ?", id)).
+		Delete(&models.TaskLabel{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("organization_id = ?", id).Delete(&models.Label{}).Error; err != nil {
+		return err
+	}
+
+	// Delete the workflow
+	if err := tx.Where("organization_id = ?", id).Delete(&models.WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("organization_id = ?", id).Delete(&models.WorkflowState{}).Error; err != nil {
+		return err
+	}
+
+	// Delete settings and usage counters
+	if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationSettings{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationAIUsage{}).Error; err != nil {
+		return err
+	}
+
+	// Delete organization
+	return tx.Model(&models.Organization{}).Where("id = ?", id).Update("deleted_at", at).Error
+}
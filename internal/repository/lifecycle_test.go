@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/database/migrations"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRepositoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	require.NoError(t, migrations.NewRunner(db, migrations.All()).Up())
+	return db
+}
+
+// setupConcurrentRepositoryTestDB opens a database file shared by several
+// connections. Each transaction takes the write lock as it begins, so
+// concurrent transactions run one after another the way they do on MySQL and
+// PostgreSQL once they lock the same row, while statements outside a
+// transaction still interleave.
+func setupConcurrentRepositoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_txlock=immediate&_busy_timeout=10000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	require.NoError(t, migrations.NewRunner(db, migrations.All()).Up())
+	return db
+}
+
+// orphanChecks are queries counting rows that outlived what they depend on.
+var orphanChecks = map[string]string{
+	"assignments without a task": `SELECT COUNT(*) FROM task_assignments a
+		LEFT JOIN tasks t ON t.id = a.task_id WHERE t.id IS NULL`,
+	"active assignments of deleted tasks": `SELECT COUNT(*) FROM task_assignments a
+		JOIN tasks t ON t.id = a.task_id WHERE a.deleted_at IS NULL AND t.deleted_at IS NOT NULL`,
+	"active assignments of non-members": `SELECT COUNT(*) FROM task_assignments a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE a.deleted_at IS NULL AND t.deleted_at IS NULL AND NOT EXISTS (
+			SELECT 1 FROM organization_members m
+			WHERE m.organization_id = t.organization_id AND m.user_id = a.user_id)`,
+	"assignments of removed members in the trash": `SELECT COUNT(*) FROM task_assignments a
+		JOIN tasks t ON t.id = a.task_id
+		JOIN organizations o ON o.id = t.organization_id AND o.deleted_at IS NULL
+		WHERE NOT EXISTS (
+			SELECT 1 FROM organization_members m
+			WHERE m.organization_id = t.organization_id AND m.user_id = a.user_id)`,
+	"tasks of missing or deleted organizations": `SELECT COUNT(*) FROM tasks t
+		LEFT JOIN organizations o ON o.id = t.organization_id
+		WHERE o.id IS NULL OR (t.deleted_at IS NULL AND o.deleted_at IS NOT NULL)`,
+	"members of missing or deleted organizations": `SELECT COUNT(*) FROM organization_members x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"invites of missing or deleted organizations": `SELECT COUNT(*) FROM organization_invites x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"invite uses without an invite": `SELECT COUNT(*) FROM organization_invite_uses u
+		LEFT JOIN organization_invites i ON i.id = u.invite_id WHERE i.id IS NULL`,
+	"invitations of missing or deleted organizations": `SELECT COUNT(*) FROM organization_invitations x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"policy rules of missing or deleted organizations": `SELECT COUNT(*) FROM organization_policy_rules x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"settings of missing or deleted organizations": `SELECT COUNT(*) FROM organization_settings x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"AI usage of missing or deleted organizations": `SELECT COUNT(*) FROM organization_ai_usages x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"labels of missing or deleted organizations": `SELECT COUNT(*) FROM labels x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL OR o.deleted_at IS NOT NULL`,
+	"subtasks of missing, deleted or foreign parents": `SELECT COUNT(*) FROM tasks x
+		LEFT JOIN tasks p ON p.id = x.parent_id
+		WHERE x.parent_id IS NOT NULL AND (p.id IS NULL OR p.organization_id <> x.organization_id
+			OR (x.deleted_at IS NULL AND p.deleted_at IS NOT NULL))`,
+	"task labels without a task or label": `SELECT COUNT(*) FROM task_labels x
+		LEFT JOIN tasks t ON t.id = x.task_id
+		LEFT JOIN labels l ON l.id = x.label_id WHERE t.id IS NULL OR l.id IS NULL`,
+	"task dependencies without a task": `SELECT COUNT(*) FROM task_dependencies x
+		LEFT JOIN tasks t ON t.id = x.task_id
+		LEFT JOIN tasks b ON b.id = x.blocker_id WHERE t.id IS NULL OR b.id IS NULL`,
+	// A deleted organization keeps its audit log until the purge
+	"audit log entries of missing organizations": `SELECT COUNT(*) FROM audit_log_entries x
+		LEFT JOIN organizations o ON o.id = x.organization_id WHERE o.id IS NULL`,
+}
+
+func requireNoOrphans(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	for name, query := range orphanChecks {
+		var count int64
+		require.NoError(t, db.Raw(query).Scan(&count).Error, name)
+		require.Zero(t, count, name)
+	}
+}
+
+type lifecycleFixture struct {
+	org      *models.Organization
+	owner    *models.User
+	member   *models.User
+	task     *models.Task
+	trashed  *models.Task
+	subtask  *models.Task
+	ownTasks []*models.Task
+}
+
+// seedLifecycleFixture creates an organization with members, tasks in use and
+// in the trash, a subtask, a dependency, invites, invitations, policy overrides
+// and labels.
+func seedLifecycleFixture(t *testing.T, db *gorm.DB, name string) lifecycleFixture {
+	t.Helper()
+
+	now := time.Now()
+	owner := &models.User{Username: name + "-owner"}
+	member := &models.User{Username: name + "-member"}
+	require.NoError(t, db.Create(owner).Error)
+	require.NoError(t, db.Create(member).Error)
+
+	org := &models.Organization{Name: name, InviteCode: name + "_CODE"}
+	require.NoError(t, db.Create(org).Error)
+
+	orgRepo := NewOrganizationRepository(db)
+	taskRepo := NewTaskRepository(db)
+	require.NoError(t, orgRepo.AddMember(&models.OrganizationMember{
+		OrganizationID: org.ID, UserID: owner.ID, Role: models.RoleOwner, JoinedAt: now,
+	}, nil))
+
+	invite := &models.OrganizationInvite{
+		OrganizationID: org.ID, Name: "team", Code: "inv_" + name, Role: models.RoleMember, CreatedByID: owner.ID,
+	}
+	require.NoError(t, db.Create(invite).Error)
+	redeemed, err := NewInviteRepository(db).Redeem(invite, &models.OrganizationMember{
+		OrganizationID: org.ID, UserID: member.ID, Role: models.RoleMember, JoinedAt: now,
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, redeemed)
+
+	require.NoError(t, db.Create(&models.OrganizationInvitation{
+		OrganizationID: org.ID, InviteeID: member.ID, InviterID: owner.ID, Role: models.RoleMember,
+		Status: models.InvitationStatusDeclined, ExpiresAt: now.Add(time.Hour),
+	}).Error)
+	require.NoError(t, orgRepo.ReplacePolicyRules(org.ID, []models.OrganizationPolicyRule{
+		{Action: "task.update", Role: models.RoleMember, Scope: "any"},
+	}))
+
+	settings := models.DefaultOrganizationSettings(org.ID)
+	require.NoError(t, orgRepo.SaveSettings(&settings))
+	consumed, err := orgRepo.ConsumeAIGeneration(org.ID, now.Format(time.DateOnly), nil)
+	require.NoError(t, err)
+	require.True(t, consumed)
+	require.NoError(t, NewAuditLogRepository(db).Create(&models.AuditLogEntry{
+		OrganizationID: org.ID, ActorID: member.ID, Action: models.AuditActionMemberJoin,
+		TargetType: models.AuditTargetMember, TargetID: member.ID, Changes: "{}",
+	}))
+
+	newTask := func(title string, creatorID uint64, assignees ...uint64) *models.Task {
+		task := &models.Task{Title: title, CreatorID: creatorID, OrganizationID: org.ID}
+		require.NoError(t, taskRepo.Create(task, nil))
+		require.NoError(t, taskRepo.AssignUsers(task.ID, assignees))
+		return task
+	}
+
+	fixture := lifecycleFixture{
+		org:     org,
+		owner:   owner,
+		member:  member,
+		task:    newTask("shared", owner.ID, owner.ID, member.ID),
+		trashed: newTask("trashed", owner.ID, owner.ID, member.ID),
+	}
+	fixture.ownTasks = []*models.Task{newTask("member's own", member.ID, member.ID)}
+	fixture.subtask = &models.Task{Title: "trashed subtask", CreatorID: owner.ID, OrganizationID: org.ID, ParentID: &fixture.trashed.ID}
+	require.NoError(t, taskRepo.Create(fixture.subtask, nil))
+	require.NoError(t, taskRepo.AssignUsers(fixture.subtask.ID, []uint64{owner.ID, member.ID}))
+
+	label := &models.Label{OrganizationID: org.ID, Name: "bug", Color: "#d73a4a"}
+	require.NoError(t, orgRepo.CreateLabel(label, 1))
+	require.NoError(t, taskRepo.AddLabels(fixture.task.ID, []uint64{label.ID}))
+	require.NoError(t, taskRepo.AddLabels(fixture.trashed.ID, []uint64{label.ID}))
+	added, err := taskRepo.AddDependency(org.ID, fixture.task.ID, fixture.trashed.ID)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, taskRepo.Delete(fixture.trashed.ID))
+
+	return fixture
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+
+	var count int64
+	require.NoError(t, db.Unscoped().Model(model).Where(query, args...).Count(&count).Error)
+	return count
+}
+
+func TestLifecycle_DeleteOrganization(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	doomed := seedLifecycleFixture(t, db, "doomed")
+	kept := seedLifecycleFixture(t, db, "kept")
+	requireNoOrphans(t, db)
+
+	require.NoError(t, NewOrganizationRepository(db).Delete(doomed.org.ID))
+	requireNoOrphans(t, db)
+	require.Equal(t, int64(1), countRows(t, db, &models.AuditLogEntry{}, "organization_id = ?", doomed.org.ID))
+	require.Zero(t, countRows(t, db, &models.Label{}, "organization_id = ?", doomed.org.ID))
+
+	// Deleted tasks keep their assignments in the trash until the purge
+	require.Equal(t, int64(4), countRows(t, db, &models.Task{}, "organization_id = ?", doomed.org.ID))
+	require.Zero(t, countRows(t, db, &models.TaskAssignment{},
+		"deleted_at IS NULL AND task_id IN (?)", db.Unscoped().Model(&models.Task{}).Select("id").Where("organization_id = ?", doomed.org.ID)))
+
+	_, err := NewRetentionRepository(db).PurgeDeletedBefore(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	requireNoOrphans(t, db)
+
+	require.Zero(t, countRows(t, db, &models.Organization{}, "id = ?", doomed.org.ID))
+	require.Zero(t, countRows(t, db, &models.AuditLogEntry{}, "organization_id = ?", doomed.org.ID))
+	require.Zero(t, countRows(t, db, &models.Task{}, "organization_id = ?", doomed.org.ID))
+	require.Zero(t, countRows(t, db, &models.TaskAssignment{}, "task_id IN ?",
+		[]uint64{doomed.task.ID, doomed.trashed.ID, doomed.subtask.ID, doomed.ownTasks[0].ID}))
+
+	// The other organization only loses what was in its trash
+	require.Equal(t, int64(2), countRows(t, db, &models.Task{}, "organization_id = ?", kept.org.ID))
+	require.Equal(t, int64(2), countRows(t, db, &models.OrganizationMember{}, "organization_id = ?", kept.org.ID))
+	require.Equal(t, int64(1), countRows(t, db, &models.OrganizationPolicyRule{}, "organization_id = ?", kept.org.ID))
+	require.Equal(t, int64(1), countRows(t, db, &models.OrganizationSettings{}, "organization_id = ?", kept.org.ID))
+	require.Equal(t, int64(1), countRows(t, db, &models.AuditLogEntry{}, "organization_id = ?", kept.org.ID))
+	require.Equal(t, int64(1), countRows(t, db, &models.Label{}, "organization_id = ?", kept.org.ID))
+	require.Equal(t, int64(1), countRows(t, db, &models.TaskLabel{}, "task_id = ?", kept.task.ID))
+	// Dependencies on purged tasks go with them
+	require.Zero(t, countRows(t, db, &models.TaskDependency{}, "task_id = ?", kept.task.ID))
+}
+
+func TestLifecycle_RemoveMember(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	f := seedLifecycleFixture(t, db, "team")
+
+	require.NoError(t, NewOrganizationRepository(db).RemoveMember(f.org.ID, f.member.ID))
+	requireNoOrphans(t, db)
+
+	// Assignments are gone, including the one on the task in the trash
+	require.Zero(t, countRows(t, db, &models.TaskAssignment{}, "user_id = ?", f.member.ID))
+	// The audit log still remembers the member
+	require.Equal(t, int64(1), countRows(t, db, &models.AuditLogEntry{}, "actor_id = ?", f.member.ID))
+
+	taskRepo := NewTaskRepository(db)
+	trashed, err := taskRepo.FindDeletedByID(f.trashed.ID)
+	require.NoError(t, err)
+	require.Len(t, trashed.Assignments, 1)
+	require.NoError(t, taskRepo.Restore(trashed, nil))
+	requireNoOrphans(t, db)
+
+	restored, err := taskRepo.FindByID(f.trashed.ID, "Assignments")
+	require.NoError(t, err)
+	require.Len(t, restored.Assignments, 1)
+	require.Equal(t, f.owner.ID, restored.Assignments[0].UserID)
+
+	// The subtask deleted with the task comes back with it
+	subtask, err := taskRepo.FindByID(f.subtask.ID, "Assignments")
+	require.NoError(t, err)
+	require.Len(t, subtask.Assignments, 1)
+	require.Equal(t, f.owner.ID, subtask.Assignments[0].UserID)
+
+	// Tasks the member created stay with the organization
+	own, err := taskRepo.FindByID(f.ownTasks[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, f.member.ID, own.CreatorID)
+}
+
+func TestLifecycle_DeleteAndRestoreTask(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	f := seedLifecycleFixture(t, db, "team")
+	taskRepo := NewTaskRepository(db)
+
+	// An assignment removed before the task was deleted stays removed
+	require.NoError(t, taskRepo.UnassignUsers(f.task.ID, []uint64{f.member.ID}))
+	require.NoError(t, taskRepo.Delete(f.task.ID))
+	requireNoOrphans(t, db)
+
+	deleted, err := taskRepo.FindDeletedByID(f.task.ID)
+	require.NoError(t, err)
+	require.Len(t, deleted.Assignments, 1)
+
+	require.NoError(t, taskRepo.Restore(deleted, nil))
+	requireNoOrphans(t, db)
+
+	restored, err := taskRepo.FindByID(f.task.ID, "Assignments", "Labels.Label")
+	require.NoError(t, err)
+	require.Len(t, restored.Assignments, 1)
+	require.Equal(t, f.owner.ID, restored.Assignments[0].UserID)
+	// Labels stay on the task through the trash
+	require.Len(t, restored.Labels, 1)
+	require.Equal(t, "bug", restored.Labels[0].Label.Name)
+}
+
+func TestLifecycle_DeleteAccount(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	solo := seedLifecycleFixture(t, db, "solo")
+	shared := seedLifecycleFixture(t, db, "shared")
+
+	// The member of "solo" is removed first so its owner is the only one left
+	require.NoError(t, NewOrganizationRepository(db).RemoveMember(solo.org.ID, solo.member.ID))
+
+	require.NoError(t, NewUserRepository(db).DeleteAccount(AccountDeletion{
+		UserID:                shared.member.ID,
+		AnonymizedUsername:    "deleted-user",
+		DeleteOrganizationIDs: nil,
+		NewOwners:             map[uint64]uint64{},
+	}))
+	require.NoError(t, NewUserRepository(db).DeleteAccount(AccountDeletion{
+		UserID:                solo.owner.ID,
+		AnonymizedUsername:    "deleted-owner",
+		DeleteOrganizationIDs: []uint64{solo.org.ID},
+		NewOwners:             map[uint64]uint64{},
+	}))
+	requireNoOrphans(t, db)
+
+	require.Zero(t, countRows(t, db, &models.TaskAssignment{}, "user_id IN ?", []uint64{shared.member.ID, solo.owner.ID}))
+}
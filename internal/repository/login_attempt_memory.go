@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryLoginAttemptPruneInterval is how often expired entries are swept
+const memoryLoginAttemptPruneInterval = time.Minute
+
+type memoryLoginAttempt struct {
+	failures    int64
+	expiresAt   time.Time
+	lockedUntil time.Time
+}
+
+// MemoryLoginAttemptStore keeps login attempts in process memory.
+// Suitable for a single instance; counters are lost on restart.
+type MemoryLoginAttemptStore struct {
+	mu         sync.Mutex
+	attempts   map[string]*memoryLoginAttempt
+	lastPruned time.Time
+}
+
+// NewMemoryLoginAttemptStore creates an in-memory LoginAttemptStore
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		attempts:   make(map[string]*memoryLoginAttempt),
+		lastPruned: time.Now(),
+	}
+}
+
+// RecordFailure increments the failure count for key
+func (s *MemoryLoginAttemptStore) RecordFailure(key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+
+	attempt := s.attempts[key]
+	if attempt == nil {
+		attempt = &memoryLoginAttempt{}
+		s.attempts[key] = attempt
+	} else if !now.Before(attempt.expiresAt) {
+		attempt.failures = 0
+	}
+
+	attempt.failures++
+	attempt.expiresAt = now.Add(window)
+	return attempt.failures, nil
+}
+
+// Lock blocks key for the given duration
+func (s *MemoryLoginAttemptStore) Lock(key string, duration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt := s.attempts[key]
+	if attempt == nil {
+		attempt = &memoryLoginAttempt{}
+		s.attempts[key] = attempt
+	}
+	attempt.lockedUntil = time.Now().Add(duration)
+	return nil
+}
+
+// LockedFor returns how long key remains locked
+func (s *MemoryLoginAttemptStore) LockedFor(key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt := s.attempts[key]
+	if attempt == nil {
+		return 0, nil
+	}
+
+	remaining := time.Until(attempt.lockedUntil)
+	if remaining <= 0 {
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+// Reset clears the failure count and lock of key
+func (s *MemoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// prune drops entries whose window and lock have both passed so the map does
+// not grow without bound under a spray of usernames. Callers must hold mu.
+func (s *MemoryLoginAttemptStore) prune(now time.Time) {
+	if now.Sub(s.lastPruned) < memoryLoginAttemptPruneInterval {
+		return
+	}
+	s.lastPruned = now
+
+	for key, attempt := range s.attempts {
+		if !now.Before(attempt.expiresAt) && !now.Before(attempt.lockedUntil) {
+			delete(s.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// recordFailureScript increments the counter and (re)sets its expiry atomically
+var recordFailureScript = redis.NewScript(1, `
+local count = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return count
+`)
+
+// RedisLoginAttemptStore keeps login attempts in Redis so that limits are
+// shared between all API instances.
+type RedisLoginAttemptStore struct {
+	pool   *redis.Pool
+	prefix string
+}
+
+// NewRedisLoginAttemptStore creates a Redis-backed LoginAttemptStore.
+// Keys are namespaced with prefix.
+func NewRedisLoginAttemptStore(pool *redis.Pool, prefix string) LoginAttemptStore {
+	return &RedisLoginAttemptStore{pool: pool, prefix: prefix}
+}
+
+func (s *RedisLoginAttemptStore) failuresKey(key string) string {
+	return s.prefix + "failures:" + key
+}
+
+func (s *RedisLoginAttemptStore) lockKey(key string) string {
+	return s.prefix + "lock:" + key
+}
+
+// RecordFailure increments the failure count for key
+func (s *RedisLoginAttemptStore) RecordFailure(key string, window time.Duration) (int64, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	return redis.Int64(recordFailureScript.Do(conn, s.failuresKey(key), window.Milliseconds()))
+}
+
+// Lock blocks key for the given duration
+func (s *RedisLoginAttemptStore) Lock(key string, duration time.Duration) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SET", s.lockKey(key), 1, "PX", duration.Milliseconds())
+	return err
+}
+
+// LockedFor returns how long key remains locked
+func (s *RedisLoginAttemptStore) LockedFor(key string) (time.Duration, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	ttl, err := redis.Int64(conn.Do("PTTL", s.lockKey(key)))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	// PTTL returns -2 for a missing key and -1 for a key without expiry
+	if ttl <= 0 {
+		return 0, nil
+	}
+	return time.Duration(ttl) * time.Millisecond, nil
+}
+
+// Reset clears the failure count and lock of key
+func (s *RedisLoginAttemptStore) Reset(key string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", s.failuresKey(key), s.lockKey(key))
+	return err
+}
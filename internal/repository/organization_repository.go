@@ -1,8 +1,14 @@
 package repository
 
 import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/database"
 	"github.com/yukikurage/task-management-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormOrganizationRepository is a GORM implementation of OrganizationRepository
@@ -10,6 +16,18 @@ type GormOrganizationRepository struct {
 	db *gorm.DB
 }
 
+var (
+	// ErrMemberLimitReached is returned when adding a member would take an
+	// organization over its member limit.
+	ErrMemberLimitReached = errors.New("organization repository: member limit reached")
+	// ErrLabelLimitReached is returned when creating a label would take an
+	// organization over its label limit.
+	ErrLabelLimitReached = errors.New("organization repository: label limit reached")
+	// ErrLabelNameTaken is returned when another label of the organization
+	// already has the name, ignoring case.
+	ErrLabelNameTaken = errors.New("organization repository: label name taken")
+)
+
 // NewOrganizationRepository creates a new OrganizationRepository
 func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
 	return &GormOrganizationRepository{db: db}
@@ -43,37 +61,34 @@ func (r *GormOrganizationRepository) Update(org *models.Organization) error {
 	return r.db.Save(org).Error
 }
 
+// SetArchivedAt archives an organization, or restores it when archivedAt is nil
+func (r *GormOrganizationRepository) SetArchivedAt(id uint64, archivedAt *time.Time) error {
+	return r.db.Model(&models.Organization{}).Where("id = ?", id).Update("archived_at", archivedAt).Error
+}
+
 // Delete deletes an organization and all related data in a transaction
 func (r *GormOrganizationRepository) Delete(id uint64) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Delete all tasks in the organization
-		if err := tx.Where("organization_id = ?", id).Delete(&models.Task{}).Error; err != nil {
-			return err
-		}
-
-		// Delete all members
-		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
-			return err
-		}
-
-		// Delete organization
-		if err := tx.Delete(&models.Organization{}, id).Error; err != nil {
-			return err
-		}
-
-		return nil
+		return deleteOrganization(tx, id, time.Now())
 	})
 }
 
 // AddMember adds a member to an organization
-func (r *GormOrganizationRepository) AddMember(member *models.OrganizationMember) error {
-	return r.db.Create(member).Error
+func (r *GormOrganizationRepository) AddMember(member *models.OrganizationMember, maxMembers *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockMemberCapacity(tx, member.OrganizationID, maxMembers); err != nil {
+			return err
+		}
+		return tx.Create(member).Error
+	})
 }
 
-// RemoveMember removes a member from an organization
+// RemoveMember removes a member from an organization along with their task
+// assignments in it
 func (r *GormOrganizationRepository) RemoveMember(organizationID, userID uint64) error {
-	return r.db.Where("organization_id = ? AND user_id = ?", organizationID, userID).
-		Delete(&models.OrganizationMember{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return removeMembership(tx, organizationID, userID)
+	})
 }
 
 // FindMember finds a specific organization member
@@ -86,6 +101,110 @@ func (r *GormOrganizationRepository) FindMember(organizationID, userID uint64) (
 	return &member, nil
 }
 
+// UpdateMemberRole changes the role of an organization member
+func (r *GormOrganizationRepository) UpdateMemberRole(organizationID, userID uint64, role models.OrganizationRole) error {
+	return r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Update("role", role).Error
+}
+
+// TransferOwnership makes one member an owner and demotes the current owner
+// to admin within a single transaction and reports whether ownership was
+// transferred. It is not if, once the organization is locked, the current
+// owner is no longer an owner or the new owner is no longer a member.
+func (r *GormOrganizationRepository) TransferOwnership(organizationID, fromUserID, toUserID uint64) (bool, error) {
+	transferred := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		var members []models.OrganizationMember
+		if err := tx.Where("organization_id = ? AND user_id IN ?", organizationID, []uint64{fromUserID, toUserID}).
+			Find(&members).Error; err != nil {
+			return err
+		}
+		if len(members) != 2 {
+			return nil
+		}
+		for _, member := range members {
+			if member.UserID == fromUserID && member.Role != models.RoleOwner {
+				return nil
+			}
+		}
+
+		if err := tx.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, toUserID).
+			Update("role", models.RoleOwner).Error; err != nil {
+			return err
+		}
+
+		transferred = true
+		return tx.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, fromUserID).
+			Update("role", models.RoleAdmin).Error
+	})
+	return transferred, err
+}
+
+// DemoteOwner changes the role of an owner unless they are the last owner of
+// the organization and reports whether the role was changed
+func (r *GormOrganizationRepository) DemoteOwner(organizationID, userID uint64, role models.OrganizationRole) (bool, error) {
+	demoted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		hasOther, err := lockOtherOwners(tx, organizationID, userID)
+		if err != nil || !hasOther {
+			return err
+		}
+
+		demoted = true
+		return tx.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Update("role", role).Error
+	})
+	return demoted, err
+}
+
+// RemoveOwner removes an owner along with their task assignments unless they
+// are the last owner of the organization and reports whether they were removed
+func (r *GormOrganizationRepository) RemoveOwner(organizationID, userID uint64) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		hasOther, err := lockOtherOwners(tx, organizationID, userID)
+		if err != nil || !hasOther {
+			return err
+		}
+
+		removed = true
+		return removeMembership(tx, organizationID, userID)
+	})
+	return removed, err
+}
+
+// lockOrganization locks the organization row until the end of the
+// transaction to serialize changes that check and write related rows
+func lockOrganization(tx *gorm.DB, organizationID uint64) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&models.Organization{}, organizationID).Error
+}
+
+// lockOtherOwners locks the organization row so that owner changes are
+// serialized and reports whether an owner other than the user remains
+func lockOtherOwners(tx *gorm.DB, organizationID, userID uint64) (bool, error) {
+	if err := lockOrganization(tx, organizationID); err != nil {
+		return false, err
+	}
+
+	var others int64
+	if err := tx.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ? AND user_id <> ?", organizationID, models.RoleOwner, userID).
+		Count(&others).Error; err != nil {
+		return false, err
+	}
+	return others > 0, nil
+}
+
 // ListMembersByUserID lists all organizations a user is a member of
 func (r *GormOrganizationRepository) ListMembersByUserID(userID uint64) ([]models.OrganizationMember, error) {
 	var memberships []models.OrganizationMember
@@ -107,3 +226,359 @@ func (r *GormOrganizationRepository) ListMembers(organizationID uint64) ([]model
 	}
 	return members, nil
 }
+
+// likeEscaper escapes LIKE wildcards using "!", which needs no quoting in any
+// supported dialect
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// SearchMembers lists a page of organization members matching the filter
+func (r *GormOrganizationRepository) SearchMembers(filter MemberFilter) ([]models.OrganizationMember, int64, error) {
+	query := r.db.Model(&models.OrganizationMember{}).
+		Joins("JOIN users ON users.id = organization_members.user_id").
+		Where("organization_members.organization_id = ?", filter.OrganizationID)
+
+	if username := strings.TrimSpace(filter.Username); username != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(username)) + "%"
+		query = query.Where("LOWER(users.username) LIKE ? ESCAPE '!'", pattern)
+	}
+	if filter.Role != nil {
+		query = query.Where("organization_members.role = ?", *filter.Role)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if filter.NewestFirst {
+		direction = "DESC"
+	}
+
+	var members []models.OrganizationMember
+	if err := query.Order("organization_members.joined_at " + direction).
+		Order("organization_members.user_id " + direction).
+		Scopes(database.Paginate(filter.Pagination)).
+		Preload("User").
+		Find(&members).Error; err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
+
+// CountOpenAssignedTasks counts the unfinished tasks each user is assigned to
+// in an organization. Users without any are left out of the map.
+func (r *GormOrganizationRepository) CountOpenAssignedTasks(organizationID uint64, userIDs []uint64, doneStatuses []models.TaskStatus) (map[uint64]int64, error) {
+	counts := make(map[uint64]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := r.db.Model(&models.TaskAssignment{}).
+		Select("task_assignments.user_id, COUNT(*) AS count").
+		Joins("JOIN tasks ON tasks.id = task_assignments.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.organization_id = ?", organizationID).
+		Where("task_assignments.user_id IN ?", userIDs)
+	if len(doneStatuses) > 0 {
+		query = query.Where("tasks.status NOT IN ?", doneStatuses)
+	}
+
+	var rows []struct {
+		UserID uint64
+		Count  int64
+	}
+	if err := query.Group("task_assignments.user_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+	return counts, nil
+}
+
+// countTasksWithStatusNotIn counts the tasks of an organization, including
+// the ones in the trash, whose status is not one of the given statuses
+func countTasksWithStatusNotIn(db *gorm.DB, organizationID uint64, statuses []models.TaskStatus) (int64, error) {
+	query := db.Unscoped().Model(&models.Task{}).Where("organization_id = ?", organizationID)
+	if len(statuses) > 0 {
+		query = query.Where("status NOT IN ?", statuses)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// lockMemberCapacity locks the organization row so that joins are serialized
+// and fails with ErrMemberLimitReached if it cannot take another member
+func lockMemberCapacity(tx *gorm.DB, organizationID uint64, maxMembers *int) error {
+	if maxMembers == nil {
+		return nil
+	}
+	if err := lockOrganization(tx, organizationID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.OrganizationMember{}).
+		Where("organization_id = ?", organizationID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= int64(*maxMembers) {
+		return ErrMemberLimitReached
+	}
+	return nil
+}
+
+// FindSettings finds the stored settings of an organization
+func (r *GormOrganizationRepository) FindSettings(organizationID uint64) (*models.OrganizationSettings, error) {
+	var settings models.OrganizationSettings
+	if err := r.db.Where("organization_id = ?", organizationID).First(&settings).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSettings creates or replaces the settings of an organization
+func (r *GormOrganizationRepository) SaveSettings(settings *models.OrganizationSettings) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}},
+		UpdateAll: true,
+	}).Create(settings).Error
+}
+
+// ConsumeAIGeneration increments the organization's AI usage for the day if
+// it is below the limit
+func (r *GormOrganizationRepository) ConsumeAIGeneration(organizationID uint64, day string, limit *int) (bool, error) {
+	consumed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.OrganizationAIUsage{OrganizationID: organizationID, Day: day}).Error; err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.OrganizationAIUsage{}).
+			Where("organization_id = ? AND day = ?", organizationID, day)
+		if limit != nil {
+			query = query.Where("count < ?", *limit)
+		}
+		result := query.Update("count", gorm.Expr("count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+
+		consumed = result.RowsAffected > 0
+		return nil
+	})
+	return consumed, err
+}
+
+// ReleaseAIGeneration decrements the organization's AI usage for the day
+func (r *GormOrganizationRepository) ReleaseAIGeneration(organizationID uint64, day string) error {
+	return r.db.Model(&models.OrganizationAIUsage{}).
+		Where("organization_id = ? AND day = ? AND count > 0", organizationID, day).
+		Update("count", gorm.Expr("count - 1")).Error
+}
+
+// ListPolicyRules lists the policy overrides of an organization
+func (r *GormOrganizationRepository) ListPolicyRules(organizationID uint64) ([]models.OrganizationPolicyRule, error) {
+	var rules []models.OrganizationPolicyRule
+	if err := r.db.Where("organization_id = ?", organizationID).
+		Order("action, role").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ReplacePolicyRules replaces all policy overrides of an organization
+func (r *GormOrganizationRepository) ReplacePolicyRules(organizationID uint64, rules []models.OrganizationPolicyRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", organizationID).Delete(&models.OrganizationPolicyRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		for i := range rules {
+			rules[i].OrganizationID = organizationID
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// FindWorkflow finds the stored workflow of an organization, returning
+// gorm.ErrRecordNotFound if it never defined one
+func (r *GormOrganizationRepository) FindWorkflow(organizationID uint64) (*models.Workflow, error) {
+	var workflow models.Workflow
+	if err := r.db.Where("organization_id = ?", organizationID).
+		Order("position, id").
+		Find(&workflow.States).Error; err != nil {
+		return nil, err
+	}
+	if len(workflow.States) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := r.db.Where("organization_id = ?", organizationID).
+		Order("id").
+		Find(&workflow.Transitions).Error; err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// ReplaceWorkflow replaces the states and transitions of an organization's
+// workflow unless tasks, including the ones in the trash, are in states it
+// drops, and returns the number of such tasks
+func (r *GormOrganizationRepository) ReplaceWorkflow(organizationID uint64, workflow models.Workflow) (int64, error) {
+	var stranded int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		keys := make([]models.TaskStatus, len(workflow.States))
+		for i, state := range workflow.States {
+			keys[i] = state.Key
+		}
+		var err error
+		stranded, err = countTasksWithStatusNotIn(tx, organizationID, keys)
+		if err != nil || stranded > 0 {
+			return err
+		}
+
+		if err := tx.Where("organization_id = ?", organizationID).Delete(&models.WorkflowTransition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", organizationID).Delete(&models.WorkflowState{}).Error; err != nil {
+			return err
+		}
+
+		states := make([]models.WorkflowState, len(workflow.States))
+		for i, state := range workflow.States {
+			states[i] = models.WorkflowState{
+				OrganizationID: organizationID,
+				Key:            state.Key,
+				Name:           state.Name,
+				Position:       i,
+				Done:           state.Done,
+			}
+		}
+		if len(states) > 0 {
+			if err := tx.Create(&states).Error; err != nil {
+				return err
+			}
+		}
+
+		transitions := make([]models.WorkflowTransition, len(workflow.Transitions))
+		for i, transition := range workflow.Transitions {
+			transitions[i] = models.WorkflowTransition{
+				OrganizationID: organizationID,
+				FromStatus:     transition.FromStatus,
+				ToStatus:       transition.ToStatus,
+			}
+		}
+		if len(transitions) > 0 {
+			return tx.Create(&transitions).Error
+		}
+		return nil
+	})
+	return stranded, err
+}
+
+// ListLabels lists the labels of an organization by name
+func (r *GormOrganizationRepository) ListLabels(organizationID uint64) ([]models.Label, error) {
+	var labels []models.Label
+	if err := r.db.Where("organization_id = ?", organizationID).
+		Order("LOWER(name), id").
+		Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// FindLabel finds a label of the given organization
+func (r *GormOrganizationRepository) FindLabel(organizationID, labelID uint64) (*models.Label, error) {
+	var label models.Label
+	if err := r.db.Where("id = ? AND organization_id = ?", labelID, organizationID).
+		First(&label).Error; err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// CreateLabel creates a label unless the organization already has maxLabels
+// labels or a label with the same name
+func (r *GormOrganizationRepository) CreateLabel(label *models.Label, maxLabels int) error {
+	return translateLabelError(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockLabelName(tx, label); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.Label{}).
+			Where("organization_id = ?", label.OrganizationID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxLabels) {
+			return ErrLabelLimitReached
+		}
+
+		return tx.Create(label).Error
+	}))
+}
+
+// UpdateLabel saves a label unless another label of the organization has the
+// same name
+func (r *GormOrganizationRepository) UpdateLabel(label *models.Label) error {
+	return translateLabelError(r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockLabelName(tx, label); err != nil {
+			return err
+		}
+		return tx.Save(label).Error
+	}))
+}
+
+// lockLabelName locks the organization row so that label changes are
+// serialized and fails with ErrLabelNameTaken if another of its labels has
+// the label's name, ignoring case
+func lockLabelName(tx *gorm.DB, label *models.Label) error {
+	if err := lockOrganization(tx, label.OrganizationID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.Label{}).
+		Where("organization_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", label.OrganizationID, label.Name, label.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrLabelNameTaken
+	}
+	return nil
+}
+
+// translateLabelError reports a violation of the unique index on the label
+// name as ErrLabelNameTaken
+func translateLabelError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrLabelNameTaken
+	}
+	return err
+}
+
+// DeleteLabel deletes a label and detaches it from every task, including
+// tasks in the trash
+func (r *GormOrganizationRepository) DeleteLabel(labelID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("label_id = ?", labelID).Delete(&models.TaskLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Label{}, labelID).Error
+	})
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+func TestOrganizationRepository_ConsumeAIGeneration(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, repo.Create(org))
+
+	limit := 2
+	for i := 0; i < limit; i++ {
+		consumed, err := repo.ConsumeAIGeneration(org.ID, "2025-03-01", &limit)
+		require.NoError(t, err)
+		require.True(t, consumed)
+	}
+
+	consumed, err := repo.ConsumeAIGeneration(org.ID, "2025-03-01", &limit)
+	require.NoError(t, err)
+	require.False(t, consumed, "the daily limit is reached")
+
+	// Each day starts over and no limit never blocks
+	consumed, err = repo.ConsumeAIGeneration(org.ID, "2025-03-02", &limit)
+	require.NoError(t, err)
+	require.True(t, consumed)
+	consumed, err = repo.ConsumeAIGeneration(org.ID, "2025-03-01", nil)
+	require.NoError(t, err)
+	require.True(t, consumed)
+
+	var usage models.OrganizationAIUsage
+	require.NoError(t, db.Where("organization_id = ? AND day = ?", org.ID, "2025-03-01").First(&usage).Error)
+	require.Equal(t, 3, usage.Count)
+}
+
+func TestOrganizationRepository_SaveSettings(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, repo.Create(org))
+
+	maxTasks := 10
+	settings := models.DefaultOrganizationSettings(org.ID)
+	settings.MaxTasks = &maxTasks
+	require.NoError(t, repo.SaveSettings(&settings))
+
+	// Saving again replaces every field, including turning flags off
+	settings = models.DefaultOrganizationSettings(org.ID)
+	settings.AIGenerationEnabled = false
+	settings.Timezone = "Asia/Tokyo"
+	require.NoError(t, repo.SaveSettings(&settings))
+
+	found, err := repo.FindSettings(org.ID)
+	require.NoError(t, err)
+	require.False(t, found.AIGenerationEnabled)
+	require.Equal(t, "Asia/Tokyo", found.Timezone)
+	require.Nil(t, found.MaxTasks)
+}
+
+func TestOrganizationRepository_KeepsLastOwner(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, repo.Create(org))
+	for _, userID := range []uint64{1, 2} {
+		require.NoError(t, repo.AddMember(&models.OrganizationMember{OrganizationID: org.ID, UserID: userID, Role: models.RoleOwner}, nil))
+	}
+
+	demoted, err := repo.DemoteOwner(org.ID, 1, models.RoleAdmin)
+	require.NoError(t, err)
+	require.True(t, demoted)
+
+	// The remaining owner can neither be demoted nor removed
+	demoted, err = repo.DemoteOwner(org.ID, 2, models.RoleAdmin)
+	require.NoError(t, err)
+	require.False(t, demoted)
+	removed, err := repo.RemoveOwner(org.ID, 2)
+	require.NoError(t, err)
+	require.False(t, removed)
+
+	member, err := repo.FindMember(org.ID, 2)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleOwner, member.Role)
+}
+
+func TestOrganizationRepository_AddMemberWithinLimitConcurrently(t *testing.T) {
+	db := setupConcurrentRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, repo.Create(org))
+
+	const attempts = 20
+	limit := attempts - 1
+	errs := make([]error, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = repo.AddMember(&models.OrganizationMember{
+				OrganizationID: org.ID, UserID: uint64(i + 1), Role: models.RoleMember,
+			}, &limit)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	rejected := 0
+	for _, err := range errs {
+		if errors.Is(err, ErrMemberLimitReached) {
+			rejected++
+			continue
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, rejected)
+	require.Equal(t, int64(limit), countRows(t, db, &models.OrganizationMember{}, "organization_id = ?", org.ID))
+}
+
+func TestOrganizationRepository_TransferOwnershipRechecksOwner(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, repo.Create(org))
+	for userID, role := range map[uint64]models.OrganizationRole{1: models.RoleOwner, 2: models.RoleOwner, 3: models.RoleMember} {
+		require.NoError(t, repo.AddMember(&models.OrganizationMember{OrganizationID: org.ID, UserID: userID, Role: role}, nil))
+	}
+
+	// Demoted after the caller checked their role
+	demoted, err := repo.DemoteOwner(org.ID, 1, models.RoleAdmin)
+	require.NoError(t, err)
+	require.True(t, demoted)
+
+	transferred, err := repo.TransferOwnership(org.ID, 1, 3)
+	require.NoError(t, err)
+	require.False(t, transferred)
+	member, err := repo.FindMember(org.ID, 3)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleMember, member.Role)
+
+	// Nor does a user who is not a member become an owner
+	transferred, err = repo.TransferOwnership(org.ID, 2, 4)
+	require.NoError(t, err)
+	require.False(t, transferred)
+
+	transferred, err = repo.TransferOwnership(org.ID, 2, 3)
+	require.NoError(t, err)
+	require.True(t, transferred)
+	for userID, role := range map[uint64]models.OrganizationRole{2: models.RoleAdmin, 3: models.RoleOwner} {
+		member, err := repo.FindMember(org.ID, userID)
+		require.NoError(t, err)
+		require.Equal(t, role, member.Role)
+	}
+}
+
+func TestOrganizationRepository_CreateLabelConcurrently(t *testing.T) {
+	db := setupConcurrentRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, repo.Create(org))
+
+	// Names differing only in case collide, which the unique index alone
+	// does not catch
+	var names []string
+	for i := 0; i < 20; i++ {
+		name := []byte("label")
+		for j := range name {
+			if i&(1<<j) != 0 {
+				name[j] -= 'a' - 'A'
+			}
+		}
+		names = append(names, string(name))
+	}
+	errs := make([]error, len(names))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			<-start
+			errs[i] = repo.CreateLabel(&models.Label{OrganizationID: org.ID, Name: name, Color: "#d73a4a"}, 10)
+		}(i, name)
+	}
+	close(start)
+	wg.Wait()
+
+	taken := 0
+	for _, err := range errs {
+		if errors.Is(err, ErrLabelNameTaken) {
+			taken++
+			continue
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, len(names)-1, taken)
+
+	// An exact duplicate written past the check is reported the same way
+	require.NoError(t, db.Create(&models.Label{OrganizationID: org.ID, Name: "docs", Color: "#d73a4a"}).Error)
+	require.ErrorIs(t, translateLabelError(db.Create(&models.Label{OrganizationID: org.ID, Name: "docs", Color: "#d73a4a"}).Error), ErrLabelNameTaken)
+
+	require.ErrorIs(t, repo.CreateLabel(&models.Label{OrganizationID: org.ID, Name: "ui", Color: "#d73a4a"}, 2), ErrLabelLimitReached)
+}
+
+func TestOrganizationRepository_ReplaceWorkflowKeepsStatesInUse(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewOrganizationRepository(db)
+	fixture := seedLifecycleFixture(t, db, "org")
+
+	review := models.Workflow{States: []models.WorkflowState{
+		{Key: models.TaskStatusTodo, Name: "To do"},
+		{Key: "REVIEW", Name: "Review"},
+		{Key: models.TaskStatusDone, Name: "Done", Done: true},
+	}}
+	stranded, err := repo.ReplaceWorkflow(fixture.org.ID, review)
+	require.NoError(t, err)
+	require.Zero(t, stranded)
+
+	// A task in the trash still holds on to its state
+	require.NoError(t, db.Unscoped().Model(&models.Task{}).Where("id = ?", fixture.trashed.ID).Update("status", "REVIEW").Error)
+	stranded, err = repo.ReplaceWorkflow(fixture.org.ID, models.DefaultWorkflow())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stranded)
+
+	workflow, err := repo.FindWorkflow(fixture.org.ID)
+	require.NoError(t, err)
+	require.Len(t, workflow.States, 3)
+}
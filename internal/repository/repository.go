@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/utils"
 )
 
 // TaskRepository defines the interface for task data access
 type TaskRepository interface {
-	// Create creates a new task
-	Create(task *models.Task) error
+	// Create creates a new task, failing with ErrTaskLimitReached if the
+	// organization already has maxTasks active tasks
+	Create(task *models.Task, maxTasks *int) error
 
 	// FindByID finds a task by ID with optional preloading
 	FindByID(id uint64, preload ...string) (*models.Task, error)
@@ -20,9 +22,28 @@ type TaskRepository interface {
 	// Update updates a task
 	Update(task *models.Task) error
 
-	// Delete soft deletes a task
+	// Delete soft deletes a task and its subtasks along with their assignments
 	Delete(id uint64) error
 
+	// ListDeleted lists a page of the soft-deleted tasks of an organization
+	// and returns the total number of them
+	ListDeleted(organizationID uint64, params utils.PaginationParams) ([]models.Task, int64, error)
+
+	// FindDeletedByID finds a soft-deleted task along with the assignments removed with it
+	FindDeletedByID(id uint64) (*models.Task, error)
+
+	// Restore brings a soft-deleted task back with the subtasks and
+	// assignments deleted with it, failing with ErrTaskLimitReached if they
+	// would take the organization over maxTasks active tasks
+	Restore(task *models.Task, maxTasks *int) error
+
+	// ListSubtaskIDs lists the active direct subtasks of the given tasks
+	ListSubtaskIDs(parentIDs []uint64) ([]uint64, error)
+
+	// CountSubtasksByStatus counts the active direct subtasks of each of the
+	// given tasks by status
+	CountSubtasksByStatus(parentIDs []uint64) (map[uint64]map[models.TaskStatus]int64, error)
+
 	// AssignUsers assigns multiple users to a task
 	AssignUsers(taskID uint64, userIDs []uint64) error
 
@@ -32,21 +53,96 @@ type TaskRepository interface {
 	// FindAssignment finds a specific task assignment
 	FindAssignment(taskID, userID uint64) (*models.TaskAssignment, error)
 
+	// AddLabels attaches labels to a task, skipping the ones already attached
+	AddLabels(taskID uint64, labelIDs []uint64) error
+
+	// RemoveLabels detaches labels from a task
+	RemoveLabels(taskID uint64, labelIDs []uint64) error
+
+	// CountLabelsByIDs counts how many of the given label IDs belong to the organization
+	CountLabelsByIDs(labelIDs []uint64, organizationID uint64) (int64, error)
+
+	// AddDependency records that a task of the organization is blocked by
+	// another task, doing nothing if it already is. It reports false without
+	// adding anything when the blocker already waits on the task through any
+	// chain of dependencies, including tasks in the trash.
+	AddDependency(organizationID, taskID, blockerID uint64) (bool, error)
+
+	// RemoveDependency removes a dependency, reporting whether there was one
+	RemoveDependency(taskID, blockerID uint64) (bool, error)
+
+	// ListBlockers lists the active tasks blocking a task
+	ListBlockers(taskID uint64) ([]models.Task, error)
+
+	// ListDependents lists the active tasks blocked by a task
+	ListDependents(taskID uint64) ([]models.Task, error)
+
+	// ListBlockerStatuses lists the statuses of the active blockers of each
+	// of the given tasks
+	ListBlockerStatuses(taskIDs []uint64) (map[uint64][]models.TaskStatus, error)
+
+	// ListDependenciesAmong lists the dependencies between the given tasks
+	ListDependenciesAmong(taskIDs []uint64) ([]models.TaskDependency, error)
+
 	// CountUsersByIDs counts how many of the given user IDs exist
 	CountUsersByIDs(userIDs []uint64, organizationID uint64) (int64, error)
+
+	// ListByCreatorID lists all tasks created by a user across organizations
+	ListByCreatorID(userID uint64) ([]models.Task, error)
+
+	// ListAssignedToUser lists all tasks currently assigned to a user across organizations
+	ListAssignedToUser(userID uint64) ([]models.Task, error)
 }
 
 // TaskFilter holds filtering options for listing tasks
 type TaskFilter struct {
 	OrganizationIDs []uint64
 	Status          *models.TaskStatus
+	// ExcludeStatuses leaves out tasks in any of the statuses
+	ExcludeStatuses []models.TaskStatus
 	CreatorID       *uint64
 	AssignedUserID  *uint64
 	DueDateFrom     *time.Time
 	DueDateTo       *time.Time
-	SortByDueDate   bool
-	Page            int
-	PageSize        int
+	// ParentID keeps the direct subtasks of a task
+	ParentID *uint64
+	// TopLevelOnly keeps tasks that are not subtasks
+	TopLevelOnly bool
+	// LabelIDs keeps tasks with any of the labels, or all of them with MatchAllLabels
+	LabelIDs       []uint64
+	MatchAllLabels bool
+	// Sort orders the tasks by each key in turn, newest first when empty
+	Sort     []TaskSort
+	Page     int
+	PageSize int
+}
+
+// TaskSortField is a field tasks can be sorted by
+type TaskSortField string
+
+const (
+	TaskSortPriority  TaskSortField = "priority"
+	TaskSortDueDate   TaskSortField = "due_date"
+	TaskSortTitle     TaskSortField = "title"
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortUpdatedAt TaskSortField = "updated_at"
+)
+
+// TaskSort is one key of a task listing's sort order. Priority sorts from
+// urgent to low and tasks without a due date come last in either direction.
+type TaskSort struct {
+	Field TaskSortField
+	Desc  bool
+}
+
+// MemberFilter holds filtering options for listing organization members
+type MemberFilter struct {
+	OrganizationID uint64
+	// Username matches members whose username contains it, ignoring case
+	Username    string
+	Role        *models.OrganizationRole
+	NewestFirst bool
+	Pagination  utils.PaginationParams
 }
 
 // OrganizationRepository defines the interface for organization data access
@@ -63,23 +159,174 @@ type OrganizationRepository interface {
 	// Update updates an organization
 	Update(org *models.Organization) error
 
+	// SetArchivedAt archives an organization, or restores it when archivedAt is nil
+	SetArchivedAt(id uint64, archivedAt *time.Time) error
+
 	// Delete deletes an organization and all related data
 	Delete(id uint64) error
 
-	// AddMember adds a member to an organization
-	AddMember(member *models.OrganizationMember) error
+	// AddMember adds a member to an organization, failing with
+	// ErrMemberLimitReached if it already has maxMembers members
+	AddMember(member *models.OrganizationMember, maxMembers *int) error
 
-	// RemoveMember removes a member from an organization
+	// RemoveMember removes a member from an organization along with their task assignments in it
 	RemoveMember(organizationID, userID uint64) error
 
 	// FindMember finds a specific organization member
 	FindMember(organizationID, userID uint64) (*models.OrganizationMember, error)
 
+	// UpdateMemberRole changes the role of an organization member
+	UpdateMemberRole(organizationID, userID uint64, role models.OrganizationRole) error
+
+	// DemoteOwner changes the role of an owner unless they are the last owner
+	// of the organization and reports whether the role was changed
+	DemoteOwner(organizationID, userID uint64, role models.OrganizationRole) (bool, error)
+
+	// RemoveOwner removes an owner along with their task assignments unless
+	// they are the last owner of the organization and reports whether they were removed
+	RemoveOwner(organizationID, userID uint64) (bool, error)
+
+	// TransferOwnership makes one member an owner and demotes the current owner
+	// to admin unless the current owner is no longer an owner or the new owner
+	// no longer a member, and reports whether ownership was transferred
+	TransferOwnership(organizationID, fromUserID, toUserID uint64) (bool, error)
+
 	// ListMembersByUserID lists all organizations a user is a member of
 	ListMembersByUserID(userID uint64) ([]models.OrganizationMember, error)
 
 	// ListMembers lists all members of an organization
 	ListMembers(organizationID uint64) ([]models.OrganizationMember, error)
+
+	// SearchMembers lists a page of organization members matching the filter
+	// and returns the total number of matches
+	SearchMembers(filter MemberFilter) ([]models.OrganizationMember, int64, error)
+
+	// CountOpenAssignedTasks counts the tasks of an organization each of the
+	// given users is assigned to whose status is not one of the done statuses
+	CountOpenAssignedTasks(organizationID uint64, userIDs []uint64, doneStatuses []models.TaskStatus) (map[uint64]int64, error)
+
+	// FindSettings finds the stored settings of an organization
+	FindSettings(organizationID uint64) (*models.OrganizationSettings, error)
+
+	// SaveSettings creates or replaces the settings of an organization
+	SaveSettings(settings *models.OrganizationSettings) error
+
+	// ConsumeAIGeneration records an AI generation for the organization on the
+	// given day unless the limit has been reached. It reports whether the
+	// generation was recorded; a nil limit never blocks.
+	ConsumeAIGeneration(organizationID uint64, day string, limit *int) (bool, error)
+
+	// ReleaseAIGeneration gives back a generation recorded on the given day
+	// whose result could not be used
+	ReleaseAIGeneration(organizationID uint64, day string) error
+
+	// ListPolicyRules lists the policy overrides of an organization
+	ListPolicyRules(organizationID uint64) ([]models.OrganizationPolicyRule, error)
+
+	// ReplacePolicyRules replaces all policy overrides of an organization
+	ReplacePolicyRules(organizationID uint64, rules []models.OrganizationPolicyRule) error
+
+	// FindWorkflow finds the stored workflow of an organization, returning
+	// gorm.ErrRecordNotFound if it never defined one
+	FindWorkflow(organizationID uint64) (*models.Workflow, error)
+
+	// ReplaceWorkflow replaces the states and transitions of an organization's
+	// workflow unless tasks, including the ones in the trash, are in states it
+	// drops, and returns the number of such tasks
+	ReplaceWorkflow(organizationID uint64, workflow models.Workflow) (int64, error)
+
+	// ListLabels lists the labels of an organization by name
+	ListLabels(organizationID uint64) ([]models.Label, error)
+
+	// FindLabel finds a label of the given organization
+	FindLabel(organizationID, labelID uint64) (*models.Label, error)
+
+	// CreateLabel creates a label, failing with ErrLabelLimitReached if the
+	// organization already has maxLabels labels and with ErrLabelNameTaken if
+	// another of its labels has the same name, ignoring case
+	CreateLabel(label *models.Label, maxLabels int) error
+
+	// UpdateLabel saves a label, failing with ErrLabelNameTaken if another
+	// label of the organization has the same name, ignoring case
+	UpdateLabel(label *models.Label) error
+
+	// DeleteLabel deletes a label and detaches it from every task
+	DeleteLabel(labelID uint64) error
+}
+
+// InviteRepository defines the interface for organization invite link data access
+type InviteRepository interface {
+	// Create creates a new invite
+	Create(invite *models.OrganizationInvite) error
+
+	// FindByCode finds an invite by its code
+	FindByCode(code string) (*models.OrganizationInvite, error)
+
+	// FindByIDForOrganization finds an invite of the given organization with
+	// the users who joined through it
+	FindByIDForOrganization(id, organizationID uint64) (*models.OrganizationInvite, error)
+
+	// ListByOrganizationID lists all invites of an organization, newest first
+	ListByOrganizationID(organizationID uint64) ([]models.OrganizationInvite, error)
+
+	// Revoke marks an invite as revoked
+	Revoke(id uint64, revokedAt time.Time) error
+
+	// Redeem counts a use of the invite and adds the member within a single
+	// transaction, returning false if the invite was revoked, expired or used up meanwhile
+	// and failing with ErrMemberLimitReached if the organization already has maxMembers members
+	Redeem(invite *models.OrganizationInvite, member *models.OrganizationMember, maxMembers *int) (bool, error)
+}
+
+// InvitationRepository defines the interface for targeted organization invitation data access
+type InvitationRepository interface {
+	// Create creates a new invitation
+	Create(invitation *models.OrganizationInvitation) error
+
+	// FindByID finds an invitation with its organization and inviter
+	FindByID(id uint64) (*models.OrganizationInvitation, error)
+
+	// FindPending finds an unexpired pending invitation of a user to an organization
+	FindPending(organizationID, inviteeID uint64, now time.Time) (*models.OrganizationInvitation, error)
+
+	// ListByOrganizationID lists the invitations sent by an organization, newest first
+	ListByOrganizationID(organizationID uint64) ([]models.OrganizationInvitation, error)
+
+	// ListByInviteeID lists the invitations a user received, newest first
+	ListByInviteeID(inviteeID uint64) ([]models.OrganizationInvitation, error)
+
+	// Respond moves a pending invitation to the given status, returning false
+	// if it was no longer pending
+	Respond(id uint64, status models.InvitationStatus, respondedAt time.Time) (bool, error)
+
+	// Accept marks a pending invitation as accepted and adds the member within
+	// a single transaction, returning false if it was no longer pending and
+	// failing with ErrMemberLimitReached if the organization already has maxMembers members
+	Accept(id uint64, member *models.OrganizationMember, maxMembers *int) (bool, error)
+}
+
+// AuditLogRepository defines the interface for the append-only organization audit log
+type AuditLogRepository interface {
+	// Create appends an entry
+	Create(entry *models.AuditLogEntry) error
+
+	// List lists an organization's entries matching the filter with their
+	// actors, newest first
+	List(filter AuditLogFilter) ([]models.AuditLogEntry, error)
+}
+
+// AuditLogFilter holds filtering options for listing audit log entries
+type AuditLogFilter struct {
+	OrganizationID uint64
+	ActorID        *uint64
+	Action         *models.AuditAction
+	TargetType     *models.AuditTargetType
+	TargetID       *uint64
+	Since          *time.Time
+	Until          *time.Time
+	// BeforeID only matches entries older than the one with this ID
+	BeforeID *uint64
+	Limit    int
 }
 
 // UserRepository defines the interface for user data access
@@ -96,4 +343,147 @@ type UserRepository interface {
 
 	// FindByUsername finds a user by username
 	FindByUsername(username string) (*models.User, error)
+
+	// CreateWithIdentity creates a user, their personal organization, the
+	// membership and a linked external identity within a single transaction.
+	CreateWithIdentity(user *models.User, org *models.Organization, member *models.OrganizationMember, identity *models.UserIdentity) error
+
+	// FindByIdentity finds the user linked to an external identity
+	FindByIdentity(issuer, subject string) (*models.User, error)
+
+	// CreateIdentity links an external identity to an existing user
+	CreateIdentity(identity *models.UserIdentity) error
+
+	// UpdatePassword replaces a user's password hash
+	UpdatePassword(userID uint64, passwordHash string) error
+
+	// ListIdentities lists the external identities linked to a user
+	ListIdentities(userID uint64) ([]models.UserIdentity, error)
+
+	// DeleteAccount removes a user's personal data and memberships, hands off or
+	// deletes their organizations and anonymizes the user row within a single transaction
+	DeleteAccount(deletion AccountDeletion) error
+}
+
+// AccountDeletion describes how a user's account is removed
+type AccountDeletion struct {
+	UserID             uint64
+	AnonymizedUsername string
+	// NewOwners maps organizations the user solely owns to the member promoted to owner
+	NewOwners map[uint64]uint64
+	// DeleteOrganizationIDs lists organizations in which the user is the only member
+	DeleteOrganizationIDs []uint64
+}
+
+// AccessTokenRepository defines the interface for personal access token data access
+type AccessTokenRepository interface {
+	// Create creates a new personal access token
+	Create(token *models.PersonalAccessToken) error
+
+	// FindByHash finds a token by the hash of its secret value
+	FindByHash(hash string) (*models.PersonalAccessToken, error)
+
+	// FindByIDForUser finds a token by ID that belongs to the given user
+	FindByIDForUser(id, userID uint64) (*models.PersonalAccessToken, error)
+
+	// ListByUserID lists all tokens owned by a user, newest first
+	ListByUserID(userID uint64) ([]models.PersonalAccessToken, error)
+
+	// Revoke marks a token as revoked
+	Revoke(id uint64, revokedAt time.Time) error
+
+	// TouchLastUsed records when a token was last used
+	TouchLastUsed(id uint64, usedAt time.Time) error
+}
+
+// TwoFactorRepository defines the interface for two-factor authentication data access
+type TwoFactorRepository interface {
+	// FindByUserID finds the TOTP enrollment of a user
+	FindByUserID(userID uint64) (*models.UserTwoFactor, error)
+
+	// SavePending stores a new, not yet enabled TOTP secret, replacing any previous one
+	SavePending(twoFactor *models.UserTwoFactor) error
+
+	// Enable activates the enrollment, records the verified time step and
+	// replaces the user's recovery codes within a single transaction
+	Enable(userID uint64, enabledAt time.Time, step int64, codeHashes []string) error
+
+	// ConsumeStep records a used time step, returning false if it (or a later
+	// one) was already used so that codes cannot be replayed
+	ConsumeStep(userID uint64, step int64) (bool, error)
+
+	// ReplaceRecoveryCodes deletes all recovery codes of a user and stores new ones
+	ReplaceRecoveryCodes(userID uint64, codeHashes []string) error
+
+	// UseRecoveryCode marks an unused recovery code as used, returning false if none matched
+	UseRecoveryCode(userID uint64, codeHash string, usedAt time.Time) (bool, error)
+
+	// CountUnusedRecoveryCodes counts the recovery codes a user has left
+	CountUnusedRecoveryCodes(userID uint64) (int64, error)
+
+	// Delete removes the enrollment and all recovery codes of a user
+	Delete(userID uint64) error
+}
+
+// SessionRepository defines the interface for login session registry access
+type SessionRepository interface {
+	// Create records a new login session
+	Create(session *models.UserSession) error
+
+	// FindByHash finds a session by the hash of its token
+	FindByHash(hash string) (*models.UserSession, error)
+
+	// ListActiveByUserID lists a user's sessions that are not revoked or expired, most recently seen first
+	ListActiveByUserID(userID uint64, now time.Time) ([]models.UserSession, error)
+
+	// Revoke revokes a session owned by the given user, returning false if no active session matched
+	Revoke(id, userID uint64, revokedAt time.Time) (bool, error)
+
+	// RevokeAllForUser revokes every active session of a user except keepID (0 keeps none)
+	RevokeAllForUser(userID, keepID uint64, revokedAt time.Time) (int64, error)
+
+	// TouchLastSeen records when a session was last used
+	TouchLastSeen(id uint64, seenAt time.Time) error
+}
+
+// LoginAttemptStore tracks failed login attempts and temporary lockouts by key.
+// Implementations must be safe for concurrent use.
+type LoginAttemptStore interface {
+	// RecordFailure increments the failure count for key, keeping it for window
+	// after this failure, and returns the new count
+	RecordFailure(key string, window time.Duration) (int64, error)
+
+	// Lock blocks key for the given duration
+	Lock(key string, duration time.Duration) error
+
+	// LockedFor returns how long key remains locked, or zero if it is not locked
+	LockedFor(key string) (time.Duration, error)
+
+	// Reset clears the failure count and lock of key
+	Reset(key string) error
+}
+
+// PasswordResetRepository defines the interface for password reset token data access
+type PasswordResetRepository interface {
+	// Create stores a new reset token, invalidating the user's outstanding tokens
+	Create(token *models.PasswordResetToken) error
+
+	// FindByHash finds a reset token by the hash of its secret value
+	FindByHash(hash string) (*models.PasswordResetToken, error)
+
+	// MarkUsed marks an unused token as used, returning false if it was already used
+	MarkUsed(id uint64, usedAt time.Time) (bool, error)
+}
+
+// RetentionRepository defines the interface for permanently removing soft-deleted data
+type RetentionRepository interface {
+	// PurgeDeletedBefore hard-deletes rows soft-deleted before the cutoff
+	PurgeDeletedBefore(cutoff time.Time) (PurgeResult, error)
+}
+
+// PurgeResult counts the rows removed by a purge
+type PurgeResult struct {
+	Organizations   int64
+	Tasks           int64
+	TaskAssignments int64
 }
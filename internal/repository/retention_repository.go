@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormRetentionRepository is a GORM implementation of RetentionRepository
+type GormRetentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository creates a new RetentionRepository
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &GormRetentionRepository{db: db}
+}
+
+// PurgeDeletedBefore hard-deletes organizations, tasks and task assignments
+// soft-deleted before the cutoff, along with the labels and dependencies of
+// the purged tasks and the audit logs of the purged organizations. Users are
+// kept because deleted accounts stay anonymized as the creators of their tasks.
+func (r *GormRetentionRepository) PurgeDeletedBefore(cutoff time.Time) (PurgeResult, error) {
+	var result PurgeResult
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Assignments go first, including the ones still attached to purged tasks
+		expiredTasks := tx.Unscoped().Model(&models.Task{}).Select("id").Where("deleted_at < ?", cutoff)
+		assignments := tx.Unscoped().
+			Where("deleted_at < ? OR task_id IN (?)", cutoff, expiredTasks).
+			Delete(&models.TaskAssignment{})
+		if assignments.Error != nil {
+			return assignments.Error
+		}
+		result.TaskAssignments = assignments.RowsAffected
+
+		if err := tx.Where("task_id IN (?)", expiredTasks).Delete(&models.TaskLabel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN (?) OR blocker_id IN (?)", expiredTasks, expiredTasks).
+			Delete(&models.TaskDependency{}).Error; err != nil {
+			return err
+		}
+
+		tasks := tx.Unscoped().Where("deleted_at < ?", cutoff).Delete(&models.Task{})
+		if tasks.Error != nil {
+			return tasks.Error
+		}
+		result.Tasks = tasks.RowsAffected
+
+		expiredOrganizations := tx.Unscoped().Model(&models.Organization{}).Select("id").Where("deleted_at < ?", cutoff)
+		if err := tx.Where("organization_id IN (?)", expiredOrganizations).Delete(&models.AuditLogEntry{}).Error; err != nil {
+			return err
+		}
+
+		// Tasks of a deleted organization are deleted with it, so none remain here
+		organizations := tx.Unscoped().Where("deleted_at < ?", cutoff).Delete(&models.Organization{})
+		if organizations.Error != nil {
+			return organizations.Error
+		}
+		result.Organizations = organizations.RowsAffected
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormSessionRepository is a GORM implementation of SessionRepository
+type GormSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &GormSessionRepository{db: db}
+}
+
+// Create records a new login session
+func (r *GormSessionRepository) Create(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByHash finds a session by the hash of its token
+func (r *GormSessionRepository) FindByHash(hash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUserID lists a user's active sessions, most recently seen first
+func (r *GormSessionRepository) ListActiveByUserID(userID uint64, now time.Time) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	if err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Revoke revokes a session owned by the given user
+func (r *GormSessionRepository) Revoke(id, userID uint64, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeAllForUser revokes every active session of a user except keepID
+func (r *GormSessionRepository) RevokeAllForUser(userID, keepID uint64, revokedAt time.Time) (int64, error) {
+	query := r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepID != 0 {
+		query = query.Where("id <> ?", keepID)
+	}
+
+	result := query.Update("revoked_at", revokedAt)
+	return result.RowsAffected, result.Error
+}
+
+// TouchLastSeen records when a session was last used
+func (r *GormSessionRepository) TouchLastSeen(id uint64, seenAt time.Time) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("id = ?", id).
+		Update("last_seen_at", seenAt).Error
+}
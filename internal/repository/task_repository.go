@@ -1,7 +1,14 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/database"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/utils"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -11,14 +18,29 @@ type GormTaskRepository struct {
 	db *gorm.DB
 }
 
+// ErrTaskLimitReached is returned when creating or restoring tasks would take
+// an organization over its task limit.
+var ErrTaskLimitReached = errors.New("task repository: task limit reached")
+
 // NewTaskRepository creates a new TaskRepository
 func NewTaskRepository(db *gorm.DB) TaskRepository {
 	return &GormTaskRepository{db: db}
 }
 
-// Create creates a new task
-func (r *GormTaskRepository) Create(task *models.Task) error {
-	return r.db.Create(task).Error
+// includeDeletedUsers keeps deleted users, who are anonymized rather than
+// removed, visible as the creators of their tasks
+func includeDeletedUsers(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// Create creates a new task within the organization's task limit
+func (r *GormTaskRepository) Create(task *models.Task, maxTasks *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockTaskCapacity(tx, task.OrganizationID, 1, maxTasks); err != nil {
+			return err
+		}
+		return tx.Create(task).Error
+	})
 }
 
 // FindByID finds a task by ID with optional preloading
@@ -28,6 +50,10 @@ func (r *GormTaskRepository) FindByID(id uint64, preload ...string) (*models.Tas
 
 	// Apply preloading if specified
 	for _, p := range preload {
+		if p == "Creator" {
+			query = query.Preload(p, includeDeletedUsers)
+			continue
+		}
 		query = query.Preload(p)
 	}
 
@@ -52,6 +78,9 @@ func (r *GormTaskRepository) List(filter TaskFilter) ([]models.Task, int64, erro
 	if filter.Status != nil {
 		query = query.Where("tasks.status = ?", *filter.Status)
 	}
+	if len(filter.ExcludeStatuses) > 0 {
+		query = query.Where("tasks.status NOT IN ?", filter.ExcludeStatuses)
+	}
 	if filter.CreatorID != nil {
 		query = query.Where("tasks.creator_id = ?", *filter.CreatorID)
 	}
@@ -69,6 +98,22 @@ func (r *GormTaskRepository) List(filter TaskFilter) ([]models.Task, int64, erro
 	if filter.DueDateTo != nil {
 		query = query.Where("tasks.due_date < ?", *filter.DueDateTo)
 	}
+	if filter.ParentID != nil {
+		query = query.Where("tasks.parent_id = ?", *filter.ParentID)
+	}
+	if filter.TopLevelOnly {
+		query = query.Where("tasks.parent_id IS NULL")
+	}
+	if len(filter.LabelIDs) > 0 {
+		labelSubQuery := r.db.Model(&models.TaskLabel{}).
+			Where("task_labels.task_id = tasks.id").
+			Where("task_labels.label_id IN ?", filter.LabelIDs)
+		if filter.MatchAllLabels {
+			query = query.Where("(?) = ?", labelSubQuery.Select("COUNT(*)"), len(filter.LabelIDs))
+		} else {
+			query = query.Where("EXISTS (?)", labelSubQuery.Select("1"))
+		}
+	}
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -76,40 +121,188 @@ func (r *GormTaskRepository) List(filter TaskFilter) ([]models.Task, int64, erro
 	}
 
 	listQuery := query
-	if filter.SortByDueDate {
-		listQuery = listQuery.Order("CASE WHEN tasks.due_date IS NULL THEN 1 ELSE 0 END, tasks.due_date ASC")
-	} else {
+	if len(filter.Sort) == 0 {
 		listQuery = listQuery.Order("tasks.created_at DESC")
 	}
+	for _, sort := range filter.Sort {
+		listQuery = listQuery.Order(taskSortExpr(r.db, sort))
+	}
+	// Break ties so pages neither overlap nor skip tasks
+	listQuery = listQuery.Order("tasks.id DESC")
 
 	if filter.Page > 0 && filter.PageSize > 0 {
 		offset := (filter.Page - 1) * filter.PageSize
 		listQuery = listQuery.Offset(offset).Limit(filter.PageSize)
 	}
 
-	if err := listQuery.Preload("Creator").Find(&tasks).Error; err != nil {
+	if err := listQuery.Preload("Creator", includeDeletedUsers).Preload("Labels.Label").Find(&tasks).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return tasks, total, nil
 }
 
-// Update updates a task
+// taskPriorityRank ranks priorities from urgent (0) to low so they sort by
+// urgency rather than alphabetically
+var taskPriorityRank = func() string {
+	var b strings.Builder
+	b.WriteString("CASE tasks.priority")
+	for rank, priority := range models.TaskPriorities {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", priority, rank)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(models.TaskPriorities))
+	return b.String()
+}()
+
+// taskSortExpr returns the ORDER BY expression for a sort key. Titles are
+// compared case-insensitively since the default collations of the supported
+// databases disagree on case.
+func taskSortExpr(db *gorm.DB, sort TaskSort) string {
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+
+	switch sort.Field {
+	case TaskSortPriority:
+		return fmt.Sprintf("%s %s", taskPriorityRank, direction)
+	case TaskSortDueDate:
+		return database.NullsLastExpr(db, "tasks.due_date", sort.Desc)
+	case TaskSortTitle:
+		return fmt.Sprintf("LOWER(tasks.title) %s", direction)
+	case TaskSortUpdatedAt:
+		return fmt.Sprintf("tasks.updated_at %s", direction)
+	default:
+		return fmt.Sprintf("tasks.created_at %s", direction)
+	}
+}
+
+// Update updates the columns of a task, leaving its assignments and labels
+// to their own methods so that a stale copy cannot bring removed ones back
 func (r *GormTaskRepository) Update(task *models.Task) error {
-	return r.db.Save(task).Error
+	return r.db.Omit(clause.Associations).Save(task).Error
 }
 
-// Delete soft deletes a task
+// Delete soft deletes a task and its subtasks along with their assignments
 func (r *GormTaskRepository) Delete(id uint64) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("task_id = ?", id).Delete(&models.TaskAssignment{}).Error; err != nil {
+		subtaskIDs, err := collectSubtasks(tx, id)
+		if err != nil {
 			return err
 		}
+		return softDeleteTasks(tx, time.Now(), "id IN ?", append([]uint64{id}, subtaskIDs...))
+	})
+}
+
+// ListDeleted lists a page of the soft-deleted tasks of an organization,
+// most recently deleted first
+func (r *GormTaskRepository) ListDeleted(organizationID uint64, params utils.PaginationParams) ([]models.Task, int64, error) {
+	query := r.db.Unscoped().Model(&models.Task{}).
+		Where("organization_id = ? AND deleted_at IS NOT NULL", organizationID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []models.Task
+	if err := query.Order("deleted_at DESC").Order("id DESC").
+		Scopes(database.Paginate(params)).
+		Preload("Creator", includeDeletedUsers).
+		Preload("Labels.Label").
+		Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
 
-		return tx.Delete(&models.Task{}, id).Error
+// FindDeletedByID finds a soft-deleted task along with the assignments removed with it
+func (r *GormTaskRepository) FindDeletedByID(id uint64) (*models.Task, error) {
+	var task models.Task
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&task, id).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Unscoped().
+		Where("task_id = ? AND deleted_at = ?", task.ID, task.DeletedAt.Time).
+		Find(&task.Assignments).Error; err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// Restore brings a soft-deleted task back along with the subtasks and
+// assignments removed with it. Assignments of users who have left the
+// organization stay deleted.
+func (r *GormTaskRepository) Restore(task *models.Task, maxTasks *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		subtaskIDs, err := collectSubtasks(tx, task.ID, deletedAt(task.DeletedAt.Time))
+		if err != nil {
+			return err
+		}
+		taskIDs := append([]uint64{task.ID}, subtaskIDs...)
+		if err := lockTaskCapacity(tx, task.OrganizationID, len(taskIDs), maxTasks); err != nil {
+			return err
+		}
+
+		members := tx.Model(&models.OrganizationMember{}).Select("user_id").Where("organization_id = ?", task.OrganizationID)
+		if err := tx.Unscoped().Model(&models.TaskAssignment{}).
+			Where("task_id IN ? AND deleted_at = ? AND user_id IN (?)", taskIDs, task.DeletedAt.Time, members).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&models.Task{}).Where("id IN ?", taskIDs).Update("deleted_at", nil).Error
 	})
 }
 
+// deletedAt scopes a query to the rows soft-deleted at the given time
+func deletedAt(at time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Where("deleted_at = ?", at)
+	}
+}
+
+// ListSubtaskIDs lists the active direct subtasks of the given tasks
+func (r *GormTaskRepository) ListSubtaskIDs(parentIDs []uint64) ([]uint64, error) {
+	var ids []uint64
+	if err := r.db.Model(&models.Task{}).Where("parent_id IN ?", parentIDs).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CountSubtasksByStatus counts the active direct subtasks of each of the
+// given tasks by status
+func (r *GormTaskRepository) CountSubtasksByStatus(parentIDs []uint64) (map[uint64]map[models.TaskStatus]int64, error) {
+	counts := make(map[uint64]map[models.TaskStatus]int64)
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParentID uint64
+		Status   models.TaskStatus
+		Count    int64
+	}
+	if err := r.db.Model(&models.Task{}).
+		Select("parent_id, status, COUNT(*) AS count").
+		Where("parent_id IN ?", parentIDs).
+		Group("parent_id, status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.ParentID] == nil {
+			counts[row.ParentID] = make(map[models.TaskStatus]int64)
+		}
+		counts[row.ParentID][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 // AssignUsers assigns multiple users to a task
 func (r *GormTaskRepository) AssignUsers(taskID uint64, userIDs []uint64) error {
 	assignments := make([]models.TaskAssignment, len(userIDs))
@@ -135,6 +328,154 @@ func (r *GormTaskRepository) UnassignUsers(taskID uint64, userIDs []uint64) erro
 		Delete(&models.TaskAssignment{}).Error
 }
 
+// AddLabels attaches labels to a task, skipping the ones already attached
+func (r *GormTaskRepository) AddLabels(taskID uint64, labelIDs []uint64) error {
+	taskLabels := make([]models.TaskLabel, len(labelIDs))
+	for i, labelID := range labelIDs {
+		taskLabels[i] = models.TaskLabel{
+			TaskID:  taskID,
+			LabelID: labelID,
+		}
+	}
+
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&taskLabels).Error
+}
+
+// RemoveLabels detaches labels from a task
+func (r *GormTaskRepository) RemoveLabels(taskID uint64, labelIDs []uint64) error {
+	return r.db.Where("task_id = ? AND label_id IN ?", taskID, labelIDs).
+		Delete(&models.TaskLabel{}).Error
+}
+
+// CountLabelsByIDs counts how many of the given label IDs belong to the organization
+func (r *GormTaskRepository) CountLabelsByIDs(labelIDs []uint64, organizationID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Label{}).
+		Where("organization_id = ? AND id IN ?", organizationID, labelIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// AddDependency records that a task is blocked by another task unless that
+// would create a cycle. The organization row is locked so that concurrent
+// additions cannot close a cycle between them.
+func (r *GormTaskRepository) AddDependency(organizationID, taskID, blockerID uint64) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		waits, err := waitsOn(tx, blockerID, taskID)
+		if err != nil || waits {
+			return err
+		}
+
+		added = true
+		dependency := models.TaskDependency{TaskID: taskID, BlockerID: blockerID}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dependency).Error
+	})
+	return added, err
+}
+
+// RemoveDependency removes a dependency, reporting whether there was one
+func (r *GormTaskRepository) RemoveDependency(taskID, blockerID uint64) (bool, error) {
+	result := r.db.Where("task_id = ? AND blocker_id = ?", taskID, blockerID).Delete(&models.TaskDependency{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// waitsOn reports whether a task waits on another one through any chain of
+// dependencies, including tasks in the trash
+func waitsOn(tx *gorm.DB, taskID, blockerID uint64) (bool, error) {
+	seen := map[uint64]struct{}{taskID: {}}
+	for frontier := []uint64{taskID}; len(frontier) > 0; {
+		var blockerIDs []uint64
+		if err := tx.Model(&models.TaskDependency{}).
+			Where("task_id IN ?", frontier).
+			Distinct().
+			Pluck("blocker_id", &blockerIDs).Error; err != nil {
+			return false, err
+		}
+
+		frontier = frontier[:0]
+		for _, id := range blockerIDs {
+			if id == blockerID {
+				return true, nil
+			}
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return false, nil
+}
+
+// ListBlockers lists the active tasks blocking a task
+func (r *GormTaskRepository) ListBlockers(taskID uint64) ([]models.Task, error) {
+	blockerIDs := r.db.Model(&models.TaskDependency{}).Select("blocker_id").Where("task_id = ?", taskID)
+	return r.listDependencyTasks(blockerIDs)
+}
+
+// ListDependents lists the active tasks blocked by a task
+func (r *GormTaskRepository) ListDependents(taskID uint64) ([]models.Task, error) {
+	dependentIDs := r.db.Model(&models.TaskDependency{}).Select("task_id").Where("blocker_id = ?", taskID)
+	return r.listDependencyTasks(dependentIDs)
+}
+
+// listDependencyTasks loads the active tasks selected by the subquery in the
+// order they were created
+func (r *GormTaskRepository) listDependencyTasks(ids *gorm.DB) ([]models.Task, error) {
+	var tasks []models.Task
+	if err := r.db.Where("id IN (?)", ids).
+		Order("id").
+		Preload("Creator", includeDeletedUsers).
+		Preload("Labels.Label").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListBlockerStatuses lists the statuses of the active blockers of each of
+// the given tasks
+func (r *GormTaskRepository) ListBlockerStatuses(taskIDs []uint64) (map[uint64][]models.TaskStatus, error) {
+	statuses := make(map[uint64][]models.TaskStatus)
+	if len(taskIDs) == 0 {
+		return statuses, nil
+	}
+
+	var rows []struct {
+		TaskID uint64
+		Status models.TaskStatus
+	}
+	if err := r.db.Model(&models.TaskDependency{}).
+		Select("task_dependencies.task_id, tasks.status").
+		Joins("JOIN tasks ON tasks.id = task_dependencies.blocker_id AND tasks.deleted_at IS NULL").
+		Where("task_dependencies.task_id IN ?", taskIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		statuses[row.TaskID] = append(statuses[row.TaskID], row.Status)
+	}
+	return statuses, nil
+}
+
+// ListDependenciesAmong lists the dependencies between the given tasks
+func (r *GormTaskRepository) ListDependenciesAmong(taskIDs []uint64) ([]models.TaskDependency, error) {
+	var dependencies []models.TaskDependency
+	if len(taskIDs) == 0 {
+		return dependencies, nil
+	}
+	if err := r.db.Where("task_id IN ? AND blocker_id IN ?", taskIDs, taskIDs).
+		Find(&dependencies).Error; err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
 // FindAssignment finds a specific task assignment
 func (r *GormTaskRepository) FindAssignment(taskID, userID uint64) (*models.TaskAssignment, error) {
 	var assignment models.TaskAssignment
@@ -145,6 +486,27 @@ func (r *GormTaskRepository) FindAssignment(taskID, userID uint64) (*models.Task
 	return &assignment, nil
 }
 
+// lockTaskCapacity locks the organization row so that task creations are
+// serialized and fails with ErrTaskLimitReached if it cannot take n more
+// active tasks
+func lockTaskCapacity(tx *gorm.DB, organizationID uint64, n int, maxTasks *int) error {
+	if maxTasks == nil {
+		return nil
+	}
+	if err := lockOrganization(tx, organizationID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.Task{}).Where("organization_id = ?", organizationID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count+int64(n) > int64(*maxTasks) {
+		return ErrTaskLimitReached
+	}
+	return nil
+}
+
 // CountUsersByIDs counts how many of the given user IDs exist in the organization
 func (r *GormTaskRepository) CountUsersByIDs(userIDs []uint64, organizationID uint64) (int64, error) {
 	var count int64
@@ -157,3 +519,27 @@ func (r *GormTaskRepository) CountUsersByIDs(userIDs []uint64, organizationID ui
 
 	return count, err
 }
+
+// ListByCreatorID lists all tasks created by a user across organizations
+func (r *GormTaskRepository) ListByCreatorID(userID uint64) ([]models.Task, error) {
+	var tasks []models.Task
+	if err := r.db.Where("creator_id = ?", userID).
+		Order("created_at ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListAssignedToUser lists all tasks currently assigned to a user across organizations
+func (r *GormTaskRepository) ListAssignedToUser(userID uint64) ([]models.Task, error) {
+	var tasks []models.Task
+	if err := r.db.
+		Joins("JOIN task_assignments ON task_assignments.task_id = tasks.id").
+		Where("task_assignments.user_id = ? AND task_assignments.deleted_at IS NULL", userID).
+		Order("tasks.created_at ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yukikurage/task-management-api/internal/models"
+)
+
+func TestTaskRepository_UpdateLeavesAssociations(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	fixture := seedLifecycleFixture(t, db, "org")
+	repo := NewTaskRepository(db)
+
+	stale, err := repo.FindByID(fixture.task.ID, "Assignments", "Labels")
+	require.NoError(t, err)
+	require.Len(t, stale.Labels, 1)
+	require.Len(t, stale.Assignments, 2)
+
+	// Removed while the stale copy is being edited
+	require.NoError(t, repo.RemoveLabels(stale.ID, []uint64{stale.Labels[0].LabelID}))
+	require.NoError(t, repo.UnassignUsers(stale.ID, []uint64{fixture.member.ID}))
+
+	stale.Title = "renamed"
+	require.NoError(t, repo.Update(stale))
+
+	updated, err := repo.FindByID(stale.ID, "Assignments", "Labels")
+	require.NoError(t, err)
+	require.Equal(t, "renamed", updated.Title)
+	require.Empty(t, updated.Labels)
+	require.Len(t, updated.Assignments, 1)
+}
+
+func TestTaskRepository_CreateWithinLimitConcurrently(t *testing.T) {
+	db := setupConcurrentRepositoryTestDB(t)
+	repo := NewTaskRepository(db)
+
+	creator := &models.User{Username: "creator"}
+	require.NoError(t, db.Create(creator).Error)
+	org := &models.Organization{Name: "Org", InviteCode: "ORG_CODE"}
+	require.NoError(t, db.Create(org).Error)
+
+	// The creations start together, so only the lock keeps them from all
+	// counting the tasks before any of them is written
+	const attempts = 20
+	limit := attempts - 1
+	errs := make([]error, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			task := &models.Task{Title: fmt.Sprintf("Task %d", i), CreatorID: creator.ID, OrganizationID: org.ID}
+			errs[i] = repo.Create(task, &limit)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	rejected := 0
+	for _, err := range errs {
+		if errors.Is(err, ErrTaskLimitReached) {
+			rejected++
+			continue
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, rejected)
+	require.Equal(t, int64(limit), countRows(t, db, &models.Task{}, "organization_id = ?", org.ID))
+}
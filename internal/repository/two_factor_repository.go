@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// GormTwoFactorRepository is a GORM implementation of TwoFactorRepository
+type GormTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new TwoFactorRepository
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &GormTwoFactorRepository{db: db}
+}
+
+// FindByUserID finds the TOTP enrollment of a user
+func (r *GormTwoFactorRepository) FindByUserID(userID uint64) (*models.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+	if err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error; err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// SavePending stores a new, not yet enabled TOTP secret, replacing any previous one
+func (r *GormTwoFactorRepository) SavePending(twoFactor *models.UserTwoFactor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", twoFactor.UserID).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return err
+		}
+		twoFactor.EnabledAt = nil
+		twoFactor.LastUsedStep = 0
+		return tx.Create(twoFactor).Error
+	})
+}
+
+// Enable activates the enrollment and replaces the user's recovery codes
+func (r *GormTwoFactorRepository) Enable(userID uint64, enabledAt time.Time, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserTwoFactor{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{
+				"enabled_at":     enabledAt,
+				"last_used_step": step,
+			}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// ConsumeStep records a used time step unless it was already used
+func (r *GormTwoFactorRepository) ConsumeStep(userID uint64, step int64) (bool, error) {
+	result := r.db.Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes deletes all recovery codes of a user and stores new ones
+func (r *GormTwoFactorRepository) ReplaceRecoveryCodes(userID uint64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint64, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	codes := make([]models.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+func (r *GormTwoFactorRepository) UseRecoveryCode(userID uint64, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnusedRecoveryCodes counts the recovery codes a user has left
+func (r *GormTwoFactorRepository) CountUnusedRecoveryCodes(userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Delete removes the enrollment and all recovery codes of a user
+func (r *GormTwoFactorRepository) Delete(userID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error
+	})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/yukikurage/task-management-api/internal/models"
 	"gorm.io/gorm"
@@ -20,6 +21,8 @@ var (
 	ErrCreateOrganization = errors.New("user repository: create organization failed")
 	// ErrCreateOrganizationMember is returned when creating an organization member fails inside the signup transaction.
 	ErrCreateOrganizationMember = errors.New("user repository: create organization member failed")
+	// ErrCreateIdentity is returned when linking an external identity fails inside the signup transaction.
+	ErrCreateIdentity = errors.New("user repository: create identity failed")
 )
 
 // NewUserRepository creates a new UserRepository
@@ -35,25 +38,46 @@ func (r *GormUserRepository) Create(user *models.User) error {
 // CreateWithPersonalOrganization creates a user, a personal organization, and the membership atomically.
 func (r *GormUserRepository) CreateWithPersonalOrganization(user *models.User, org *models.Organization, member *models.OrganizationMember) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(user).Error; err != nil {
-			return fmt.Errorf("%w: %v", ErrCreateUser, err)
-		}
+		return createWithPersonalOrganization(tx, user, org, member)
+	})
+}
 
-		if err := tx.Create(org).Error; err != nil {
-			return fmt.Errorf("%w: %v", ErrCreateOrganization, err)
+// CreateWithIdentity creates a user with a personal organization and links an external identity atomically.
+func (r *GormUserRepository) CreateWithIdentity(user *models.User, org *models.Organization, member *models.OrganizationMember, identity *models.UserIdentity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createWithPersonalOrganization(tx, user, org, member); err != nil {
+			return err
 		}
 
-		member.OrganizationID = org.ID
-		member.UserID = user.ID
-
-		if err := tx.Create(member).Error; err != nil {
-			return fmt.Errorf("%w: %v", ErrCreateOrganizationMember, err)
+		identity.UserID = user.ID
+		if err := tx.Create(identity).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrCreateIdentity, err)
 		}
 
 		return nil
 	})
 }
 
+// createWithPersonalOrganization performs the signup inserts inside an existing transaction.
+func createWithPersonalOrganization(tx *gorm.DB, user *models.User, org *models.Organization, member *models.OrganizationMember) error {
+	if err := tx.Create(user).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrCreateUser, err)
+	}
+
+	if err := tx.Create(org).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrCreateOrganization, err)
+	}
+
+	member.OrganizationID = org.ID
+	member.UserID = user.ID
+
+	if err := tx.Create(member).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrCreateOrganizationMember, err)
+	}
+
+	return nil
+}
+
 // FindByID finds a user by ID
 func (r *GormUserRepository) FindByID(id uint64) (*models.User, error) {
 	var user models.User
@@ -71,3 +95,90 @@ func (r *GormUserRepository) FindByUsername(username string) (*models.User, erro
 	}
 	return &user, nil
 }
+
+// FindByIdentity finds the user linked to an external identity
+func (r *GormUserRepository) FindByIdentity(issuer, subject string) (*models.User, error) {
+	var user models.User
+	if err := r.db.
+		Joins("JOIN user_identities ON user_identities.user_id = users.id").
+		Where("user_identities.issuer = ? AND user_identities.subject = ?", issuer, subject).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateIdentity links an external identity to an existing user
+func (r *GormUserRepository) CreateIdentity(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// UpdatePassword replaces a user's password hash
+func (r *GormUserRepository) UpdatePassword(userID uint64, passwordHash string) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("password_hash", passwordHash).Error
+}
+
+// ListIdentities lists the external identities linked to a user
+func (r *GormUserRepository) ListIdentities(userID uint64) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	if err := r.db.Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// DeleteAccount removes everything tied to the user except authored tasks, which
+// keep pointing at the anonymized, soft-deleted user row.
+func (r *GormUserRepository) DeleteAccount(deletion AccountDeletion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, orgID := range deletion.DeleteOrganizationIDs {
+			if err := deleteOrganization(tx, orgID, time.Now()); err != nil {
+				return fmt.Errorf("delete organization %d: %w", orgID, err)
+			}
+		}
+
+		for orgID, newOwnerID := range deletion.NewOwners {
+			if err := tx.Model(&models.OrganizationMember{}).
+				Where("organization_id = ? AND user_id = ?", orgID, newOwnerID).
+				Update("role", models.RoleOwner).Error; err != nil {
+				return fmt.Errorf("transfer ownership of organization %d: %w", orgID, err)
+			}
+		}
+
+		// Personal data and credentials are removed for good
+		for _, model := range []interface{}{
+			&models.OrganizationMember{},
+			&models.OrganizationInviteUse{},
+			&models.TaskAssignment{},
+			&models.UserIdentity{},
+			&models.RecoveryCode{},
+			&models.UserTwoFactor{},
+			&models.PersonalAccessToken{},
+			&models.UserSession{},
+			&models.PasswordResetToken{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", deletion.UserID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("invitee_id = ?", deletion.UserID).Delete(&models.OrganizationInvitation{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", deletion.UserID).
+			Updates(map[string]interface{}{
+				"username":      deletion.AnonymizedUsername,
+				"password_hash": "",
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.User{}, deletion.UserID).Error
+	})
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAccessTokenNotFound     = errors.New("access token not found")
+	ErrInvalidAccessTokenName  = errors.New("token name cannot be empty")
+	ErrInvalidAccessTokenScope = errors.New("invalid token scope")
+	ErrInvalidAccessTokenTTL   = errors.New("invalid token expiry")
+	ErrFailedToGenerateToken   = errors.New("failed to generate token")
+)
+
+// AccessTokenService manages personal access tokens.
+type AccessTokenService struct {
+	tokenRepo repository.AccessTokenRepository
+}
+
+// NewAccessTokenService creates a new AccessTokenService.
+func NewAccessTokenService(tokenRepo repository.AccessTokenRepository) *AccessTokenService {
+	return &AccessTokenService{
+		tokenRepo: tokenRepo,
+	}
+}
+
+// CreateAccessTokenInput represents parameters to mint a new token.
+type CreateAccessTokenInput struct {
+	UserID        uint64
+	Name          string
+	Scopes        []models.TokenScope
+	ExpiresInDays *int
+}
+
+// CreateAccessToken mints a new token and returns it along with the plaintext
+// secret. The secret is only available at creation time.
+func (s *AccessTokenService) CreateAccessToken(input CreateAccessTokenInput) (*models.PersonalAccessToken, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", ErrInvalidAccessTokenName
+	}
+
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var expiresAt *time.Time
+	if input.ExpiresInDays != nil {
+		days := *input.ExpiresInDays
+		if days < 1 || days > constants.AccessTokenMaxLifetimeDays {
+			return nil, "", ErrInvalidAccessTokenTTL
+		}
+		t := time.Now().AddDate(0, 0, days)
+		expiresAt = &t
+	}
+
+	secret, err := utils.GenerateSecureToken(constants.AccessTokenPrefix, constants.AccessTokenByteLength)
+	if err != nil {
+		return nil, "", ErrFailedToGenerateToken
+	}
+
+	token := &models.PersonalAccessToken{
+		UserID:      input.UserID,
+		Name:        name,
+		TokenHash:   utils.HashToken(secret),
+		TokenPrefix: secret[:constants.AccessTokenDisplayPrefixLength],
+		Scopes:      scopes,
+		ExpiresAt:   expiresAt,
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, "", fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return token, secret, nil
+}
+
+// ListAccessTokens returns all tokens owned by a user.
+func (s *AccessTokenService) ListAccessTokens(userID uint64) ([]models.PersonalAccessToken, error) {
+	tokens, err := s.tokenRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeAccessToken revokes a token owned by the user.
+func (s *AccessTokenService) RevokeAccessToken(userID, tokenID uint64) error {
+	token, err := s.tokenRepo.FindByIDForUser(tokenID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccessTokenNotFound
+		}
+		return fmt.Errorf("failed to find access token: %w", err)
+	}
+
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.tokenRepo.Revoke(token.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeScopes validates and de-duplicates scopes, returning the stored
+// comma-separated form. Write access implies read access.
+func normalizeScopes(scopes []models.TokenScope) (string, error) {
+	if len(scopes) == 0 {
+		return "", ErrInvalidAccessTokenScope
+	}
+
+	hasRead, hasWrite := false, false
+	for _, scope := range scopes {
+		switch scope {
+		case models.TokenScopeRead:
+			hasRead = true
+		case models.TokenScopeWrite:
+			hasRead = true
+			hasWrite = true
+		default:
+			return "", ErrInvalidAccessTokenScope
+		}
+	}
+
+	result := make([]string, 0, 2)
+	if hasRead {
+		result = append(result, string(models.TokenScopeRead))
+	}
+	if hasWrite {
+		result = append(result, string(models.TokenScopeWrite))
+	}
+	return strings.Join(result, ","), nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+)
+
+// AccountService handles account deletion and personal data export.
+type AccountService struct {
+	authService        *AuthService
+	userRepo           repository.UserRepository
+	orgRepo            repository.OrganizationRepository
+	taskRepo           repository.TaskRepository
+	sessionService     *SessionService
+	accessTokenService *AccessTokenService
+	twoFactorService   *TwoFactorService
+}
+
+// NewAccountService creates a new AccountService.
+func NewAccountService(
+	authService *AuthService,
+	userRepo repository.UserRepository,
+	orgRepo repository.OrganizationRepository,
+	taskRepo repository.TaskRepository,
+	sessionService *SessionService,
+	accessTokenService *AccessTokenService,
+	twoFactorService *TwoFactorService,
+) *AccountService {
+	return &AccountService{
+		authService:        authService,
+		userRepo:           userRepo,
+		orgRepo:            orgRepo,
+		taskRepo:           taskRepo,
+		sessionService:     sessionService,
+		accessTokenService: accessTokenService,
+		twoFactorService:   twoFactorService,
+	}
+}
+
+// DeleteAccount permanently deletes a user's account. The password is required
+// unless the account only signs in through single sign-on.
+//
+// Organizations the user solely owns are handed to their longest-standing
+// admin (or member, or guest), or deleted if the user is the only member.
+// Tasks the user created are kept and show the anonymized username.
+func (s *AccountService) DeleteAccount(userID uint64, password string) error {
+	user, err := s.authService.GetUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordHash != "" {
+		if err := s.authService.VerifyPassword(user, password); err != nil {
+			return err
+		}
+	}
+
+	deletion := repository.AccountDeletion{
+		UserID:             user.ID,
+		AnonymizedUsername: constants.DeletedUsernamePrefix + strconv.FormatUint(user.ID, 10),
+		NewOwners:          map[uint64]uint64{},
+	}
+
+	memberships, err := s.orgRepo.ListMembersByUserID(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	for _, membership := range memberships {
+		if membership.Role != models.RoleOwner {
+			continue
+		}
+
+		members, err := s.orgRepo.ListMembers(membership.OrganizationID)
+		if err != nil {
+			return fmt.Errorf("failed to list organization members: %w", err)
+		}
+
+		newOwnerID, needsOwner := successorOwner(members, user.ID)
+		switch {
+		case !needsOwner:
+			// Another owner remains
+		case newOwnerID == 0:
+			deletion.DeleteOrganizationIDs = append(deletion.DeleteOrganizationIDs, membership.OrganizationID)
+		default:
+			deletion.NewOwners[membership.OrganizationID] = newOwnerID
+		}
+	}
+
+	if err := s.userRepo.DeleteAccount(deletion); err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
+
+// successorOwner picks the member to promote when the leaving user is the
+// organization's only owner. needsOwner is false if another owner remains; a
+// zero ID means nobody else is left.
+func successorOwner(members []models.OrganizationMember, leavingUserID uint64) (newOwnerID uint64, needsOwner bool) {
+	var others []models.OrganizationMember
+	for _, member := range members {
+		if member.UserID == leavingUserID {
+			continue
+		}
+		if member.Role == models.RoleOwner {
+			return 0, false
+		}
+		others = append(others, member)
+	}
+
+	if len(others) == 0 {
+		return 0, true
+	}
+
+	sort.Slice(others, func(i, j int) bool {
+		if rank := successorRank(others[i].Role) - successorRank(others[j].Role); rank != 0 {
+			return rank < 0
+		}
+		if !others[i].JoinedAt.Equal(others[j].JoinedAt) {
+			return others[i].JoinedAt.Before(others[j].JoinedAt)
+		}
+		return others[i].UserID < others[j].UserID
+	})
+	return others[0].UserID, true
+}
+
+// successorRank orders roles by preference for inheriting ownership.
+func successorRank(role models.OrganizationRole) int {
+	switch role {
+	case models.RoleAdmin:
+		return 0
+	case models.RoleMember:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// AccountExport holds everything stored about a user.
+type AccountExport struct {
+	User             models.User
+	TwoFactorEnabled bool
+	Identities       []models.UserIdentity
+	Memberships      []models.OrganizationMember
+	CreatedTasks     []models.Task
+	AssignedTasks    []models.Task
+	Sessions         []models.UserSession
+	AccessTokens     []models.PersonalAccessToken
+	ExportedAt       time.Time
+}
+
+// ExportAccount collects the user's personal data for download.
+func (s *AccountService) ExportAccount(userID uint64) (*AccountExport, error) {
+	user, err := s.authService.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &AccountExport{
+		User:       *user,
+		ExportedAt: time.Now(),
+	}
+
+	if export.TwoFactorEnabled, err = s.twoFactorService.IsEnabled(userID); err != nil {
+		return nil, err
+	}
+	if export.Identities, err = s.userRepo.ListIdentities(userID); err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	if export.Memberships, err = s.orgRepo.ListMembersByUserID(userID); err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+	if export.CreatedTasks, err = s.taskRepo.ListByCreatorID(userID); err != nil {
+		return nil, fmt.Errorf("failed to list created tasks: %w", err)
+	}
+	if export.AssignedTasks, err = s.taskRepo.ListAssignedToUser(userID); err != nil {
+		return nil, fmt.Errorf("failed to list assigned tasks: %w", err)
+	}
+	if export.Sessions, err = s.sessionService.ListSessions(userID); err != nil {
+		return nil, err
+	}
+	if export.AccessTokens, err = s.accessTokenService.ListAccessTokens(userID); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
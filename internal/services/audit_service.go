@@ -0,0 +1,201 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+)
+
+var (
+	ErrInvalidAuditAction     = errors.New("unknown audit log action")
+	ErrInvalidAuditTargetType = errors.New("audit log target type must be task, member or organization")
+	ErrInvalidAuditTimeRange  = errors.New("since must be before until")
+)
+
+// auditActions lists every action the audit log records.
+var auditActions = map[models.AuditAction]struct{}{
+	models.AuditActionTaskCreate:                    {},
+	models.AuditActionTaskUpdate:                    {},
+	models.AuditActionTaskDelete:                    {},
+	models.AuditActionTaskRestore:                   {},
+	models.AuditActionTaskAssign:                    {},
+	models.AuditActionTaskUnassign:                  {},
+	models.AuditActionTaskToggleStatus:              {},
+	models.AuditActionTaskTransition:                {},
+	models.AuditActionTaskLabel:                     {},
+	models.AuditActionTaskUnlabel:                   {},
+	models.AuditActionTaskBlock:                     {},
+	models.AuditActionTaskUnblock:                   {},
+	models.AuditActionMemberJoin:                    {},
+	models.AuditActionMemberRemove:                  {},
+	models.AuditActionMemberLeave:                   {},
+	models.AuditActionMemberRoleChange:              {},
+	models.AuditActionOrganizationRename:            {},
+	models.AuditActionOrganizationDelete:            {},
+	models.AuditActionOrganizationArchive:           {},
+	models.AuditActionOrganizationRestore:           {},
+	models.AuditActionOrganizationRegenerateCode:    {},
+	models.AuditActionOrganizationTransferOwnership: {},
+}
+
+// RequestMeta describes the request behind a change for the audit log.
+type RequestMeta struct {
+	IPAddress string
+	UserAgent string
+	// AccessTokenID is set when the request authenticated with a personal access token
+	AccessTokenID *uint64
+}
+
+// AuditService appends to and reads the organization audit log.
+type AuditService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(auditRepo repository.AuditLogRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// auditChange is the before and after value of one changed field.
+type auditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// auditChanges maps changed fields to their values.
+type auditChanges map[string]auditChange
+
+// set records a field unless it kept its value. Values must be comparable.
+func (c auditChanges) set(field string, before, after interface{}) {
+	if before == after {
+		return
+	}
+	c[field] = auditChange{Before: before, After: after}
+}
+
+// auditTime formats an optional time for the audit log.
+func auditTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// auditEvent is a change to record in an organization's audit log.
+type auditEvent struct {
+	OrganizationID uint64
+	ActorID        uint64
+	Action         models.AuditAction
+	TargetType     models.AuditTargetType
+	TargetID       uint64
+	Changes        auditChanges
+}
+
+// record appends an event to the audit log. The change it describes has
+// already been committed, so a failure is logged rather than returned. A nil
+// AuditService records nothing.
+func (s *AuditService) record(meta RequestMeta, event auditEvent) {
+	if s == nil {
+		return
+	}
+
+	changes := event.Changes
+	if changes == nil {
+		changes = auditChanges{}
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		log.Printf("Failed to encode audit log changes for %s: %v", event.Action, err)
+		encoded = []byte("{}")
+	}
+
+	userAgent := meta.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	entry := &models.AuditLogEntry{
+		OrganizationID: event.OrganizationID,
+		ActorID:        event.ActorID,
+		Action:         event.Action,
+		TargetType:     event.TargetType,
+		TargetID:       event.TargetID,
+		Changes:        string(encoded),
+		IPAddress:      meta.IPAddress,
+		UserAgent:      userAgent,
+		AccessTokenID:  meta.AccessTokenID,
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("Failed to record audit log entry %s on %s %d in organization %d: %v",
+			event.Action, event.TargetType, event.TargetID, event.OrganizationID, err)
+	}
+}
+
+// ListAuditLogInput represents filters for listing audit log entries
+type ListAuditLogInput struct {
+	OrganizationID uint64
+	ActorID        *uint64
+	Action         *models.AuditAction
+	TargetType     *models.AuditTargetType
+	TargetID       *uint64
+	Since          *time.Time
+	Until          *time.Time
+	// Cursor continues a previous listing from the entry ID it returned
+	Cursor *uint64
+	Limit  int
+}
+
+// AuditLogPage is a page of audit log entries, newest first.
+type AuditLogPage struct {
+	Entries []models.AuditLogEntry
+	// NextCursor continues the listing, or is nil on the last page
+	NextCursor *uint64
+}
+
+// ListEntries returns a page of an organization's audit log.
+func (s *AuditService) ListEntries(input ListAuditLogInput) (*AuditLogPage, error) {
+	if input.Action != nil {
+		if _, ok := auditActions[*input.Action]; !ok {
+			return nil, ErrInvalidAuditAction
+		}
+	}
+	if input.TargetType != nil {
+		switch *input.TargetType {
+		case models.AuditTargetTask, models.AuditTargetMember, models.AuditTargetOrganization:
+		default:
+			return nil, ErrInvalidAuditTargetType
+		}
+	}
+	if input.Since != nil && input.Until != nil && !input.Since.Before(*input.Until) {
+		return nil, ErrInvalidAuditTimeRange
+	}
+
+	// Fetch one extra entry to learn whether another page follows
+	entries, err := s.auditRepo.List(repository.AuditLogFilter{
+		OrganizationID: input.OrganizationID,
+		ActorID:        input.ActorID,
+		Action:         input.Action,
+		TargetType:     input.TargetType,
+		TargetID:       input.TargetID,
+		Since:          input.Since,
+		Until:          input.Until,
+		BeforeID:       input.Cursor,
+		Limit:          input.Limit + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	page := &AuditLogPage{Entries: entries}
+	if len(entries) > input.Limit {
+		page.Entries = entries[:input.Limit]
+		next := page.Entries[len(page.Entries)-1].ID
+		page.NextCursor = &next
+	}
+	return page, nil
+}
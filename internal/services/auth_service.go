@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/yukikurage/task-management-api/internal/constants"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/notifier"
 	"github.com/yukikurage/task-management-api/internal/repository"
 	"github.com/yukikurage/task-management-api/internal/utils"
 	"golang.org/x/crypto/bcrypt"
@@ -16,6 +18,9 @@ import (
 
 var (
 	ErrUsernameTaken        = errors.New("username already exists")
+	ErrUsernameReserved     = errors.New("username is reserved")
+	ErrIncorrectPassword    = errors.New("current password is incorrect")
+	ErrInvalidResetToken    = errors.New("invalid or expired password reset token")
 	ErrInvalidCredentials   = errors.New("invalid username or password")
 	ErrPasswordTooShort     = errors.New("password too short")
 	ErrUserNotFound         = errors.New("user not found")
@@ -27,13 +32,27 @@ var (
 
 // AuthService handles authentication related business logic.
 type AuthService struct {
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	resetRepo      repository.PasswordResetRepository
+	loginThrottle  *LoginThrottle
+	sessionService *SessionService
+	notifier       notifier.Notifier
 }
 
 // NewAuthService creates a new AuthService.
-func NewAuthService(userRepo repository.UserRepository) *AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	loginThrottle *LoginThrottle,
+	sessionService *SessionService,
+	accountNotifier notifier.Notifier,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		resetRepo:      resetRepo,
+		loginThrottle:  loginThrottle,
+		sessionService: sessionService,
+		notifier:       accountNotifier,
 	}
 }
 
@@ -49,8 +68,11 @@ func (s *AuthService) Signup(input SignupInput) (*models.User, error) {
 	if username == "" {
 		return nil, fmt.Errorf("username is required")
 	}
-	if len(input.Password) < constants.MinPasswordLength {
-		return nil, ErrPasswordTooShort
+	if isReservedUsername(username) {
+		return nil, ErrUsernameReserved
+	}
+	if err := ValidatePassword(input.Password, username); err != nil {
+		return nil, err
 	}
 
 	if _, err := s.userRepo.FindByUsername(username); err == nil {
@@ -69,61 +91,115 @@ func (s *AuthService) Signup(input SignupInput) (*models.User, error) {
 		PasswordHash: string(hashedPassword),
 	}
 
-	orgName := fmt.Sprintf("%sの組織", user.Username)
+	org, member, err := newPersonalOrganization(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.CreateWithPersonalOrganization(user, org, member); err != nil {
+		return nil, mapSignupError(err)
+	}
+
+	return user, nil
+}
+
+// isReservedUsername reports whether a username looks like an anonymized, deleted user.
+func isReservedUsername(username string) bool {
+	return strings.HasPrefix(strings.ToLower(username), constants.DeletedUsernamePrefix)
+}
+
+// newPersonalOrganization builds the personal organization and owner membership
+// every new user starts with.
+func newPersonalOrganization(username string) (*models.Organization, *models.OrganizationMember, error) {
 	inviteCode, err := utils.GenerateInviteCode()
 	if err != nil {
-		return nil, ErrFailedToCreateOrg
+		return nil, nil, ErrFailedToCreateOrg
 	}
 
 	org := &models.Organization{
-		Name:       orgName,
+		Name:       fmt.Sprintf("%sの組織", username),
 		InviteCode: inviteCode,
 	}
 
 	member := &models.OrganizationMember{
-		Role:           models.RoleOwner,
-		JoinedAt:       time.Now(),
+		Role:     models.RoleOwner,
+		JoinedAt: time.Now(),
 	}
 
-	if err := s.userRepo.CreateWithPersonalOrganization(user, org, member); err != nil {
-		switch {
-		case errors.Is(err, repository.ErrCreateUser):
-			return nil, ErrFailedToCreateUser
-		case errors.Is(err, repository.ErrCreateOrganization):
-			return nil, ErrFailedToCreateOrg
-		case errors.Is(err, repository.ErrCreateOrganizationMember):
-			return nil, ErrFailedToAddMember
-		default:
-			return nil, fmt.Errorf("failed to complete signup: %w", err)
-		}
-	}
+	return org, member, nil
+}
 
-	return user, nil
+// mapSignupError translates repository signup transaction failures to service errors.
+func mapSignupError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCreateUser):
+		return ErrFailedToCreateUser
+	case errors.Is(err, repository.ErrCreateOrganization):
+		return ErrFailedToCreateOrg
+	case errors.Is(err, repository.ErrCreateOrganizationMember):
+		return ErrFailedToAddMember
+	default:
+		return fmt.Errorf("failed to complete signup: %w", err)
+	}
 }
 
 // LoginInput holds the credentials for authentication.
 type LoginInput struct {
-	Username string
-	Password string
+	Username  string
+	Password  string
+	IPAddress string
 }
 
-// Login verifies credentials and returns the authenticated user.
+// Login verifies credentials and returns the authenticated user. Repeated
+// failures for the same username or IP address lock further attempts. The
+// failures are only cleared by CompleteLogin once any second factor passed.
 func (s *AuthService) Login(input LoginInput) (*models.User, error) {
+	if err := s.loginThrottle.Check(input.Username, input.IPAddress); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.FindByUsername(input.Username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrInvalidCredentials
+			return nil, s.rejectLogin(input)
 		}
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
-		return nil, ErrInvalidCredentials
+		return nil, s.rejectLogin(input)
 	}
 
 	return user, nil
 }
 
+// CompleteLogin clears the failed attempts of a user once every factor of
+// the login has been verified. Until then wrong second factors keep counting
+// towards the same lockout as wrong passwords.
+func (s *AuthService) CompleteLogin(user *models.User) error {
+	return s.loginThrottle.RecordSuccess(user.Username)
+}
+
+// CheckLoginLockout returns an *AccountLockedError while the user or the
+// address may not attempt to log in.
+func (s *AuthService) CheckLoginLockout(user *models.User, ipAddress string) error {
+	return s.loginThrottle.Check(user.Username, ipAddress)
+}
+
+// RecordFailedSecondFactor counts a wrong TOTP or recovery code like a failed
+// login so that fresh pending logins cannot be used to keep guessing codes.
+func (s *AuthService) RecordFailedSecondFactor(user *models.User, ipAddress string) error {
+	return s.loginThrottle.RecordFailure(user.Username, ipAddress)
+}
+
+// rejectLogin records a failed attempt and returns the error to report.
+func (s *AuthService) rejectLogin(input LoginInput) error {
+	if err := s.loginThrottle.RecordFailure(input.Username, input.IPAddress); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
 // GetUser retrieves a user by ID.
 func (s *AuthService) GetUser(id uint64) (*models.User, error) {
 	user, err := s.userRepo.FindByID(id)
@@ -136,3 +212,147 @@ func (s *AuthService) GetUser(id uint64) (*models.User, error) {
 
 	return user, nil
 }
+
+// VerifyPassword re-authenticates a signed-in user before a sensitive change.
+// Guessing the password from a hijacked session counts towards the login lockout.
+func (s *AuthService) VerifyPassword(user *models.User, password string) error {
+	if err := s.loginThrottle.Check(user.Username, ""); err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if err := s.loginThrottle.RecordFailure(user.Username, ""); err != nil {
+			return err
+		}
+		return ErrIncorrectPassword
+	}
+	return nil
+}
+
+// ChangePasswordInput holds the parameters to change a password.
+type ChangePasswordInput struct {
+	UserID          uint64
+	CurrentPassword string
+	NewPassword     string
+	// CurrentSessionID is kept signed in; every other session is revoked.
+	CurrentSessionID uint64
+}
+
+// ChangePassword replaces the password after verifying the current one and
+// signs out all other sessions.
+func (s *AuthService) ChangePassword(input ChangePasswordInput) error {
+	user, err := s.GetUser(input.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.VerifyPassword(user, input.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := s.setPassword(user, input.NewPassword); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionService.RevokeOtherSessions(user.ID, input.CurrentSessionID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RequestPasswordReset issues a reset token and sends it through the notifier.
+// Unknown usernames are ignored so that the response does not reveal which accounts exist.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, username string) error {
+	user, err := s.userRepo.FindByUsername(strings.TrimSpace(username))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	token, err := utils.GenerateSecureToken("", constants.PasswordResetTokenByteLength)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	resetToken := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(constants.PasswordResetTokenTTL),
+	}
+	if err := s.resetRepo.Create(resetToken); err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	if err := s.notifier.SendPasswordReset(ctx, notifier.PasswordResetNotification{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Token:     token,
+		ExpiresAt: resetToken.ExpiresAt,
+	}); err != nil {
+		return fmt.Errorf("failed to send reset notification: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token. The token can only be
+// used once, and every session of the user is revoked.
+func (s *AuthService) ResetPassword(token, newPassword string) error {
+	resetToken, err := s.resetRepo.FindByHash(utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to find reset token: %w", err)
+	}
+
+	now := time.Now()
+	if !resetToken.IsUsable(now) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.GetUser(resetToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidatePassword(newPassword, user.Username); err != nil {
+		return err
+	}
+
+	used, err := s.resetRepo.MarkUsed(resetToken.ID, now)
+	if err != nil {
+		return fmt.Errorf("failed to use reset token: %w", err)
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(user, newPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionService.RevokeAllSessions(user.ID); err != nil {
+		return err
+	}
+
+	// A successful reset also lifts any lockout on the username
+	return s.loginThrottle.RecordSuccess(user.Username)
+}
+
+// setPassword validates and stores a new password for the user.
+func (s *AuthService) setPassword(user *models.User, password string) error {
+	if err := ValidatePassword(password, user.Username); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return ErrFailedToHashPassword
+	}
+
+	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
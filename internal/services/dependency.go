@@ -0,0 +1,280 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTaskBlocker = errors.New("blocker must be another active task of the task's organization")
+	ErrDependencyCycle    = errors.New("the blocker already depends on this task")
+	ErrDependencyNotFound = errors.New("task is not blocked by this task")
+	ErrTaskBlocked        = errors.New("task cannot be completed while it has open blockers")
+	ErrTaskOrderCycle     = errors.New("task dependencies contain a cycle")
+)
+
+// TaskOrderCycleError is returned when open tasks wait on each other so that
+// they cannot be ordered. It matches ErrTaskOrderCycle with errors.Is.
+type TaskOrderCycleError struct {
+	// TaskIDs lists the tasks of the cycle, each blocked by the next one and
+	// the last one by the first
+	TaskIDs []uint64
+}
+
+func (e *TaskOrderCycleError) Error() string {
+	ids := make([]string, len(e.TaskIDs)+1)
+	for i, id := range e.TaskIDs {
+		ids[i] = strconv.FormatUint(id, 10)
+	}
+	ids[len(e.TaskIDs)] = ids[0]
+	return fmt.Sprintf("%s: %s", ErrTaskOrderCycle, strings.Join(ids, " -> "))
+}
+
+func (e *TaskOrderCycleError) Unwrap() error {
+	return ErrTaskOrderCycle
+}
+
+// ListDependencies returns the active tasks blocking a task and the ones it blocks.
+func (s *TaskService) ListDependencies(taskID uint64) ([]models.Task, []models.Task, error) {
+	blockers, err := s.taskRepo.ListBlockers(taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list blockers: %w", err)
+	}
+	dependents, err := s.taskRepo.ListDependents(taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list dependent tasks: %w", err)
+	}
+	return blockers, dependents, nil
+}
+
+// AddDependency marks a task as blocked by another task of its organization.
+// Dependencies that would make a task wait on itself are rejected.
+func (s *TaskService) AddDependency(taskID, actorID, blockerID uint64) error {
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskUpdate, actorID)
+	if err != nil {
+		return err
+	}
+
+	if blockerID == task.ID {
+		return ErrInvalidTaskBlocker
+	}
+	blocker, err := s.taskRepo.FindByID(blockerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidTaskBlocker
+		}
+		return fmt.Errorf("failed to find blocker: %w", err)
+	}
+	if blocker.OrganizationID != task.OrganizationID {
+		return ErrInvalidTaskBlocker
+	}
+
+	added, err := s.taskRepo.AddDependency(task.OrganizationID, task.ID, blocker.ID)
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+	if !added {
+		return ErrDependencyCycle
+	}
+
+	changes := auditChanges{}
+	changes.set("blocker_id", nil, blocker.ID)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskBlock, changes)
+
+	return nil
+}
+
+// RemoveDependency stops a task from being blocked by another task.
+func (s *TaskService) RemoveDependency(taskID, actorID, blockerID uint64) error {
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskUpdate, actorID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.taskRepo.RemoveDependency(task.ID, blockerID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	if !removed {
+		return ErrDependencyNotFound
+	}
+
+	changes := auditChanges{}
+	changes.set("blocker_id", blockerID, nil)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskUnblock, changes)
+
+	return nil
+}
+
+// BlockedTasks reports which of the tasks have at least one active blocker
+// that is not in a done state of its organization's workflow.
+func (s *TaskService) BlockedTasks(tasks []models.Task) (map[uint64]bool, error) {
+	blocked := make(map[uint64]bool)
+	if len(tasks) == 0 {
+		return blocked, nil
+	}
+
+	taskIDs := make([]uint64, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	statuses, err := s.taskRepo.ListBlockerStatuses(taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blockers: %w", err)
+	}
+
+	workflows := workflowCache{}
+	for _, task := range tasks {
+		if len(statuses[task.ID]) == 0 {
+			continue
+		}
+		workflow, err := workflows.load(s.orgRepo, task.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		blocked[task.ID] = hasOpenStatus(workflow, statuses[task.ID])
+	}
+	return blocked, nil
+}
+
+// TopologicalOrder returns the organization's open tasks ordered so that
+// every task comes after the open tasks blocking it. Tasks that could go
+// first at the same time keep the order of priority, then due date.
+func (s *TaskService) TopologicalOrder(orgID uint64) ([]models.Task, error) {
+	workflow, err := loadWorkflow(s.orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, _, err := s.taskRepo.List(repository.TaskFilter{
+		OrganizationIDs: []uint64{orgID},
+		ExcludeStatuses: workflow.DoneStatuses(),
+		Sort: []repository.TaskSort{
+			{Field: repository.TaskSortPriority},
+			{Field: repository.TaskSortDueDate},
+			{Field: repository.TaskSortCreatedAt},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open tasks: %w", err)
+	}
+
+	index := make(map[uint64]int, len(tasks))
+	taskIDs := make([]uint64, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+		taskIDs[i] = task.ID
+	}
+	dependencies, err := s.taskRepo.ListDependenciesAmong(taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+
+	// Kahn's algorithm, always taking the earliest ready task in list order
+	waitingOn := make([]int, len(tasks))
+	unblocks := make([][]int, len(tasks))
+	blockedBy := make([][]int, len(tasks))
+	for _, dependency := range dependencies {
+		task, blocker := index[dependency.TaskID], index[dependency.BlockerID]
+		waitingOn[task]++
+		unblocks[blocker] = append(unblocks[blocker], task)
+		blockedBy[task] = append(blockedBy[task], blocker)
+	}
+
+	var ready []int
+	for i, count := range waitingOn {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]models.Task, 0, len(tasks))
+	for len(ready) > 0 {
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, tasks[next])
+
+		for _, task := range unblocks[next] {
+			if waitingOn[task]--; waitingOn[task] == 0 {
+				at, _ := slices.BinarySearch(ready, task)
+				ready = slices.Insert(ready, at, task)
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, &TaskOrderCycleError{TaskIDs: findCycle(tasks, waitingOn, blockedBy)}
+	}
+	return ordered, nil
+}
+
+// findCycle follows the blockers of the tasks Kahn's algorithm left waiting
+// until one repeats. Every waiting task still has a waiting blocker, so the
+// walk always ends on a cycle.
+func findCycle(tasks []models.Task, waitingOn []int, blockedBy [][]int) []uint64 {
+	current := slices.IndexFunc(waitingOn, func(count int) bool { return count > 0 })
+	visited := make(map[int]int)
+	var path []int
+	for {
+		if at, ok := visited[current]; ok {
+			cycle := make([]uint64, 0, len(path)-at)
+			for _, i := range path[at:] {
+				cycle = append(cycle, tasks[i].ID)
+			}
+			return cycle
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+
+		for _, blocker := range blockedBy[current] {
+			if waitingOn[blocker] > 0 {
+				current = blocker
+				break
+			}
+		}
+	}
+}
+
+// ensureUnblocked checks, when the organization enforces dependencies, that
+// a task moving to a done state has no open blockers.
+func (s *TaskService) ensureUnblocked(task *models.Task, workflow *models.Workflow, status models.TaskStatus) error {
+	if state, ok := workflow.State(status); !ok || !state.Done {
+		return nil
+	}
+
+	settings, err := loadOrganizationSettings(s.orgRepo, task.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !settings.EnforceDependencies {
+		return nil
+	}
+
+	statuses, err := s.taskRepo.ListBlockerStatuses([]uint64{task.ID})
+	if err != nil {
+		return fmt.Errorf("failed to list blockers: %w", err)
+	}
+	if hasOpenStatus(workflow, statuses[task.ID]) {
+		return ErrTaskBlocked
+	}
+	return nil
+}
+
+// hasOpenStatus reports whether any of the statuses is not a done state of
+// the workflow
+func hasOpenStatus(workflow *models.Workflow, statuses []models.TaskStatus) bool {
+	for _, status := range statuses {
+		if state, ok := workflow.State(status); !ok || !state.Done {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInviteeNotFound          = errors.New("no user with this username")
+	ErrInvitationAlreadyPending = errors.New("this user already has a pending invitation to the organization")
+	ErrInvitationAnswered       = errors.New("invitation is no longer pending")
+	ErrInvitationExpired        = errors.New("invitation has expired")
+)
+
+// InvitationService handles invitations of specific users to organizations.
+type InvitationService struct {
+	invitationRepo repository.InvitationRepository
+	orgRepo        repository.OrganizationRepository
+	userRepo       repository.UserRepository
+	auditService   *AuditService
+	meta           RequestMeta
+}
+
+// NewInvitationService creates a new InvitationService.
+func NewInvitationService(
+	invitationRepo repository.InvitationRepository,
+	orgRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	auditService *AuditService,
+) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		auditService:   auditService,
+	}
+}
+
+// WithRequestMeta returns a copy of the service that records the request in
+// the audit log entries of its changes.
+func (s *InvitationService) WithRequestMeta(meta RequestMeta) *InvitationService {
+	copied := *s
+	copied.meta = meta
+	return &copied
+}
+
+// CreateInvitationInput represents parameters to invite a user.
+type CreateInvitationInput struct {
+	OrganizationID uint64
+	InviterID      uint64
+	Username       string
+	Role           models.OrganizationRole
+}
+
+// CreateInvitation invites an existing user by username. Admins can only
+// invite members and guests.
+func (s *InvitationService) CreateInvitation(input CreateInvitationInput) (*models.OrganizationInvitation, error) {
+	role := input.Role
+	if role == "" {
+		role = models.RoleMember
+	}
+	if !role.IsValid() || role == models.RoleOwner {
+		return nil, ErrInvalidInviteRole
+	}
+
+	inviter, err := s.orgRepo.FindMember(input.OrganizationID, input.InviterID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to find organization member: %w", err)
+	}
+	settings, err := loadOrganizationSettings(s.orgRepo, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.CanInvite(inviter.Role, role, settings.MembersCanInvite) {
+		return nil, ErrInsufficientRole
+	}
+
+	invitee, err := s.userRepo.FindByUsername(strings.TrimSpace(input.Username))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteeNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if _, err := s.orgRepo.FindMember(input.OrganizationID, invitee.ID); err == nil {
+		return nil, ErrAlreadyOrganizationMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to verify membership: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := s.invitationRepo.FindPending(input.OrganizationID, invitee.ID, now); err == nil {
+		return nil, ErrInvitationAlreadyPending
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check pending invitations: %w", err)
+	}
+
+	invitation := &models.OrganizationInvitation{
+		OrganizationID: input.OrganizationID,
+		InviteeID:      invitee.ID,
+		InviterID:      input.InviterID,
+		Role:           role,
+		Status:         models.InvitationStatusPending,
+		ExpiresAt:      now.Add(constants.InvitationTTL),
+		Invitee:        *invitee,
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// ListOrganizationInvitations returns the invitations an organization has sent.
+func (s *InvitationService) ListOrganizationInvitations(orgID uint64) ([]models.OrganizationInvitation, error) {
+	invitations, err := s.invitationRepo.ListByOrganizationID(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// CancelInvitation withdraws a pending invitation of the organization.
+func (s *InvitationService) CancelInvitation(orgID, invitationID uint64) error {
+	invitation, err := s.findInvitation(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.OrganizationID != orgID {
+		return ErrInvitationNotFound
+	}
+
+	return s.respond(invitation, models.InvitationStatusCanceled)
+}
+
+// ListInbox returns the invitations a user received. A non-empty status
+// filters by the effective status.
+func (s *InvitationService) ListInbox(userID uint64, status models.InvitationStatus) ([]models.OrganizationInvitation, error) {
+	invitations, err := s.invitationRepo.ListByInviteeID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	if status == "" {
+		return invitations, nil
+	}
+
+	now := time.Now()
+	filtered := make([]models.OrganizationInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.EffectiveStatus(now) == status {
+			filtered = append(filtered, invitation)
+		}
+	}
+	return filtered, nil
+}
+
+// AcceptInvitation adds the invitee to the organization with the invited role.
+func (s *InvitationService) AcceptInvitation(userID, invitationID uint64) (*models.OrganizationInvitation, error) {
+	invitation, err := s.findInboxInvitation(userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := invitationStatusError(invitation.EffectiveStatus(now)); err != nil {
+		return nil, err
+	}
+	if invitation.Organization.IsArchived() {
+		return nil, ErrOrganizationArchived
+	}
+
+	if _, err := s.orgRepo.FindMember(invitation.OrganizationID, userID); err == nil {
+		return nil, ErrAlreadyOrganizationMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to verify membership: %w", err)
+	}
+	maxMembers, err := loadMemberLimit(s.orgRepo, invitation.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted, err := s.invitationRepo.Accept(invitation.ID, &models.OrganizationMember{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         userID,
+		Role:           invitation.Role,
+		JoinedAt:       now,
+	}, maxMembers)
+	if err != nil {
+		if errors.Is(err, repository.ErrMemberLimitReached) {
+			return nil, ErrMemberQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	if !accepted {
+		return nil, ErrInvitationAnswered
+	}
+
+	s.auditService.record(s.meta, auditEvent{
+		OrganizationID: invitation.OrganizationID,
+		ActorID:        userID,
+		Action:         models.AuditActionMemberJoin,
+		TargetType:     models.AuditTargetMember,
+		TargetID:       userID,
+		Changes: auditChanges{
+			"role":          {After: invitation.Role},
+			"invitation_id": {After: invitation.ID},
+		},
+	})
+
+	invitation.Status = models.InvitationStatusAccepted
+	invitation.RespondedAt = &now
+	return invitation, nil
+}
+
+// DeclineInvitation turns down an invitation.
+func (s *InvitationService) DeclineInvitation(userID, invitationID uint64) (*models.OrganizationInvitation, error) {
+	invitation, err := s.findInboxInvitation(userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.respond(invitation, models.InvitationStatusDeclined); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// respond moves a pending, unexpired invitation to a final status.
+func (s *InvitationService) respond(invitation *models.OrganizationInvitation, status models.InvitationStatus) error {
+	now := time.Now()
+	if err := invitationStatusError(invitation.EffectiveStatus(now)); err != nil {
+		return err
+	}
+
+	ok, err := s.invitationRepo.Respond(invitation.ID, status, now)
+	if err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+	if !ok {
+		return ErrInvitationAnswered
+	}
+
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	return nil
+}
+
+// findInboxInvitation finds an invitation addressed to the user. Invitations
+// of other users are reported as not found.
+func (s *InvitationService) findInboxInvitation(userID, invitationID uint64) (*models.OrganizationInvitation, error) {
+	invitation, err := s.findInvitation(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeID != userID {
+		return nil, ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) findInvitation(invitationID uint64) (*models.OrganizationInvitation, error) {
+	invitation, err := s.invitationRepo.FindByID(invitationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	return invitation, nil
+}
+
+// invitationStatusError maps a status that can no longer be answered to its error.
+func invitationStatusError(status models.InvitationStatus) error {
+	switch status {
+	case models.InvitationStatusPending:
+		return nil
+	case models.InvitationStatusExpired:
+		return ErrInvitationExpired
+	default:
+		return ErrInvitationAnswered
+	}
+}
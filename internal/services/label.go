@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrInvalidLabelName   = fmt.Errorf("label name must be 1-%d characters", constants.LabelNameMaxLength)
+	ErrInvalidLabelColor  = errors.New("label color must be a hex color such as #1f883d")
+	ErrLabelNameTaken     = errors.New("the organization already has a label with this name")
+	ErrLabelLimitReached  = fmt.Errorf("an organization can have at most %d labels", constants.MaxLabelsPerOrganization)
+	ErrInvalidTaskLabel   = errors.New("one or more labels do not exist in the task's organization")
+	ErrNoLabelIDsProvided = errors.New("at least one label ID is required")
+)
+
+// labelColorPattern matches hex RGB colors such as #1f883d
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// LabelInput holds the editable fields of a label
+type LabelInput struct {
+	Name  string
+	Color string
+}
+
+// ListLabels returns the labels of an organization ordered by name.
+func (s *OrganizationService) ListLabels(orgID uint64) ([]models.Label, error) {
+	labels, err := s.orgRepo.ListLabels(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	return labels, nil
+}
+
+// CreateLabel adds a label to an organization.
+func (s *OrganizationService) CreateLabel(orgID uint64, input LabelInput) (*models.Label, error) {
+	name, color, err := normalizeLabelInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	label := &models.Label{
+		OrganizationID: orgID,
+		Name:           name,
+		Color:          color,
+	}
+	if err := s.orgRepo.CreateLabel(label, constants.MaxLabelsPerOrganization); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrLabelLimitReached):
+			return nil, ErrLabelLimitReached
+		case errors.Is(err, repository.ErrLabelNameTaken):
+			return nil, ErrLabelNameTaken
+		}
+		return nil, fmt.Errorf("failed to create label: %w", err)
+	}
+	return label, nil
+}
+
+// UpdateLabel renames or recolors a label of an organization.
+func (s *OrganizationService) UpdateLabel(orgID, labelID uint64, input LabelInput) (*models.Label, error) {
+	label, err := s.findLabel(orgID, labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, color, err := normalizeLabelInput(input)
+	if err != nil {
+		return nil, err
+	}
+	label.Name = name
+	label.Color = color
+	if err := s.orgRepo.UpdateLabel(label); err != nil {
+		if errors.Is(err, repository.ErrLabelNameTaken) {
+			return nil, ErrLabelNameTaken
+		}
+		return nil, fmt.Errorf("failed to update label: %w", err)
+	}
+	return label, nil
+}
+
+// DeleteLabel deletes a label of an organization and removes it from every
+// task, including tasks in the trash.
+func (s *OrganizationService) DeleteLabel(orgID, labelID uint64) error {
+	label, err := s.findLabel(orgID, labelID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.orgRepo.DeleteLabel(label.ID); err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+	return nil
+}
+
+// findLabel looks up a label of an organization, mapping a missing row to ErrLabelNotFound.
+func (s *OrganizationService) findLabel(orgID, labelID uint64) (*models.Label, error) {
+	label, err := s.orgRepo.FindLabel(orgID, labelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLabelNotFound
+		}
+		return nil, fmt.Errorf("failed to find label: %w", err)
+	}
+	return label, nil
+}
+
+// normalizeLabelInput trims the name and lower-cases the color after
+// validating both.
+func normalizeLabelInput(input LabelInput) (string, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > constants.LabelNameMaxLength {
+		return "", "", ErrInvalidLabelName
+	}
+	if !labelColorPattern.MatchString(input.Color) {
+		return "", "", ErrInvalidLabelColor
+	}
+	return name, strings.ToLower(input.Color), nil
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOIDCNotConfigured       = errors.New("single sign-on is not configured")
+	ErrOIDCProviderUnavailable = errors.New("identity provider is unavailable")
+	ErrOIDCInvalidState        = errors.New("invalid or expired login state")
+	ErrOIDCExchangeFailed      = errors.New("failed to exchange authorization code")
+	ErrOIDCInvalidIDToken      = errors.New("invalid ID token")
+	ErrOIDCIdentityLinked      = errors.New("external identity is already linked to another user")
+)
+
+var usernameDisallowedChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// OIDCConfig holds the OpenID Connect client settings.
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// OIDCService implements the OpenID Connect authorization code flow with PKCE.
+type OIDCService struct {
+	config   OIDCConfig
+	userRepo repository.UserRepository
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCService creates a new OIDCService. Provider discovery happens lazily
+// on first use so the server can start while the identity provider is down.
+func NewOIDCService(config OIDCConfig, userRepo repository.UserRepository) *OIDCService {
+	return &OIDCService{
+		config:   config,
+		userRepo: userRepo,
+	}
+}
+
+// Enabled reports whether single sign-on is configured.
+func (s *OIDCService) Enabled() bool {
+	return s != nil && s.config.IssuerURL != "" && s.config.ClientID != ""
+}
+
+// OIDCAuthRequest holds the redirect URL and the values that must be kept in
+// the user's session until the callback.
+type OIDCAuthRequest struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// BeginLogin prepares an authorization request to the identity provider.
+func (s *OIDCService) BeginLogin(ctx context.Context) (*OIDCAuthRequest, error) {
+	if !s.Enabled() {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GenerateSecureToken("", constants.OIDCRandomByteLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+	nonce, err := utils.GenerateSecureToken("", constants.OIDCRandomByteLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	url := s.oauth2Config(provider).AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(verifier),
+	)
+
+	return &OIDCAuthRequest{
+		URL:          url,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+	}, nil
+}
+
+// OIDCCallbackInput carries the callback parameters and the values stored in
+// the session by BeginLogin.
+type OIDCCallbackInput struct {
+	Code          string
+	State         string
+	ExpectedState string
+	Nonce         string
+	CodeVerifier  string
+	// CurrentUserID links the identity to an already signed-in user when set.
+	CurrentUserID *uint64
+}
+
+// CompleteLogin validates the callback, verifies the ID token and returns the
+// linked user, provisioning a new one on first login.
+func (s *OIDCService) CompleteLogin(ctx context.Context, input OIDCCallbackInput) (*models.User, error) {
+	if !s.Enabled() {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	if input.ExpectedState == "" || input.Nonce == "" || input.CodeVerifier == "" ||
+		subtle.ConstantTimeCompare([]byte(input.State), []byte(input.ExpectedState)) != 1 {
+		return nil, ErrOIDCInvalidState
+	}
+
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.oauth2Config(provider).Exchange(ctx, input.Code, oauth2.VerifierOption(input.CodeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCExchangeFailed, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: missing id_token", ErrOIDCInvalidIDToken)
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: s.config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCInvalidIDToken, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(input.Nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrOIDCInvalidIDToken)
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCInvalidIDToken, err)
+	}
+
+	user, err := s.userRepo.FindByIdentity(idToken.Issuer, idToken.Subject)
+	if err == nil {
+		if input.CurrentUserID != nil && *input.CurrentUserID != user.ID {
+			return nil, ErrOIDCIdentityLinked
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find linked user: %w", err)
+	}
+
+	identity := &models.UserIdentity{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Email:   claims.Email,
+	}
+
+	if input.CurrentUserID != nil {
+		return s.linkIdentity(*input.CurrentUserID, identity)
+	}
+
+	return s.provisionUser(identity, claims.PreferredUsername)
+}
+
+// linkIdentity attaches an external identity to an existing user.
+func (s *OIDCService) linkIdentity(userID uint64, identity *models.UserIdentity) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	identity.UserID = user.ID
+	if err := s.userRepo.CreateIdentity(identity); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return user, nil
+}
+
+// provisionUser creates a new user with the same personal organization
+// bootstrap as password signup. SSO users have no password.
+func (s *OIDCService) provisionUser(identity *models.UserIdentity, preferredUsername string) (*models.User, error) {
+	username, err := s.availableUsername(preferredUsername, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: username,
+	}
+
+	org, member, err := newPersonalOrganization(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.CreateWithIdentity(user, org, member, identity); err != nil {
+		return nil, mapSignupError(err)
+	}
+
+	return user, nil
+}
+
+// availableUsername derives a unique username from the identity provider's claims.
+func (s *OIDCService) availableUsername(preferredUsername, email string) (string, error) {
+	base := preferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(email, "@")
+	}
+	base = usernameDisallowedChars.ReplaceAllString(base, "")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if len(base) < 3 || isReservedUsername(base) {
+		base = "user" + base
+	}
+
+	candidate := base
+	for attempt := 0; attempt < constants.OIDCUsernameMaxAttempts; attempt++ {
+		if _, err := s.userRepo.FindByUsername(candidate); errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		} else if err != nil {
+			return "", fmt.Errorf("failed to check username: %w", err)
+		}
+
+		suffix, err := utils.GenerateSecureToken("", 3)
+		if err != nil {
+			return "", ErrFailedToCreateUser
+		}
+		candidate = base + "-" + strings.ToLower(suffix)
+	}
+
+	return "", ErrUsernameTaken
+}
+
+func (s *OIDCService) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.provider != nil {
+		return s.provider, nil
+	}
+
+	discoveryCtx, cancel := context.WithTimeout(ctx, constants.OIDCProviderTimeout)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(discoveryCtx, s.config.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+
+	s.provider = provider
+	return provider, nil
+}
+
+func (s *OIDCService) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		RedirectURL:  s.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/yukikurage/task-management-api/internal/constants"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
 	"github.com/yukikurage/task-management-api/internal/repository"
 	"github.com/yukikurage/task-management-api/internal/utils"
 	"gorm.io/gorm"
@@ -14,26 +16,57 @@ import (
 
 var (
 	ErrOrganizationNotFound       = errors.New("organization not found")
+	ErrOrganizationArchived       = errors.New("organization is archived and read-only")
 	ErrInvalidOrganizationName    = errors.New("organization name cannot be empty")
 	ErrInviteCodeGenerationFailed = errors.New("failed to generate invite code")
 	ErrInvalidInviteCode          = errors.New("invalid invite code")
 	ErrAlreadyOrganizationMember  = errors.New("user is already a member of this organization")
 	ErrCannotRemoveYourself       = errors.New("cannot remove yourself from the organization")
 	ErrOrganizationMemberNotFound = errors.New("organization member not found")
+	ErrInvalidOrganizationRole    = errors.New("invalid organization role")
+	ErrInsufficientRole           = errors.New("your organization role does not allow this action")
+	ErrLastOwner                  = errors.New("an organization must keep at least one owner")
+	ErrCannotTransferToYourself   = errors.New("cannot transfer ownership to yourself")
+	ErrInvalidPolicyRule          = errors.New("invalid policy rule")
+	ErrInvalidInviteName          = errors.New("invite name cannot be empty")
+	ErrInvalidInviteRole          = errors.New("invites can only grant the admin, member or guest role")
+	ErrInvalidInviteLifetime      = fmt.Errorf("invite lifetime must be between 1 and %d hours", constants.InviteMaxLifetimeHours)
+	ErrInvalidInviteMaxUses       = fmt.Errorf("invite use limit must be between 1 and %d", constants.InviteMaxUses)
+	ErrInviteNotFound             = errors.New("invite not found")
+	ErrInviteExpired              = errors.New("invite has expired")
+	ErrInviteRevoked              = errors.New("invite has been revoked")
+	ErrInviteUsedUp               = errors.New("invite has reached its use limit")
 )
 
 // OrganizationService provides business logic for organization operations.
 type OrganizationService struct {
-	orgRepo repository.OrganizationRepository
+	orgRepo      repository.OrganizationRepository
+	inviteRepo   repository.InviteRepository
+	auditService *AuditService
+	meta         RequestMeta
 }
 
 // NewOrganizationService creates a new OrganizationService.
-func NewOrganizationService(orgRepo repository.OrganizationRepository) *OrganizationService {
+func NewOrganizationService(
+	orgRepo repository.OrganizationRepository,
+	inviteRepo repository.InviteRepository,
+	auditService *AuditService,
+) *OrganizationService {
 	return &OrganizationService{
-		orgRepo: orgRepo,
+		orgRepo:      orgRepo,
+		inviteRepo:   inviteRepo,
+		auditService: auditService,
 	}
 }
 
+// WithRequestMeta returns a copy of the service that records the request in
+// the audit log entries of its changes.
+func (s *OrganizationService) WithRequestMeta(meta RequestMeta) *OrganizationService {
+	copied := *s
+	copied.meta = meta
+	return &copied
+}
+
 // CreateOrganizationInput represents parameters to create a new organization.
 type CreateOrganizationInput struct {
 	Name    string
@@ -67,7 +100,7 @@ func (s *OrganizationService) CreateOrganization(input CreateOrganizationInput)
 		JoinedAt:       time.Now(),
 	}
 
-	if err := s.orgRepo.AddMember(member); err != nil {
+	if err := s.orgRepo.AddMember(member, nil); err != nil {
 		return nil, fmt.Errorf("failed to add owner to organization: %w", err)
 	}
 
@@ -101,8 +134,64 @@ func (s *OrganizationService) GetOrganizationWithMembers(orgID uint64) (*models.
 	return org, members, nil
 }
 
+// ListMembersInput represents parameters to list organization members.
+type ListMembersInput struct {
+	OrganizationID uint64
+	Username       string
+	Role           *models.OrganizationRole
+	NewestFirst    bool
+	Pagination     utils.PaginationParams
+}
+
+// MemberList is a page of organization members.
+type MemberList struct {
+	Members []models.OrganizationMember
+	// OpenAssignedTasks maps user IDs to the number of tasks they are assigned
+	// to in the organization that are not in a done state
+	OpenAssignedTasks map[uint64]int64
+	Total             int64
+}
+
+// ListMembers returns a page of organization members, oldest first unless
+// NewestFirst is set.
+func (s *OrganizationService) ListMembers(input ListMembersInput) (*MemberList, error) {
+	if input.Role != nil && !input.Role.IsValid() {
+		return nil, ErrInvalidOrganizationRole
+	}
+
+	members, total, err := s.orgRepo.SearchMembers(repository.MemberFilter{
+		OrganizationID: input.OrganizationID,
+		Username:       input.Username,
+		Role:           input.Role,
+		NewestFirst:    input.NewestFirst,
+		Pagination:     input.Pagination,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	userIDs := make([]uint64, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	workflow, err := loadWorkflow(s.orgRepo, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	openCounts, err := s.orgRepo.CountOpenAssignedTasks(input.OrganizationID, userIDs, workflow.DoneStatuses())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count assigned tasks: %w", err)
+	}
+
+	return &MemberList{
+		Members:           members,
+		OpenAssignedTasks: openCounts,
+		Total:             total,
+	}, nil
+}
+
 // UpdateOrganizationName updates an organization's name.
-func (s *OrganizationService) UpdateOrganizationName(orgID uint64, name string) (*models.Organization, error) {
+func (s *OrganizationService) UpdateOrganizationName(orgID, actorID uint64, name string) (*models.Organization, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, ErrInvalidOrganizationName
 	}
@@ -115,33 +204,92 @@ func (s *OrganizationService) UpdateOrganizationName(orgID uint64, name string)
 		return nil, fmt.Errorf("failed to find organization: %w", err)
 	}
 
+	previous := org.Name
 	org.Name = name
 	if err := s.orgRepo.Update(org); err != nil {
 		return nil, fmt.Errorf("failed to update organization: %w", err)
 	}
 
+	changes := auditChanges{}
+	changes.set("name", previous, org.Name)
+	s.recordOrganizationEvent(orgID, actorID, models.AuditActionOrganizationRename, changes)
+
 	return org, nil
 }
 
 // DeleteOrganization removes an organization.
-func (s *OrganizationService) DeleteOrganization(orgID uint64) error {
-	// Ensure organization exists
-	if _, err := s.orgRepo.FindByID(orgID); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrOrganizationNotFound
-		}
-		return fmt.Errorf("failed to find organization: %w", err)
+func (s *OrganizationService) DeleteOrganization(orgID, actorID uint64) error {
+	org, err := s.findOrganization(orgID)
+	if err != nil {
+		return err
 	}
 
 	if err := s.orgRepo.Delete(orgID); err != nil {
 		return fmt.Errorf("failed to delete organization: %w", err)
 	}
 
+	changes := auditChanges{}
+	changes.set("name", org.Name, nil)
+	s.recordOrganizationEvent(orgID, actorID, models.AuditActionOrganizationDelete, changes)
+
 	return nil
 }
 
-// JoinOrganizationByInvite adds a user to an organization via invite code.
+// ArchiveOrganization makes an organization read-only. Archiving an archived
+// organization keeps its original archive time.
+func (s *OrganizationService) ArchiveOrganization(orgID, actorID uint64) (*models.Organization, error) {
+	org, err := s.findOrganization(orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org.IsArchived() {
+		return org, nil
+	}
+
+	now := time.Now()
+	if err := s.orgRepo.SetArchivedAt(orgID, &now); err != nil {
+		return nil, fmt.Errorf("failed to archive organization: %w", err)
+	}
+
+	org.ArchivedAt = &now
+	changes := auditChanges{}
+	changes.set("archived_at", nil, auditTime(&now))
+	s.recordOrganizationEvent(orgID, actorID, models.AuditActionOrganizationArchive, changes)
+	return org, nil
+}
+
+// RestoreOrganization makes an archived organization writable again.
+func (s *OrganizationService) RestoreOrganization(orgID, actorID uint64) (*models.Organization, error) {
+	org, err := s.findOrganization(orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !org.IsArchived() {
+		return org, nil
+	}
+
+	if err := s.orgRepo.SetArchivedAt(orgID, nil); err != nil {
+		return nil, fmt.Errorf("failed to restore organization: %w", err)
+	}
+
+	changes := auditChanges{}
+	changes.set("archived_at", auditTime(org.ArchivedAt), nil)
+	s.recordOrganizationEvent(orgID, actorID, models.AuditActionOrganizationRestore, changes)
+	org.ArchivedAt = nil
+	return org, nil
+}
+
+// JoinOrganizationByInvite adds a user to an organization via an invite link
+// code or the organization's permanent invite code.
 func (s *OrganizationService) JoinOrganizationByInvite(userID uint64, inviteCode string) (*models.Organization, error) {
+	invite, err := s.inviteRepo.FindByCode(inviteCode)
+	if err == nil {
+		return s.joinWithInviteLink(userID, invite)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find invite: %w", err)
+	}
+
 	org, err := s.orgRepo.FindByInviteCode(inviteCode)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -150,10 +298,15 @@ func (s *OrganizationService) JoinOrganizationByInvite(userID uint64, inviteCode
 		return nil, fmt.Errorf("failed to find organization by invite code: %w", err)
 	}
 
-	if _, err := s.orgRepo.FindMember(org.ID, userID); err == nil {
-		return nil, ErrAlreadyOrganizationMember
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("failed to verify membership: %w", err)
+	if org.IsArchived() {
+		return nil, ErrOrganizationArchived
+	}
+	if err := s.ensureNotMember(org.ID, userID); err != nil {
+		return nil, err
+	}
+	maxMembers, err := loadMemberLimit(s.orgRepo, org.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	member := &models.OrganizationMember{
@@ -163,15 +316,202 @@ func (s *OrganizationService) JoinOrganizationByInvite(userID uint64, inviteCode
 		JoinedAt:       time.Now(),
 	}
 
-	if err := s.orgRepo.AddMember(member); err != nil {
+	if err := s.orgRepo.AddMember(member, maxMembers); err != nil {
+		if errors.Is(err, repository.ErrMemberLimitReached) {
+			return nil, ErrMemberQuotaExceeded
+		}
 		return nil, fmt.Errorf("failed to add member to organization: %w", err)
 	}
 
+	s.recordMemberEvent(org.ID, userID, userID, models.AuditActionMemberJoin, auditChanges{
+		"role": {After: member.Role},
+	})
+	return org, nil
+}
+
+// joinWithInviteLink adds a user with the invite's role and records the use.
+func (s *OrganizationService) joinWithInviteLink(userID uint64, invite *models.OrganizationInvite) (*models.Organization, error) {
+	now := time.Now()
+	if err := inviteStatusError(invite.Status(now)); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.FindByID(invite.OrganizationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInviteCode
+		}
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	if org.IsArchived() {
+		return nil, ErrOrganizationArchived
+	}
+	if err := s.ensureNotMember(org.ID, userID); err != nil {
+		return nil, err
+	}
+	maxMembers, err := loadMemberLimit(s.orgRepo, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	redeemed, err := s.inviteRepo.Redeem(invite, &models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         userID,
+		Role:           invite.Role,
+		JoinedAt:       now,
+	}, maxMembers)
+	if err != nil {
+		if errors.Is(err, repository.ErrMemberLimitReached) {
+			return nil, ErrMemberQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to join organization: %w", err)
+	}
+	if !redeemed {
+		// Another join used the last slot, or the invite was revoked meanwhile
+		return nil, ErrInviteUsedUp
+	}
+
+	s.recordMemberEvent(org.ID, userID, userID, models.AuditActionMemberJoin, auditChanges{
+		"role":      {After: invite.Role},
+		"invite_id": {After: invite.ID},
+	})
 	return org, nil
 }
 
+// ensureNotMember returns ErrAlreadyOrganizationMember if the user already belongs to the organization.
+func (s *OrganizationService) ensureNotMember(orgID, userID uint64) error {
+	if _, err := s.orgRepo.FindMember(orgID, userID); err == nil {
+		return ErrAlreadyOrganizationMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to verify membership: %w", err)
+	}
+	return nil
+}
+
+// inviteStatusError maps an unusable invite status to its error.
+func inviteStatusError(status models.InviteStatus) error {
+	switch status {
+	case models.InviteStatusExpired:
+		return ErrInviteExpired
+	case models.InviteStatusRevoked:
+		return ErrInviteRevoked
+	case models.InviteStatusUsedUp:
+		return ErrInviteUsedUp
+	default:
+		return nil
+	}
+}
+
+// CreateInviteInput represents parameters to create an invite link.
+type CreateInviteInput struct {
+	OrganizationID uint64
+	ActorID        uint64
+	Name           string
+	Role           models.OrganizationRole
+	ExpiresInHours *int
+	MaxUses        *int
+}
+
+// CreateInvite creates a named invite link. Admins can only invite members and guests.
+func (s *OrganizationService) CreateInvite(input CreateInviteInput) (*models.OrganizationInvite, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrInvalidInviteName
+	}
+
+	role := input.Role
+	if role == "" {
+		role = models.RoleMember
+	}
+	if !role.IsValid() || role == models.RoleOwner {
+		return nil, ErrInvalidInviteRole
+	}
+
+	actor, err := s.findMember(input.OrganizationID, input.ActorID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := loadOrganizationSettings(s.orgRepo, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.CanInvite(actor.Role, role, settings.MembersCanInvite) {
+		return nil, ErrInsufficientRole
+	}
+
+	var expiresAt *time.Time
+	if input.ExpiresInHours != nil {
+		hours := *input.ExpiresInHours
+		if hours < 1 || hours > constants.InviteMaxLifetimeHours {
+			return nil, ErrInvalidInviteLifetime
+		}
+		t := time.Now().Add(time.Duration(hours) * time.Hour)
+		expiresAt = &t
+	}
+
+	if input.MaxUses != nil && (*input.MaxUses < 1 || *input.MaxUses > constants.InviteMaxUses) {
+		return nil, ErrInvalidInviteMaxUses
+	}
+
+	code, err := utils.GenerateSecureToken(constants.InviteCodePrefix, constants.InviteCodeByteLength)
+	if err != nil {
+		return nil, ErrInviteCodeGenerationFailed
+	}
+
+	invite := &models.OrganizationInvite{
+		OrganizationID: input.OrganizationID,
+		Name:           name,
+		Code:           code,
+		Role:           role,
+		MaxUses:        input.MaxUses,
+		ExpiresAt:      expiresAt,
+		CreatedByID:    input.ActorID,
+	}
+
+	if err := s.inviteRepo.Create(invite); err != nil {
+		return nil, fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	return invite, nil
+}
+
+// ListInvites returns all invite links of an organization, including used up and revoked ones.
+func (s *OrganizationService) ListInvites(orgID uint64) ([]models.OrganizationInvite, error) {
+	invites, err := s.inviteRepo.ListByOrganizationID(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	return invites, nil
+}
+
+// GetInvite returns an invite link with the users who joined through it.
+func (s *OrganizationService) GetInvite(orgID, inviteID uint64) (*models.OrganizationInvite, error) {
+	invite, err := s.inviteRepo.FindByIDForOrganization(inviteID, orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("failed to find invite: %w", err)
+	}
+	return invite, nil
+}
+
+// RevokeInvite stops an invite link from being used. Revoking twice is a no-op.
+func (s *OrganizationService) RevokeInvite(orgID, inviteID uint64) error {
+	invite, err := s.GetInvite(orgID, inviteID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.inviteRepo.Revoke(invite.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+	return nil
+}
+
 // RegenerateInviteCode generates a new invite code for the organization.
-func (s *OrganizationService) RegenerateInviteCode(orgID uint64) (*models.Organization, error) {
+func (s *OrganizationService) RegenerateInviteCode(orgID, actorID uint64) (*models.Organization, error) {
 	org, err := s.orgRepo.FindByID(orgID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -190,25 +530,251 @@ func (s *OrganizationService) RegenerateInviteCode(orgID uint64) (*models.Organi
 		return nil, fmt.Errorf("failed to update invite code: %w", err)
 	}
 
+	// The codes themselves grant access, so they stay out of the log
+	s.recordOrganizationEvent(orgID, actorID, models.AuditActionOrganizationRegenerateCode, nil)
+
 	return org, nil
 }
 
-// RemoveMember removes a member from the organization.
+// RemoveMember removes a member from the organization. Owners can remove
+// anyone; admins can only remove members and guests.
 func (s *OrganizationService) RemoveMember(orgID, actorID, targetID uint64) error {
 	if targetID == actorID {
 		return ErrCannotRemoveYourself
 	}
 
-	if _, err := s.orgRepo.FindMember(orgID, targetID); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrOrganizationMemberNotFound
+	actor, err := s.findMember(orgID, actorID)
+	if err != nil {
+		return err
+	}
+	target, err := s.findMember(orgID, targetID)
+	if err != nil {
+		return err
+	}
+
+	if !policy.Default().Allows(policy.Actor{UserID: actorID, Role: actor.Role}, policy.ActionRemoveMembers, policy.Resource{}) ||
+		!policy.CanManageRole(actor.Role, target.Role) {
+		return ErrInsufficientRole
+	}
+
+	if err := s.removeMember(orgID, target); err != nil {
+		return err
+	}
+
+	s.recordMemberEvent(orgID, actorID, targetID, models.AuditActionMemberRemove, auditChanges{
+		"role": {Before: target.Role},
+	})
+
+	return nil
+}
+
+// ChangeMemberRole sets the role of an organization member. Only owners can
+// change roles, and the last owner cannot be demoted.
+func (s *OrganizationService) ChangeMemberRole(orgID, actorID, targetID uint64, role models.OrganizationRole) (*models.OrganizationMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidOrganizationRole
+	}
+
+	actor, err := s.findMember(orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Default().Allows(policy.Actor{UserID: actorID, Role: actor.Role}, policy.ActionChangeRoles, policy.Resource{}) {
+		return nil, ErrInsufficientRole
+	}
+
+	target, err := s.findMember(orgID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Role == models.RoleOwner && role != models.RoleOwner {
+		demoted, err := s.orgRepo.DemoteOwner(orgID, targetID, role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update member role: %w", err)
 		}
-		return fmt.Errorf("failed to find organization member: %w", err)
+		if !demoted {
+			return nil, ErrLastOwner
+		}
+	} else if err := s.orgRepo.UpdateMemberRole(orgID, targetID, role); err != nil {
+		return nil, fmt.Errorf("failed to update member role: %w", err)
 	}
 
-	if err := s.orgRepo.RemoveMember(orgID, targetID); err != nil {
-		return fmt.Errorf("failed to remove member: %w", err)
+	changes := auditChanges{}
+	changes.set("role", target.Role, role)
+	s.recordMemberEvent(orgID, actorID, targetID, models.AuditActionMemberRoleChange, changes)
+
+	target.Role = role
+	return target, nil
+}
+
+// LeaveOrganization removes the user from the organization along with their
+// task assignments in it. The last owner has to transfer ownership or delete
+// the organization instead.
+func (s *OrganizationService) LeaveOrganization(orgID, userID uint64) error {
+	member, err := s.findMember(orgID, userID)
+	if err != nil {
+		return err
 	}
 
+	if err := s.removeMember(orgID, member); err != nil {
+		return err
+	}
+
+	s.recordMemberEvent(orgID, userID, userID, models.AuditActionMemberLeave, auditChanges{
+		"role": {Before: member.Role},
+	})
+
 	return nil
 }
+
+// TransferOwnership makes another member the owner of the organization. The
+// previous owner stays on as an admin.
+func (s *OrganizationService) TransferOwnership(orgID, actorID, targetID uint64) (*models.OrganizationMember, error) {
+	if targetID == actorID {
+		return nil, ErrCannotTransferToYourself
+	}
+
+	actor, err := s.findMember(orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Default().Allows(policy.Actor{UserID: actorID, Role: actor.Role}, policy.ActionTransferOwnership, policy.Resource{}) {
+		return nil, ErrInsufficientRole
+	}
+
+	target, err := s.findMember(orgID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	transferred, err := s.orgRepo.TransferOwnership(orgID, actorID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer ownership: %w", err)
+	}
+	if !transferred {
+		// The actor was demoted or the target left since they were read
+		if _, err := s.findMember(orgID, targetID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInsufficientRole
+	}
+
+	s.recordMemberEvent(orgID, actorID, targetID, models.AuditActionOrganizationTransferOwnership, auditChanges{
+		"owner_id": {Before: actorID, After: targetID},
+	})
+
+	target.Role = models.RoleOwner
+	return target, nil
+}
+
+// removeMember removes a member from the organization. Owners are only
+// removed while another owner remains, checked in the same transaction.
+func (s *OrganizationService) removeMember(orgID uint64, member *models.OrganizationMember) error {
+	if member.Role != models.RoleOwner {
+		if err := s.orgRepo.RemoveMember(orgID, member.UserID); err != nil {
+			return fmt.Errorf("failed to remove member: %w", err)
+		}
+		return nil
+	}
+
+	removed, err := s.orgRepo.RemoveOwner(orgID, member.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	if !removed {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// GetPolicy returns the organization's effective policy.
+func (s *OrganizationService) GetPolicy(orgID uint64) (policy.Policy, error) {
+	overrides, err := s.orgRepo.ListPolicyRules(orgID)
+	if err != nil {
+		return policy.Policy{}, fmt.Errorf("failed to load organization policy: %w", err)
+	}
+	return policy.Default().WithOverrides(overrides), nil
+}
+
+// UpdatePolicy replaces the organization's policy overrides. Rules that match
+// the default scope are not stored, so the organization keeps following the
+// defaults for them.
+func (s *OrganizationService) UpdatePolicy(orgID uint64, rules []models.OrganizationPolicyRule) (policy.Policy, error) {
+	seen := make(map[string]struct{}, len(rules))
+	overrides := make([]models.OrganizationPolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := policy.Validate(rule); err != nil {
+			return policy.Policy{}, fmt.Errorf("%w: %s for %s: %v", ErrInvalidPolicyRule, rule.Action, rule.Role, err)
+		}
+
+		key := rule.Action + "/" + string(rule.Role)
+		if _, duplicate := seen[key]; duplicate {
+			return policy.Policy{}, fmt.Errorf("%w: %s for %s is listed more than once", ErrInvalidPolicyRule, rule.Action, rule.Role)
+		}
+		seen[key] = struct{}{}
+
+		if policy.Default().Scope(rule.Role, policy.Action(rule.Action)) == policy.Scope(rule.Scope) {
+			continue
+		}
+		overrides = append(overrides, models.OrganizationPolicyRule{
+			Action: rule.Action,
+			Role:   rule.Role,
+			Scope:  rule.Scope,
+		})
+	}
+
+	if err := s.orgRepo.ReplacePolicyRules(orgID, overrides); err != nil {
+		return policy.Policy{}, fmt.Errorf("failed to update organization policy: %w", err)
+	}
+
+	return policy.Default().WithOverrides(overrides), nil
+}
+
+// recordOrganizationEvent appends a change to the organization itself to its audit log.
+func (s *OrganizationService) recordOrganizationEvent(orgID, actorID uint64, action models.AuditAction, changes auditChanges) {
+	s.auditService.record(s.meta, auditEvent{
+		OrganizationID: orgID,
+		ActorID:        actorID,
+		Action:         action,
+		TargetType:     models.AuditTargetOrganization,
+		TargetID:       orgID,
+		Changes:        changes,
+	})
+}
+
+// recordMemberEvent appends a change to a membership to the organization's audit log.
+func (s *OrganizationService) recordMemberEvent(orgID, actorID, targetID uint64, action models.AuditAction, changes auditChanges) {
+	s.auditService.record(s.meta, auditEvent{
+		OrganizationID: orgID,
+		ActorID:        actorID,
+		Action:         action,
+		TargetType:     models.AuditTargetMember,
+		TargetID:       targetID,
+		Changes:        changes,
+	})
+}
+
+// findOrganization looks up an organization, mapping a missing row to ErrOrganizationNotFound.
+func (s *OrganizationService) findOrganization(orgID uint64) (*models.Organization, error) {
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+	return org, nil
+}
+
+// findMember looks up a membership, mapping a missing row to ErrOrganizationMemberNotFound.
+func (s *OrganizationService) findMember(orgID, userID uint64) (*models.OrganizationMember, error) {
+	member, err := s.orgRepo.FindMember(orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to find organization member: %w", err)
+	}
+	return member, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	// Time zones must resolve even on hosts without a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidDefaultTaskStatus = errors.New("default task status must be a state of the organization's workflow")
+	ErrInvalidDefaultDueTime    = errors.New("default due time must be formatted as HH:MM")
+	ErrInvalidTimezone          = errors.New("unknown time zone")
+	ErrInvalidQuota             = errors.New("quotas must be at least 1, or null for no limit")
+	ErrMemberQuotaExceeded      = errors.New("organization has reached its member limit")
+	ErrTaskQuotaExceeded        = errors.New("organization has reached its task limit")
+	ErrAIGenerationDisabled     = errors.New("AI task generation is disabled for this organization")
+	ErrAIQuotaExceeded          = errors.New("organization has used all of today's AI generations")
+)
+
+// dueTimeLayout is the format of OrganizationSettings.DefaultDueTime
+const dueTimeLayout = "15:04"
+
+// GetSettings returns the settings of an organization.
+func (s *OrganizationService) GetSettings(orgID uint64) (*models.OrganizationSettings, error) {
+	return loadOrganizationSettings(s.orgRepo, orgID)
+}
+
+// UpdateSettings replaces the settings of an organization.
+func (s *OrganizationService) UpdateSettings(orgID uint64, settings models.OrganizationSettings) (*models.OrganizationSettings, error) {
+	workflow, err := loadWorkflow(s.orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateOrganizationSettings(settings, workflow); err != nil {
+		return nil, err
+	}
+
+	settings.OrganizationID = orgID
+	if err := s.orgRepo.SaveSettings(&settings); err != nil {
+		return nil, fmt.Errorf("failed to save organization settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func validateOrganizationSettings(settings models.OrganizationSettings, workflow *models.Workflow) error {
+	if _, ok := workflow.State(settings.DefaultTaskStatus); !ok {
+		return ErrInvalidDefaultTaskStatus
+	}
+	if _, err := time.Parse(dueTimeLayout, settings.DefaultDueTime); err != nil {
+		return ErrInvalidDefaultDueTime
+	}
+	if settings.Timezone == "" {
+		return ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+	for _, quota := range []*int{settings.MaxMembers, settings.MaxTasks, settings.AIGenerationsPerDay} {
+		if quota != nil && *quota < 1 {
+			return ErrInvalidQuota
+		}
+	}
+	return nil
+}
+
+// loadOrganizationSettings returns the stored settings of an organization or
+// the defaults if it never changed them.
+func loadOrganizationSettings(orgRepo repository.OrganizationRepository, orgID uint64) (*models.OrganizationSettings, error) {
+	settings, err := orgRepo.FindSettings(orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			defaults := models.DefaultOrganizationSettings(orgID)
+			return &defaults, nil
+		}
+		return nil, fmt.Errorf("failed to load organization settings: %w", err)
+	}
+	return settings, nil
+}
+
+// loadMemberLimit returns the member limit of the organization, or nil if it
+// has none. The limit is enforced by the repository when the member is added.
+func loadMemberLimit(orgRepo repository.OrganizationRepository, orgID uint64) (*int, error) {
+	settings, err := loadOrganizationSettings(orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return settings.MaxMembers, nil
+}
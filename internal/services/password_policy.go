@@ -0,0 +1,52 @@
+package services
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"strings"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+)
+
+var (
+	ErrPasswordTooLong   = errors.New("password too long")
+	ErrPasswordTooCommon = errors.New("password is too common")
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords holds well-known breached passwords, lowercased.
+var commonPasswords = loadCommonPasswords(commonPasswordList)
+
+func loadCommonPasswords(list string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}
+
+// ValidatePassword enforces the password policy: length bounds and
+// rejection of commonly breached passwords or the username itself.
+func ValidatePassword(password, username string) error {
+	if len(password) < constants.MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > constants.MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+
+	lowered := strings.ToLower(password)
+	if _, ok := commonPasswords[lowered]; ok {
+		return ErrPasswordTooCommon
+	}
+	if username != "" && lowered == strings.ToLower(username) {
+		return ErrPasswordTooCommon
+	}
+	return nil
+}
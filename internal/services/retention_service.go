@@ -0,0 +1,43 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/repository"
+)
+
+// RetentionService permanently removes soft-deleted data once it has been in
+// the trash longer than the retention period.
+type RetentionService struct {
+	retentionRepo repository.RetentionRepository
+	retention     time.Duration
+}
+
+// NewRetentionService creates a new RetentionService.
+func NewRetentionService(retentionRepo repository.RetentionRepository, retention time.Duration) *RetentionService {
+	return &RetentionService{
+		retentionRepo: retentionRepo,
+		retention:     retention,
+	}
+}
+
+// Enabled reports whether deleted data is ever purged. A zero retention
+// period keeps it forever.
+func (s *RetentionService) Enabled() bool {
+	return s.retention > 0
+}
+
+// PurgeExpired hard-deletes data that was deleted more than the retention
+// period before now.
+func (s *RetentionService) PurgeExpired(now time.Time) (repository.PurgeResult, error) {
+	if !s.Enabled() {
+		return repository.PurgeResult{}, nil
+	}
+
+	result, err := s.retentionRepo.PurgeDeletedBefore(now.Add(-s.retention))
+	if err != nil {
+		return repository.PurgeResult{}, fmt.Errorf("failed to purge deleted data: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// maxUserAgentLength matches the user_sessions.user_agent column size
+const maxUserAgentLength = 255
+
+// SessionService manages the server-side registry of login sessions.
+type SessionService struct {
+	sessionRepo repository.SessionRepository
+}
+
+// NewSessionService creates a new SessionService.
+func NewSessionService(sessionRepo repository.SessionRepository) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// CreateSessionInput describes the client starting a session.
+type CreateSessionInput struct {
+	UserID    uint64
+	UserAgent string
+	IPAddress string
+}
+
+// CreateSession registers a new login session and returns it along with the
+// token to store in the session cookie.
+func (s *SessionService) CreateSession(input CreateSessionInput) (*models.UserSession, string, error) {
+	token, err := utils.GenerateSecureToken("", constants.SessionTokenByteLength)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	userAgent := input.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:     input.UserID,
+		TokenHash:  utils.HashToken(token),
+		UserAgent:  userAgent,
+		IPAddress:  input.IPAddress,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(constants.SessionMaxAge * time.Second),
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return session, token, nil
+}
+
+// FindActiveSession returns the active session identified by a cookie token.
+func (s *SessionService) FindActiveSession(token string) (*models.UserSession, error) {
+	session, err := s.sessionRepo.FindByHash(utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// ListSessions returns the user's active sessions.
+func (s *SessionService) ListSessions(userID uint64) ([]models.UserSession, error) {
+	sessions, err := s.sessionRepo.ListActiveByUserID(userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the user's sessions.
+func (s *SessionService) RevokeSession(userID, sessionID uint64) error {
+	revoked, err := s.sessionRepo.Revoke(sessionID, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeSessionByToken revokes the session identified by a cookie token, if it is active.
+func (s *SessionService) RevokeSessionByToken(token string) error {
+	session, err := s.FindActiveSession(token)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := s.sessionRepo.Revoke(session.ID, session.UserID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes all of the user's sessions except the current one.
+func (s *SessionService) RevokeOtherSessions(userID, currentSessionID uint64) (int64, error) {
+	count, err := s.sessionRepo.RevokeAllForUser(userID, currentSessionID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return count, nil
+}
+
+// RevokeAllSessions revokes every session of the user, e.g. after a password change.
+func (s *SessionService) RevokeAllSessions(userID uint64) error {
+	if _, err := s.sessionRepo.RevokeAllForUser(userID, 0, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
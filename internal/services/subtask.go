@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidParentTask = errors.New("parent task does not exist in the task's organization")
+	ErrTaskTooDeep       = fmt.Errorf("tasks can be nested at most %d levels deep", constants.TaskMaxDepth)
+	ErrTaskCycle         = errors.New("a task cannot be moved under itself or one of its subtasks")
+	ErrParentTaskDeleted = errors.New("the parent task is in the trash, restore it first")
+)
+
+// ListSubtasks returns the direct subtasks of a task.
+func (s *TaskService) ListSubtasks(taskID uint64, sortRaw string, page, pageSize int) ([]models.Task, int64, error) {
+	sort, err := parseTaskSort(sortRaw)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parent, err := s.taskRepo.FindByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrTaskNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	tasks, total, err := s.taskRepo.List(repository.TaskFilter{
+		OrganizationIDs: []uint64{parent.OrganizationID},
+		ParentID:        &parent.ID,
+		Page:            page,
+		PageSize:        pageSize,
+		Sort:            sort,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list subtasks: %w", err)
+	}
+	return tasks, total, nil
+}
+
+// SubtaskProgress counts the direct subtasks of each task and how many of
+// them are in a done state of the task's organization workflow. Tasks without
+// subtasks are left out of the map.
+func (s *TaskService) SubtaskProgress(tasks []models.Task) (map[uint64]models.SubtaskProgress, error) {
+	progress := make(map[uint64]models.SubtaskProgress)
+	if len(tasks) == 0 {
+		return progress, nil
+	}
+
+	taskIDs := make([]uint64, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	counts, err := s.taskRepo.CountSubtasksByStatus(taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subtasks: %w", err)
+	}
+
+	workflows := workflowCache{}
+	for _, task := range tasks {
+		byStatus, ok := counts[task.ID]
+		if !ok {
+			continue
+		}
+
+		workflow, err := workflows.load(s.orgRepo, task.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		var p models.SubtaskProgress
+		for status, count := range byStatus {
+			p.Total += count
+			if state, ok := workflow.State(status); ok && state.Done {
+				p.Done += count
+			}
+		}
+		progress[task.ID] = p
+	}
+	return progress, nil
+}
+
+// resolveParentTask loads the task a new or moved task goes under and checks
+// that it is active and belongs to the organization.
+func (s *TaskService) resolveParentTask(parentID, orgID uint64) (*models.Task, error) {
+	parent, err := s.taskRepo.FindByID(parentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidParentTask
+		}
+		return nil, fmt.Errorf("failed to find parent task: %w", err)
+	}
+	if parent.OrganizationID != orgID {
+		return nil, ErrInvalidParentTask
+	}
+	return parent, nil
+}
+
+// taskDepth returns the level of a task in its hierarchy, counting a
+// top-level task as 1.
+func (s *TaskService) taskDepth(task *models.Task) (int, error) {
+	depth := 1
+	for parentID := task.ParentID; parentID != nil; depth++ {
+		parent, err := s.taskRepo.FindByID(*parentID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find parent task: %w", err)
+		}
+		parentID = parent.ParentID
+	}
+	return depth, nil
+}
+
+// subtaskLevels returns the active subtasks of a task at any depth along with
+// the number of levels they span below it.
+func (s *TaskService) subtaskLevels(taskID uint64) ([]uint64, int, error) {
+	var subtaskIDs []uint64
+	levels := 0
+	for parentIDs := []uint64{taskID}; ; levels++ {
+		childIDs, err := s.taskRepo.ListSubtaskIDs(parentIDs)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list subtasks: %w", err)
+		}
+		if len(childIDs) == 0 {
+			return subtaskIDs, levels, nil
+		}
+		subtaskIDs = append(subtaskIDs, childIDs...)
+		parentIDs = childIDs
+	}
+}
+
+// checkNewParent verifies that the task can move under the parent without
+// creating a cycle or nesting its subtasks deeper than TaskMaxDepth.
+func (s *TaskService) checkNewParent(task, parent *models.Task) error {
+	if parent.ID == task.ID {
+		return ErrTaskCycle
+	}
+
+	subtaskIDs, levels, err := s.subtaskLevels(task.ID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(subtaskIDs, parent.ID) {
+		return ErrTaskCycle
+	}
+
+	parentDepth, err := s.taskDepth(parent)
+	if err != nil {
+		return err
+	}
+	if parentDepth+1+levels > constants.TaskMaxDepth {
+		return ErrTaskTooDeep
+	}
+	return nil
+}
+
+// ensureParentRestored checks that a deleted subtask's parent is not itself
+// in the trash.
+func (s *TaskService) ensureParentRestored(task *models.Task) error {
+	if task.ParentID == nil {
+		return nil
+	}
+	if _, err := s.taskRepo.FindByID(*task.ParentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrParentTaskDeleted
+		}
+		return fmt.Errorf("failed to find parent task: %w", err)
+	}
+	return nil
+}
+
+// auditParentID formats an optional parent task ID for the audit log.
+func auditParentID(parentID *uint64) interface{} {
+	if parentID == nil {
+		return nil
+	}
+	return *parentID
+}
@@ -4,17 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/yukikurage/task-management-api/internal/constants"
 	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/policy"
 	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
 	"gorm.io/gorm"
 )
 
 var (
 	ErrNotOrganizationMember  = errors.New("user is not a member of the organization")
+	ErrActionNotPermitted     = errors.New("your organization role does not allow this action")
 	ErrTaskNotFound           = errors.New("task not found")
 	ErrNotTaskCreator         = errors.New("only the task creator can perform this action")
 	ErrTaskPermissionDenied   = errors.New("user does not have permission to modify this task")
@@ -25,24 +29,44 @@ var (
 	ErrAIServiceNotConfigured = errors.New("AI service is not configured")
 	ErrAINoTasksGenerated     = errors.New("AI did not generate any tasks")
 	ErrAINoValidTasks         = errors.New("no valid tasks could be created from AI output")
+	ErrInvalidDueOn           = errors.New("due_on must be a date formatted as YYYY-MM-DD")
+	ErrConflictingDueDates    = errors.New("set either due_date or due_on, not both")
+	ErrInvalidTaskPriority    = errors.New("priority must be one of urgent, high, normal or low")
+	ErrInvalidTaskSort        = errors.New("sort must be a comma separated list of priority, due_date, title, created_at or updated_at, each optionally prefixed with -")
 )
 
 // TaskService handles task business logic
 type TaskService struct {
-	taskRepo  repository.TaskRepository
-	orgRepo   repository.OrganizationRepository
-	aiService *AIService
+	taskRepo     repository.TaskRepository
+	orgRepo      repository.OrganizationRepository
+	aiService    *AIService
+	auditService *AuditService
+	meta         RequestMeta
 }
 
 // NewTaskService creates a new TaskService
-func NewTaskService(taskRepo repository.TaskRepository, orgRepo repository.OrganizationRepository, aiService *AIService) *TaskService {
+func NewTaskService(
+	taskRepo repository.TaskRepository,
+	orgRepo repository.OrganizationRepository,
+	aiService *AIService,
+	auditService *AuditService,
+) *TaskService {
 	return &TaskService{
-		taskRepo:  taskRepo,
-		orgRepo:   orgRepo,
-		aiService: aiService,
+		taskRepo:     taskRepo,
+		orgRepo:      orgRepo,
+		aiService:    aiService,
+		auditService: auditService,
 	}
 }
 
+// WithRequestMeta returns a copy of the service that records the request in
+// the audit log entries of its changes
+func (s *TaskService) WithRequestMeta(meta RequestMeta) *TaskService {
+	copied := *s
+	copied.meta = meta
+	return &copied
+}
+
 // ListTasksInput represents filters for listing tasks
 type ListTasksInput struct {
 	UserID         uint64
@@ -50,9 +74,14 @@ type ListTasksInput struct {
 	AssignedToMe   bool
 	DueToday       bool
 	Status         *models.TaskStatus
-	SortByDueDate  bool
-	Page           int
-	PageSize       int
+	TopLevelOnly   bool
+	// LabelIDs keeps tasks with any of the labels, or all of them with MatchAllLabels
+	LabelIDs       []uint64
+	MatchAllLabels bool
+	// Sort is a comma separated list of sort keys such as "priority,-due_date"
+	Sort     string
+	Page     int
+	PageSize int
 }
 
 // CreateTaskInput represents input for creating a task
@@ -60,8 +89,11 @@ type CreateTaskInput struct {
 	Title          string
 	Description    string
 	Status         models.TaskStatus
+	Priority       models.TaskPriority
 	DueDate        *time.Time
+	DueOn          string // YYYY-MM-DD, due at the organization's default due time
 	OrganizationID uint64
+	ParentID       *uint64
 	CreatorID      uint64
 }
 
@@ -71,8 +103,11 @@ type UpdateTaskInput struct {
 	Title        *string
 	Description  *string
 	Status       *models.TaskStatus
+	Priority     *models.TaskPriority
 	DueDate      *time.Time
 	ClearDueDate bool
+	ParentID     *uint64
+	ClearParent  bool
 }
 
 // AssignUsersInput represents input for assigning users to a task
@@ -84,6 +119,11 @@ type AssignUsersInput struct {
 
 // ListTasks returns tasks accessible to a user based on the provided filters
 func (s *TaskService) ListTasks(input ListTasksInput) ([]models.Task, int64, error) {
+	sort, err := parseTaskSort(input.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	orgIDs, err := s.resolveAccessibleOrganizationIDs(input.UserID, input.OrganizationID)
 	if err != nil {
 		return nil, 0, err
@@ -97,7 +137,10 @@ func (s *TaskService) ListTasks(input ListTasksInput) ([]models.Task, int64, err
 		OrganizationIDs: orgIDs,
 		Page:            input.Page,
 		PageSize:        input.PageSize,
-		SortByDueDate:   input.SortByDueDate,
+		Sort:            sort,
+		TopLevelOnly:    input.TopLevelOnly,
+		LabelIDs:        uniqueUint64(input.LabelIDs),
+		MatchAllLabels:  input.MatchAllLabels,
 	}
 
 	if input.Status != nil {
@@ -124,7 +167,7 @@ func (s *TaskService) ListTasks(input ListTasksInput) ([]models.Task, int64, err
 
 // GetTask returns a task with related data
 func (s *TaskService) GetTask(taskID uint64) (*models.Task, error) {
-	task, err := s.taskRepo.FindByID(taskID, "Creator", "Organization", "Assignments", "Assignments.User")
+	task, err := s.taskRepo.FindByID(taskID, "Creator", "Organization", "Assignments", "Assignments.User", "Labels.Label")
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTaskNotFound
@@ -141,24 +184,73 @@ func (s *TaskService) CreateTask(input CreateTaskInput) (*models.Task, error) {
 		return nil, ErrTitleRequired
 	}
 
-	if err := s.ensureOrganizationMember(input.OrganizationID, input.CreatorID); err != nil {
+	if input.DueDate != nil && input.DueOn != "" {
+		return nil, ErrConflictingDueDates
+	}
+
+	if input.Priority == "" {
+		input.Priority = models.TaskPriorityNormal
+	} else if !input.Priority.IsValid() {
+		return nil, ErrInvalidTaskPriority
+	}
+
+	if err := s.authorize(policy.ActionTaskCreate, input.OrganizationID, input.CreatorID, policy.Resource{}); err != nil {
 		return nil, err
 	}
 
+	settings, err := loadOrganizationSettings(s.orgRepo, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.resolveParentTask(*input.ParentID, input.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		depth, err := s.taskDepth(parent)
+		if err != nil {
+			return nil, err
+		}
+		if depth+1 > constants.TaskMaxDepth {
+			return nil, ErrTaskTooDeep
+		}
+	}
+
 	if input.Status == "" {
-		input.Status = models.TaskStatusTodo
+		input.Status = settings.DefaultTaskStatus
+	} else {
+		workflow, err := loadWorkflow(s.orgRepo, input.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := workflow.State(input.Status); !ok {
+			return nil, ErrInvalidTaskStatus
+		}
+	}
+
+	dueDate := input.DueDate
+	if input.DueOn != "" {
+		if dueDate, err = resolveDueOn(input.DueOn, settings); err != nil {
+			return nil, err
+		}
 	}
 
 	task := &models.Task{
 		Title:          input.Title,
 		Description:    input.Description,
 		Status:         input.Status,
-		DueDate:        input.DueDate,
+		Priority:       input.Priority,
+		DueDate:        dueDate,
 		OrganizationID: input.OrganizationID,
+		ParentID:       input.ParentID,
 		CreatorID:      input.CreatorID,
 	}
 
-	if err := s.taskRepo.Create(task); err != nil {
+	if err := s.taskRepo.Create(task, settings.MaxTasks); err != nil {
+		if errors.Is(err, repository.ErrTaskLimitReached) {
+			return nil, ErrTaskQuotaExceeded
+		}
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
@@ -166,22 +258,25 @@ func (s *TaskService) CreateTask(input CreateTaskInput) (*models.Task, error) {
 		return nil, fmt.Errorf("failed to assign creator to task: %w", err)
 	}
 
-	return s.taskRepo.FindByID(task.ID, "Creator", "Organization", "Assignments", "Assignments.User")
+	changes := auditChanges{}
+	changes.set("title", nil, task.Title)
+	changes.set("description", nil, task.Description)
+	changes.set("status", nil, task.Status)
+	changes.set("priority", nil, task.Priority)
+	changes.set("due_date", nil, auditTime(task.DueDate))
+	changes.set("parent_id", nil, auditParentID(task.ParentID))
+	s.recordTaskEvent(task, input.CreatorID, models.AuditActionTaskCreate, changes)
+
+	return s.taskRepo.FindByID(task.ID, "Creator", "Organization", "Assignments", "Assignments.User", "Labels.Label")
 }
 
 // UpdateTask updates an existing task
 func (s *TaskService) UpdateTask(taskID uint64, input UpdateTaskInput) (*models.Task, error) {
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskUpdate, input.ActorID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTaskNotFound
-		}
-		return nil, fmt.Errorf("failed to find task: %w", err)
-	}
-
-	if task.CreatorID != input.ActorID {
-		return nil, ErrNotTaskCreator
+		return nil, err
 	}
+	before := *task
 
 	if input.Title != nil {
 		if *input.Title == "" {
@@ -192,40 +287,75 @@ func (s *TaskService) UpdateTask(taskID uint64, input UpdateTaskInput) (*models.
 	if input.Description != nil {
 		task.Description = *input.Description
 	}
-	if input.Status != nil {
+	if input.Status != nil && *input.Status != task.Status {
+		workflow, err := loadWorkflow(s.orgRepo, task.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTransition(workflow, task.Status, *input.Status); err != nil {
+			return nil, err
+		}
+		if err := s.ensureUnblocked(task, workflow, *input.Status); err != nil {
+			return nil, err
+		}
 		task.Status = *input.Status
 	}
+	if input.Priority != nil {
+		if !input.Priority.IsValid() {
+			return nil, ErrInvalidTaskPriority
+		}
+		task.Priority = *input.Priority
+	}
 	if input.ClearDueDate {
 		task.DueDate = nil
 	} else if input.DueDate != nil {
 		task.DueDate = input.DueDate
 	}
+	if input.ClearParent {
+		task.ParentID = nil
+	} else if input.ParentID != nil && (task.ParentID == nil || *task.ParentID != *input.ParentID) {
+		parent, err := s.resolveParentTask(*input.ParentID, task.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkNewParent(task, parent); err != nil {
+			return nil, err
+		}
+		task.ParentID = &parent.ID
+	}
 
 	if err := s.taskRepo.Update(task); err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
-	return s.taskRepo.FindByID(task.ID, "Creator", "Organization", "Assignments", "Assignments.User")
+	changes := auditChanges{}
+	changes.set("title", before.Title, task.Title)
+	changes.set("description", before.Description, task.Description)
+	changes.set("status", before.Status, task.Status)
+	changes.set("priority", before.Priority, task.Priority)
+	changes.set("due_date", auditTime(before.DueDate), auditTime(task.DueDate))
+	changes.set("parent_id", auditParentID(before.ParentID), auditParentID(task.ParentID))
+	s.recordTaskEvent(task, input.ActorID, models.AuditActionTaskUpdate, changes)
+
+	return s.taskRepo.FindByID(task.ID, "Creator", "Organization", "Assignments", "Assignments.User", "Labels.Label")
 }
 
-// DeleteTask deletes a task if the actor is the creator
+// DeleteTask moves a task and its subtasks to the trash if the actor may
+// delete the task
 func (s *TaskService) DeleteTask(taskID, actorID uint64) error {
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskDelete, actorID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTaskNotFound
-		}
-		return fmt.Errorf("failed to find task: %w", err)
-	}
-
-	if task.CreatorID != actorID {
-		return ErrNotTaskCreator
+		return err
 	}
 
 	if err := s.taskRepo.Delete(taskID); err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 
+	changes := auditChanges{}
+	changes.set("title", task.Title, nil)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskDelete, changes)
+
 	return nil
 }
 
@@ -235,16 +365,9 @@ func (s *TaskService) AssignUsers(input AssignUsersInput) error {
 		return ErrNoUserIDsProvided
 	}
 
-	task, err := s.taskRepo.FindByID(input.TaskID)
+	task, err := s.findTaskForAction(input.TaskID, policy.ActionTaskAssign, input.ActorID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTaskNotFound
-		}
-		return fmt.Errorf("failed to find task: %w", err)
-	}
-
-	if task.CreatorID != input.ActorID {
-		return ErrNotTaskCreator
+		return err
 	}
 
 	userIDs := uniqueUint64(input.UserIDs)
@@ -261,6 +384,11 @@ func (s *TaskService) AssignUsers(input AssignUsersInput) error {
 		return fmt.Errorf("failed to assign users: %w", err)
 	}
 
+	before := assigneeIDs(task)
+	s.recordTaskEvent(task, input.ActorID, models.AuditActionTaskAssign, auditChanges{
+		"assignee_ids": {Before: before, After: uniqueUint64(append(before, userIDs...))},
+	})
+
 	return nil
 }
 
@@ -270,16 +398,9 @@ func (s *TaskService) UnassignUsers(taskID, actorID uint64, userIDs []uint64) er
 		return ErrNoUserIDsProvided
 	}
 
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskAssign, actorID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTaskNotFound
-		}
-		return fmt.Errorf("failed to find task: %w", err)
-	}
-
-	if task.CreatorID != actorID {
-		return ErrNotTaskCreator
+		return err
 	}
 
 	uniqueIDs := uniqueUint64(userIDs)
@@ -288,59 +409,265 @@ func (s *TaskService) UnassignUsers(taskID, actorID uint64, userIDs []uint64) er
 		return fmt.Errorf("failed to unassign users: %w", err)
 	}
 
+	removed := make(map[uint64]struct{}, len(uniqueIDs))
+	for _, id := range uniqueIDs {
+		removed[id] = struct{}{}
+	}
+	before := assigneeIDs(task)
+	after := make([]uint64, 0, len(before))
+	for _, id := range before {
+		if _, ok := removed[id]; !ok {
+			after = append(after, id)
+		}
+	}
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskUnassign, auditChanges{
+		"assignee_ids": {Before: before, After: after},
+	})
+
 	return nil
 }
 
-// ToggleTaskStatus toggles a task between todo and done
-func (s *TaskService) ToggleTaskStatus(taskID, actorID uint64) (*models.Task, error) {
-	task, err := s.taskRepo.FindByID(taskID, "Assignments")
+// AddLabels attaches labels of the task's organization to a task
+func (s *TaskService) AddLabels(taskID, actorID uint64, labelIDs []uint64) error {
+	if len(labelIDs) == 0 {
+		return ErrNoLabelIDsProvided
+	}
+
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskUpdate, actorID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTaskNotFound
-		}
-		return nil, fmt.Errorf("failed to find task: %w", err)
+		return err
 	}
 
-	if task.CreatorID != actorID {
-		// Ensure the actor is assigned to the task
-		permitted := false
-		for _, assignment := range task.Assignments {
-			if assignment.UserID == actorID {
-				permitted = true
-				break
-			}
-		}
-		if !permitted {
-			return nil, ErrTaskPermissionDenied
+	uniqueIDs := uniqueUint64(labelIDs)
+
+	count, err := s.taskRepo.CountLabelsByIDs(uniqueIDs, task.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("failed to verify labels: %w", err)
+	}
+	if int(count) != len(uniqueIDs) {
+		return ErrInvalidTaskLabel
+	}
+
+	if err := s.taskRepo.AddLabels(task.ID, uniqueIDs); err != nil {
+		return fmt.Errorf("failed to add labels: %w", err)
+	}
+
+	before := attachedLabelIDs(task)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskLabel, auditChanges{
+		"label_ids": {Before: before, After: uniqueUint64(append(before, uniqueIDs...))},
+	})
+
+	return nil
+}
+
+// RemoveLabels detaches labels from a task
+func (s *TaskService) RemoveLabels(taskID, actorID uint64, labelIDs []uint64) error {
+	if len(labelIDs) == 0 {
+		return ErrNoLabelIDsProvided
+	}
+
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskUpdate, actorID)
+	if err != nil {
+		return err
+	}
+
+	uniqueIDs := uniqueUint64(labelIDs)
+
+	if err := s.taskRepo.RemoveLabels(task.ID, uniqueIDs); err != nil {
+		return fmt.Errorf("failed to remove labels: %w", err)
+	}
+
+	removed := make(map[uint64]struct{}, len(uniqueIDs))
+	for _, id := range uniqueIDs {
+		removed[id] = struct{}{}
+	}
+	before := attachedLabelIDs(task)
+	after := make([]uint64, 0, len(before))
+	for _, id := range before {
+		if _, ok := removed[id]; !ok {
+			after = append(after, id)
 		}
 	}
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskUnlabel, auditChanges{
+		"label_ids": {Before: before, After: after},
+	})
 
-	if task.Status == models.TaskStatusDone {
-		task.Status = models.TaskStatusTodo
-	} else {
-		task.Status = models.TaskStatusDone
+	return nil
+}
+
+// ToggleTaskStatus moves a task to the other state of a two-state workflow,
+// like between TODO and DONE in the default one
+func (s *TaskService) ToggleTaskStatus(taskID, actorID uint64) (*models.Task, error) {
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskToggleStatus, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := loadWorkflow(s.orgRepo, task.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(workflow.States) != 2 {
+		return nil, ErrToggleRequiresTwoStates
+	}
+
+	previous := task.Status
+	next := workflow.States[0].Key
+	if previous == next {
+		next = workflow.States[1].Key
+	}
+	if err := checkTransition(workflow, previous, next); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUnblocked(task, workflow, next); err != nil {
+		return nil, err
 	}
+	task.Status = next
 
 	if err := s.taskRepo.Update(task); err != nil {
 		return nil, fmt.Errorf("failed to toggle status: %w", err)
 	}
 
+	changes := auditChanges{}
+	changes.set("status", previous, task.Status)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskToggleStatus, changes)
+
 	return task, nil
 }
 
+// TransitionTask moves a task to another state of its organization's
+// workflow. Status changes need the same permission as toggling.
+func (s *TaskService) TransitionTask(taskID, actorID uint64, status models.TaskStatus) (*models.Task, error) {
+	task, err := s.findTaskForAction(taskID, policy.ActionTaskToggleStatus, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := loadWorkflow(s.orgRepo, task.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTransition(workflow, task.Status, status); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUnblocked(task, workflow, status); err != nil {
+		return nil, err
+	}
+
+	previous := task.Status
+	task.Status = status
+	if err := s.taskRepo.Update(task); err != nil {
+		return nil, fmt.Errorf("failed to change status: %w", err)
+	}
+
+	changes := auditChanges{}
+	changes.set("status", previous, task.Status)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskTransition, changes)
+
+	return s.taskRepo.FindByID(task.ID, "Creator", "Organization", "Assignments", "Assignments.User", "Labels.Label")
+}
+
+// ListTrash returns the organization's deleted tasks that have not been purged yet
+func (s *TaskService) ListTrash(orgID uint64, params utils.PaginationParams) ([]models.Task, int64, error) {
+	tasks, total, err := s.taskRepo.ListDeleted(orgID, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list deleted tasks: %w", err)
+	}
+	return tasks, total, nil
+}
+
+// RestoreTask brings a deleted task of the organization back along with the
+// subtasks deleted with it. Restoring requires the same permission as
+// deleting the task, and a subtask can only come back once its parent has.
+func (s *TaskService) RestoreTask(orgID, taskID, actorID uint64) (*models.Task, error) {
+	task, err := s.taskRepo.FindDeletedByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to find deleted task: %w", err)
+	}
+	if task.OrganizationID != orgID {
+		return nil, ErrTaskNotFound
+	}
+
+	if err := s.authorize(policy.ActionTaskDelete, orgID, actorID, policy.TaskResource(*task)); err != nil {
+		return nil, err
+	}
+	if err := s.ensureParentRestored(task); err != nil {
+		return nil, err
+	}
+
+	settings, err := loadOrganizationSettings(s.orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.Restore(task, settings.MaxTasks); err != nil {
+		if errors.Is(err, repository.ErrTaskLimitReached) {
+			return nil, ErrTaskQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	changes := auditChanges{}
+	changes.set("title", nil, task.Title)
+	s.recordTaskEvent(task, actorID, models.AuditActionTaskRestore, changes)
+
+	return s.taskRepo.FindByID(task.ID, "Creator", "Organization", "Assignments", "Assignments.User", "Labels.Label")
+}
+
 // GenerateTasksInput represents input for AI task generation
 type GenerateTasksInput struct {
-	Text      string
-	CreatorID uint64
+	Text           string
+	OrganizationID uint64
+	CreatorID      uint64
 }
 
-// GenerateTasks uses AI to generate tasks from text
+// GenerateTasks uses AI to generate tasks from text for an organization the
+// creator may create tasks in. Each call counts against the organization's
+// daily AI generation quota.
 func (s *TaskService) GenerateTasks(ctx context.Context, input GenerateTasksInput) ([]GeneratedTask, error) {
 	if s.aiService == nil {
 		return nil, ErrAIServiceNotConfigured
 	}
 
-	aiTasks, err := s.aiService.GenerateTasksFromText(ctx, input.Text)
+	if err := s.authorize(policy.ActionTaskCreate, input.OrganizationID, input.CreatorID, policy.Resource{}); err != nil {
+		return nil, err
+	}
+
+	settings, err := loadOrganizationSettings(s.orgRepo, input.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.AIGenerationEnabled {
+		return nil, ErrAIGenerationDisabled
+	}
+
+	day := time.Now().In(settings.Location()).Format(time.DateOnly)
+	consumed, err := s.orgRepo.ConsumeAIGeneration(input.OrganizationID, day, settings.AIGenerationsPerDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record AI generation: %w", err)
+	}
+	if !consumed {
+		return nil, ErrAIQuotaExceeded
+	}
+
+	// Only generations that produce usable tasks count towards the quota
+	tasks, err := s.generateValidTasks(ctx, input.Text)
+	if err != nil {
+		if releaseErr := s.orgRepo.ReleaseAIGeneration(input.OrganizationID, day); releaseErr != nil {
+			log.Printf("Failed to release AI generation of organization %d: %v", input.OrganizationID, releaseErr)
+		}
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// generateValidTasks asks the AI provider for tasks and keeps the ones with a
+// title, dropping due dates that are already in the past.
+func (s *TaskService) generateValidTasks(ctx context.Context, text string) ([]GeneratedTask, error) {
+	aiTasks, err := s.aiService.GenerateTasksFromText(ctx, text)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tasks: %w", err)
 	}
@@ -375,6 +702,48 @@ func (s *TaskService) GenerateTasks(ctx context.Context, input GenerateTasksInpu
 	return validTasks, nil
 }
 
+// parseTaskSort parses a comma separated list of sort keys, each optionally
+// prefixed with "-" for descending order. Keys may appear only once.
+func parseTaskSort(raw string) ([]repository.TaskSort, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	keys := strings.Split(raw, ",")
+	sort := make([]repository.TaskSort, 0, len(keys))
+	seen := make(map[repository.TaskSortField]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		desc := strings.HasPrefix(key, "-")
+		field := repository.TaskSortField(strings.TrimPrefix(key, "-"))
+
+		switch field {
+		case repository.TaskSortPriority, repository.TaskSortDueDate, repository.TaskSortTitle,
+			repository.TaskSortCreatedAt, repository.TaskSortUpdatedAt:
+		default:
+			return nil, ErrInvalidTaskSort
+		}
+		if seen[field] {
+			return nil, ErrInvalidTaskSort
+		}
+		seen[field] = true
+
+		sort = append(sort, repository.TaskSort{Field: field, Desc: desc})
+	}
+
+	return sort, nil
+}
+
+// resolveDueOn turns a YYYY-MM-DD date into the organization's default due
+// time on that day in its time zone.
+func resolveDueOn(dueOn string, settings *models.OrganizationSettings) (*time.Time, error) {
+	dueDate, err := time.ParseInLocation(time.DateOnly+" "+dueTimeLayout, dueOn+" "+settings.DefaultDueTime, settings.Location())
+	if err != nil {
+		return nil, ErrInvalidDueOn
+	}
+	return &dueDate, nil
+}
+
 // resolveAccessibleOrganizationIDs returns the organization IDs the user can access
 func (s *TaskService) resolveAccessibleOrganizationIDs(userID uint64, organizationID *uint64) ([]uint64, error) {
 	if organizationID != nil {
@@ -409,6 +778,98 @@ func (s *TaskService) ensureOrganizationMember(orgID, userID uint64) error {
 	return nil
 }
 
+// findTaskForAction loads a task with its assignments and verifies that the
+// actor may perform the action on it
+func (s *TaskService) findTaskForAction(taskID uint64, action policy.Action, actorID uint64) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(taskID, "Assignments", "Labels")
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	if err := s.authorize(action, task.OrganizationID, actorID, policy.TaskResource(*task)); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// authorize checks that the organization accepts changes and that its policy
+// allows the action for the actor's role
+func (s *TaskService) authorize(action policy.Action, orgID, actorID uint64, resource policy.Resource) error {
+	member, err := s.orgRepo.FindMember(orgID, actorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotOrganizationMember
+		}
+		return fmt.Errorf("failed to verify organization membership: %w", err)
+	}
+
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return fmt.Errorf("failed to find organization: %w", err)
+	}
+	if org.IsArchived() {
+		return ErrOrganizationArchived
+	}
+
+	overrides, err := s.orgRepo.ListPolicyRules(orgID)
+	if err != nil {
+		return fmt.Errorf("failed to load organization policy: %w", err)
+	}
+
+	// Authorship only counts for tasks created during the current membership,
+	// so a removed member who rejoins does not regain control of old tasks
+	if resource.CreatorID == actorID && resource.CreatedAt.Before(member.JoinedAt) {
+		resource.CreatorID = 0
+	}
+
+	scope := policy.Default().WithOverrides(overrides).Scope(member.Role, action)
+	if scope.Covers(actorID, resource) {
+		return nil
+	}
+
+	switch scope {
+	case policy.ScopeOwn:
+		return ErrNotTaskCreator
+	case policy.ScopeAssigned:
+		return ErrTaskPermissionDenied
+	default:
+		return ErrActionNotPermitted
+	}
+}
+
+// recordTaskEvent appends a change to a task to its organization's audit log
+func (s *TaskService) recordTaskEvent(task *models.Task, actorID uint64, action models.AuditAction, changes auditChanges) {
+	s.auditService.record(s.meta, auditEvent{
+		OrganizationID: task.OrganizationID,
+		ActorID:        actorID,
+		Action:         action,
+		TargetType:     models.AuditTargetTask,
+		TargetID:       task.ID,
+		Changes:        changes,
+	})
+}
+
+// attachedLabelIDs returns the IDs of the labels attached to a task with loaded labels
+func attachedLabelIDs(task *models.Task) []uint64 {
+	ids := make([]uint64, len(task.Labels))
+	for i, taskLabel := range task.Labels {
+		ids[i] = taskLabel.LabelID
+	}
+	return ids
+}
+
+// assigneeIDs returns the IDs of the users assigned to a task with loaded assignments
+func assigneeIDs(task *models.Task) []uint64 {
+	ids := make([]uint64, len(task.Assignments))
+	for i, assignment := range task.Assignments {
+		ids[i] = assignment.UserID
+	}
+	return ids
+}
+
 // uniqueUint64 removes duplicate values from a slice of uint64
 func uniqueUint64(values []uint64) []uint64 {
 	seen := make(map[uint64]struct{}, len(values))
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"github.com/yukikurage/task-management-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor enrollment has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+// TwoFactorService manages TOTP enrollment, recovery codes and second-factor checks.
+type TwoFactorService struct {
+	twoFactorRepo repository.TwoFactorRepository
+	userRepo      repository.UserRepository
+}
+
+// NewTwoFactorService creates a new TwoFactorService.
+func NewTwoFactorService(twoFactorRepo repository.TwoFactorRepository, userRepo repository.UserRepository) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// TwoFactorStatus describes a user's two-factor configuration.
+type TwoFactorStatus struct {
+	Enabled                bool
+	EnabledAt              *time.Time
+	RecoveryCodesRemaining int64
+}
+
+// TwoFactorEnrollment holds the secret to load into an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// Status reports whether two-factor authentication is enabled for a user.
+func (s *TwoFactorService) Status(userID uint64) (*TwoFactorStatus, error) {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &TwoFactorStatus{}, nil
+		}
+		return nil, fmt.Errorf("failed to find two-factor settings: %w", err)
+	}
+	if !twoFactor.IsEnabled() {
+		return &TwoFactorStatus{}, nil
+	}
+
+	remaining, err := s.twoFactorRepo.CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return &TwoFactorStatus{
+		Enabled:                true,
+		EnabledAt:              twoFactor.EnabledAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// IsEnabled reports whether a user must provide a second factor at login.
+func (s *TwoFactorService) IsEnabled(userID uint64) (bool, error) {
+	status, err := s.Status(userID)
+	if err != nil {
+		return false, err
+	}
+	return status.Enabled, nil
+}
+
+// Enroll generates a new TOTP secret for the user. It only takes effect once
+// Activate has been called with a valid code.
+func (s *TwoFactorService) Enroll(userID uint64) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	enabled, err := s.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.SavePending(&models.UserTwoFactor{
+		UserID: userID,
+		Secret: secret,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor secret: %w", err)
+	}
+
+	return &TwoFactorEnrollment{
+		Secret: secret,
+		URI:    utils.TOTPURI(constants.TOTPIssuer, user.Username, secret),
+	}, nil
+}
+
+// Activate verifies the first code from the authenticator app, enables
+// two-factor authentication and returns a fresh set of recovery codes.
+func (s *TwoFactorService) Activate(userID uint64, code string) ([]string, error) {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to find two-factor settings: %w", err)
+	}
+	if twoFactor.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	now := time.Now()
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, strings.TrimSpace(code), now)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.Enable(userID, now, step, hashes); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Verify checks a TOTP code or an unused recovery code for a user with
+// two-factor authentication enabled. Each code is accepted only once.
+func (s *TwoFactorService) Verify(userID uint64, code string) error {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		return fmt.Errorf("failed to find two-factor settings: %w", err)
+	}
+	if !twoFactor.IsEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	now := time.Now()
+
+	if step, ok := utils.ValidateTOTP(twoFactor.Secret, code, now); ok {
+		consumed, err := s.twoFactorRepo.ConsumeStep(userID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record two-factor code: %w", err)
+		}
+		if !consumed {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(userID, utils.HashToken(utils.NormalizeRecoveryCode(code)), now)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a code.
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint64, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Disable turns off two-factor authentication after verifying a code.
+func (s *TwoFactorService) Disable(userID uint64, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Delete(userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// generateRecoveryCodes returns plaintext recovery codes and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, constants.RecoveryCodeCount)
+	hashes := make([]string, constants.RecoveryCodeCount)
+	for i := range codes {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code
+		hashes[i] = utils.HashToken(utils.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yukikurage/task-management-api/internal/constants"
+	"github.com/yukikurage/task-management-api/internal/models"
+	"github.com/yukikurage/task-management-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidWorkflow         = errors.New("invalid workflow")
+	ErrWorkflowStateInUse      = errors.New("cannot remove a workflow state that tasks still use")
+	ErrInvalidTaskStatus       = errors.New("status is not a state of the organization's workflow")
+	ErrTransitionNotAllowed    = errors.New("the organization's workflow does not allow this status change")
+	ErrToggleRequiresTwoStates = errors.New("toggling only works in two-state workflows, use a transition instead")
+)
+
+// GetWorkflow returns the task workflow of an organization.
+func (s *OrganizationService) GetWorkflow(orgID uint64) (*models.Workflow, error) {
+	return loadWorkflow(s.orgRepo, orgID)
+}
+
+// UpdateWorkflow replaces the task workflow of an organization. States still
+// used by a task, including tasks in the trash, cannot be removed. Removing
+// the default task status makes the first state the new default.
+func (s *OrganizationService) UpdateWorkflow(orgID uint64, workflow models.Workflow) (*models.Workflow, error) {
+	if err := validateWorkflow(workflow); err != nil {
+		return nil, err
+	}
+
+	stranded, err := s.orgRepo.ReplaceWorkflow(orgID, workflow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workflow: %w", err)
+	}
+	if stranded > 0 {
+		return nil, fmt.Errorf("%w: %d tasks are in states the new workflow drops", ErrWorkflowStateInUse, stranded)
+	}
+
+	// New tasks start in the first state once the default status is gone
+	settings, err := loadOrganizationSettings(s.orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := workflow.State(settings.DefaultTaskStatus); !ok {
+		settings.DefaultTaskStatus = workflow.States[0].Key
+		if err := s.orgRepo.SaveSettings(settings); err != nil {
+			return nil, fmt.Errorf("failed to save organization settings: %w", err)
+		}
+	}
+	return loadWorkflow(s.orgRepo, orgID)
+}
+
+// validateWorkflow checks that a workflow has uniquely keyed, named states,
+// both open and done ones, and transitions between distinct known states.
+func validateWorkflow(workflow models.Workflow) error {
+	if len(workflow.States) < 2 || len(workflow.States) > constants.WorkflowMaxStates {
+		return fmt.Errorf("%w: a workflow needs between 2 and %d states", ErrInvalidWorkflow, constants.WorkflowMaxStates)
+	}
+
+	seen := make(map[models.TaskStatus]struct{}, len(workflow.States))
+	var open, done bool
+	for _, state := range workflow.States {
+		if !state.Key.IsValid() {
+			return fmt.Errorf("%w: state key %q must be 1-20 upper case letters, digits or underscores starting with a letter", ErrInvalidWorkflow, state.Key)
+		}
+		if _, duplicate := seen[state.Key]; duplicate {
+			return fmt.Errorf("%w: state %s is listed more than once", ErrInvalidWorkflow, state.Key)
+		}
+		seen[state.Key] = struct{}{}
+
+		name := strings.TrimSpace(state.Name)
+		if name == "" || utf8.RuneCountInString(name) > constants.WorkflowStateNameMaxLength {
+			return fmt.Errorf("%w: state %s needs a name of at most %d characters", ErrInvalidWorkflow, state.Key, constants.WorkflowStateNameMaxLength)
+		}
+
+		if state.Done {
+			done = true
+		} else {
+			open = true
+		}
+	}
+	if !open || !done {
+		return fmt.Errorf("%w: a workflow needs at least one open and one done state", ErrInvalidWorkflow)
+	}
+
+	type move struct{ from, to models.TaskStatus }
+	moves := make(map[move]struct{}, len(workflow.Transitions))
+	for _, transition := range workflow.Transitions {
+		_, fromKnown := seen[transition.FromStatus]
+		_, toKnown := seen[transition.ToStatus]
+		if !fromKnown || !toKnown {
+			return fmt.Errorf("%w: transition %s -> %s uses an unknown state", ErrInvalidWorkflow, transition.FromStatus, transition.ToStatus)
+		}
+		if transition.FromStatus == transition.ToStatus {
+			return fmt.Errorf("%w: transition %s -> %s does not change the state", ErrInvalidWorkflow, transition.FromStatus, transition.ToStatus)
+		}
+		key := move{transition.FromStatus, transition.ToStatus}
+		if _, duplicate := moves[key]; duplicate {
+			return fmt.Errorf("%w: transition %s -> %s is listed more than once", ErrInvalidWorkflow, transition.FromStatus, transition.ToStatus)
+		}
+		moves[key] = struct{}{}
+	}
+	return nil
+}
+
+// loadWorkflow returns the stored workflow of an organization or the default
+// TODO/DONE workflow if it never defined one.
+func loadWorkflow(orgRepo repository.OrganizationRepository, orgID uint64) (*models.Workflow, error) {
+	workflow, err := orgRepo.FindWorkflow(orgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			defaults := models.DefaultWorkflow()
+			return &defaults, nil
+		}
+		return nil, fmt.Errorf("failed to load workflow: %w", err)
+	}
+	return workflow, nil
+}
+
+// workflowCache loads the workflow of each organization at most once
+type workflowCache map[uint64]*models.Workflow
+
+func (c workflowCache) load(orgRepo repository.OrganizationRepository, orgID uint64) (*models.Workflow, error) {
+	if workflow, ok := c[orgID]; ok {
+		return workflow, nil
+	}
+	workflow, err := loadWorkflow(orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+	c[orgID] = workflow
+	return workflow, nil
+}
+
+// checkTransition verifies that the workflow lets a task move to the status.
+func checkTransition(workflow *models.Workflow, from, to models.TaskStatus) error {
+	if _, ok := workflow.State(to); !ok {
+		return ErrInvalidTaskStatus
+	}
+	if !workflow.Allows(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrTransitionNotAllowed, from, to)
+	}
+	return nil
+}
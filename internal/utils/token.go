@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateSecureToken returns a URL-safe random token with the given prefix
+// and byteLength bytes of entropy.
+func GenerateSecureToken(prefix string, byteLength int) (string, error) {
+	bytes := make([]byte, byteLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	return prefix + base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a token for storage and lookup.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}